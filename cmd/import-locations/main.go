@@ -0,0 +1,66 @@
+// Comando import-locations grava a lista completa de cidades do CPTEC na
+// tabela locations, para que a busca de cidades funcione a partir do
+// catálogo local.
+//
+// Uso:
+//
+//	go run ./cmd/import-locations -file cidades.xml
+//	go run ./cmd/import-locations -file cidades.csv -format csv
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"os"
+	"weather-notification/internal/domain/service"
+	"weather-notification/internal/infrastructure/adapter/cptec"
+	postgres "weather-notification/internal/infrastructure/adapter/persistence/postgres"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	file := flag.String("file", "", "arquivo XML ou CSV (id,nome,uf) com a lista de cidades do CPTEC")
+	format := flag.String("format", "", "formato do arquivo: xml ou csv (padrão: extensão do arquivo)")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format == "" {
+		*format = cptec.CatalogFormat(*file)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Arquivo .env não encontrado, usando variáveis do sistema")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Erro ao abrir arquivo: %v", err)
+	}
+	defer f.Close()
+
+	locations, err := cptec.ReadCityCatalog(f, *format)
+	if err != nil {
+		log.Fatalf("Erro ao ler catálogo de cidades: %v", err)
+	}
+
+	db, err := sql.Open("postgres", os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatalf("Erro ao conectar ao banco: %v", err)
+	}
+	defer db.Close()
+
+	catalogService := service.NewLocationCatalogService(postgres.NewLocationRepository(db))
+	imported, err := catalogService.Import(context.Background(), locations)
+	if err != nil {
+		log.Fatalf("Erro ao importar cidades: %v", err)
+	}
+
+	log.Printf("%d cidades importadas de %s", imported, *file)
+}
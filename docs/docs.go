@@ -15,14 +15,1479 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/admin/dlq": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista as notificações que esgotaram as tentativas de envio, com o último erro e o histórico de tentativas. As mensagens permanecem na fila",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Administração"
+                ],
+                "summary": "Lista mensagens da DLQ",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Administração"
+                ],
+                "summary": "Esvazia a DLQ",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/dlq/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Administração"
+                ],
+                "summary": "Busca mensagem da DLQ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da mensagem na DLQ",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/dlq/{id}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devolve a notificação para a fila de envio, iniciando uma nova rodada de tentativas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Administração"
+                ],
+                "summary": "Reprocessa mensagem da DLQ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da mensagem na DLQ",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/api/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna as notificações de um usuário em páginas, com o total de registros que atendem aos filtros",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notificações"
+                ],
+                "summary": "Lista notificações do usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Itens por página (padrão 20, máximo 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor retornado em next_cursor pela página anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de ordenação (created_at, scheduled_for, updated_at, status), com prefixo - para ordem decrescente (padrão -created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status separados por vírgula (PENDENTE, EM_PROCESSAMENTO, ENVIADA, FALHA, CANCELADA, EXPIRADA)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Filtra pela localização",
+                        "name": "location_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Agendadas a partir de (RFC 3339 ou AAAA-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Agendadas antes de (RFC 3339 ou AAAA-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Cria um agendamento de notificação de previsão do tempo",
+                "description": "Cria um agendamento de notificação de previsão do tempo. Sem location_id, agenda as localizações salvas do usuário com notificação ativa, em mensagens separadas ou combinadas conforme a preferência do usuário",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notificações"
+                ],
+                "summary": "Agenda uma nova notificação",
+                "parameters": [
+                    {
+                        "description": "Dados do agendamento",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateNotificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/notifications/global": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna as notificações globais em páginas, com o total de registros que atendem aos filtros. Sem o filtro active, apenas as ativas são listadas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notificações Globais"
+                ],
+                "summary": "Lista notificações globais",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Itens por página (padrão 20, máximo 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor retornado em next_cursor pela página anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de ordenação (created_at, time_of_day), com prefixo - para ordem decrescente",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filtra pelas ativas ou inativas (padrão true)",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtra pela frequência (DIARIA, SEMANAL)",
+                        "name": "frequency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cria uma notificação que será enviada para todos os usuários ativos no horário especificado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notificações Globais"
+                ],
+                "summary": "Cria uma notificação global",
+                "parameters": [
+                    {
+                        "description": "Dados da notificação global",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateGlobalNotificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/notifications/global/{id}/runs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista as execuções realizadas e as ignoradas (perdidas fora do período de tolerância) por fuso horário",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notificações Globais"
+                ],
+                "summary": "Histórico de execuções de uma notificação global",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID da notificação global",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/notifications/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancela uma notificação pendente ou com falha, impedindo novos envios",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notificações"
+                ],
+                "summary": "Cancela uma notificação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID da notificação",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/notifications/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna o resultado do envio da notificação em cada canal",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notificações"
+                ],
+                "summary": "Lista entregas da notificação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID da notificação",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/notifications/{id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna as mudanças de status da notificação, com autor e erro de cada transição",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notificações"
+                ],
+                "summary": "Lista o histórico da notificação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID da notificação",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/notifications/{id}/reschedule": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Altera o horário de envio de uma notificação pendente",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notificações"
+                ],
+                "summary": "Reagenda uma notificação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID da notificação",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Novo horário",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RescheduleNotificationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/scheduler/leader": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna a instância que detém a liderança do agendador de notificações globais e se a instância atual é a líder",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agendador"
+                ],
+                "summary": "Status da liderança do agendador global",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna os usuários cadastrados em páginas, com o total de registros que atendem aos filtros",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Usuários"
+                ],
+                "summary": "Lista usuários",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Itens por página (padrão 20, máximo 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor retornado em next_cursor pela página anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de ordenação (created_at, name, email), com prefixo - para ordem decrescente",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filtra pelo opt-out",
+                        "name": "opt_out",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Filtra pela localização",
+                        "name": "location_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cadastrados a partir de (RFC 3339 ou AAAA-MM-DD)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cadastrados antes de (RFC 3339 ou AAAA-MM-DD)",
+                        "name": "created_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cria um usuário e vincula a uma localização, informada por location_id, cptec_code, latitude e longitude (a cidade mais próxima) ou nome da cidade (com a UF opcional). Se o nome corresponder a mais de uma cidade, retorna 409 com as candidatas em data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Usuários"
+                ],
+                "summary": "Cria um novo usuário",
+                "parameters": [
+                    {
+                        "description": "Dados do usuário",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/by-email": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna os dados do usuário cadastrado com o e-mail informado",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Usuários"
+                ],
+                "summary": "Busca um usuário pelo e-mail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "E-mail do usuário",
+                        "name": "email",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna os dados de um usuário pelo ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Usuários"
+                ],
+                "summary": "Busca um usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Atualiza os dados de um usuário, incluindo se as localizações salvas recebem uma notificação cada (SEPARADA) ou uma única mensagem (COMBINADA) e quantos dias de previsão as notificações cobrem (1 a 11)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Usuários"
+                ],
+                "summary": "Atualiza um usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados para atualização",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove o usuário com suas notificações, inscrições e alertas. Notificações pendentes deixam de ser enviadas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Usuários"
+                ],
+                "summary": "Remove um usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/alerts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna os alertas meteorológicos em que o usuário está inscrito",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alertas"
+                ],
+                "summary": "Lista regras de alerta do usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Inscreve o usuário em um alerta meteorológico (temperatura, UV, altura das ondas ou condição do tempo)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alertas"
+                ],
+                "summary": "Cria uma regra de alerta",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Regra de alerta",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateAlertRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/alerts/{alert_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancela a inscrição do usuário em um alerta meteorológico",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alertas"
+                ],
+                "summary": "Remove uma regra de alerta",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do alerta",
+                        "name": "alert_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/channels": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Define por quais canais (EMAIL, WEBHOOK, SLACK, SMS) o usuário recebe notificações e seus endereços",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Usuários"
+                ],
+                "summary": "Atualiza os canais de notificação do usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Canais de notificação",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateChannelsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/locations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna as localizações salvas do usuário, com a principal primeiro",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Localizações do usuário"
+                ],
+                "summary": "Lista as localizações do usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adiciona uma localização com rótulo (\"casa\", \"casa de praia\") às localizações do usuário. Com primary a localização passa a ser a principal do cadastro",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Localizações do usuário"
+                ],
+                "summary": "Salva uma localização do usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Localização a salvar",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UserLocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}/locations/{user_location_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Altera o rótulo, ativa ou desativa as notificações da localização ou a torna a principal",
                 "consumes": [
                     "application/json"
                 ],
@@ -30,23 +1495,98 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Notificações"
+                    "Localizações do usuário"
                 ],
-                "summary": "Agenda uma nova notificação",
+                "summary": "Atualiza uma localização do usuário",
                 "parameters": [
                     {
-                        "description": "Dados do agendamento",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID da localização salva",
+                        "name": "user_location_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Campos a alterar",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.CreateNotificationRequest"
+                            "$ref": "#/definitions/handler.UserLocationRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove uma localização salva. A localização principal só pode ser removida depois que outra for escolhida como principal",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Localizações do usuário"
+                ],
+                "summary": "Remove uma localização do usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID da localização salva",
+                        "name": "user_location_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/handler.Response"
                         }
@@ -57,6 +1597,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -66,21 +1618,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/notifications/global": {
+        "/api/users/{id}/subscriptions": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retorna todas as notificações globais ativas",
+                "description": "Retorna os agendamentos recorrentes do usuário com a próxima execução prevista",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Notificações Globais"
+                    "Inscrições"
+                ],
+                "summary": "Lista inscrições recorrentes do usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Lista notificações globais ativas",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -88,6 +1650,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -102,7 +1676,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Cria uma notificação que será enviada para todos os usuários ativos no horário especificado",
+                "description": "Agenda o envio recorrente da previsão para o usuário a partir de uma expressão cron (\"30 6 * * 1-5\") ou de um horário e dias da semana, avaliados no fuso horário do usuário",
                 "consumes": [
                     "application/json"
                 ],
@@ -110,17 +1684,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Notificações Globais"
+                    "Inscrições"
                 ],
-                "summary": "Cria uma notificação global",
+                "summary": "Cria uma inscrição recorrente",
                 "parameters": [
                     {
-                        "description": "Dados da notificação global",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Agendamento recorrente",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.CreateGlobalNotificationRequest"
+                            "$ref": "#/definitions/handler.SubscriptionRequest"
                         }
                     }
                 ],
@@ -137,6 +1719,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -146,28 +1734,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/notifications/{user_id}": {
+        "/api/users/{id}/subscriptions/{subscription_id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retorna todas as notificações de um usuário específico",
+                "description": "Retorna um agendamento recorrente do usuário",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Notificações"
+                    "Inscrições"
                 ],
-                "summary": "Lista notificações do usuário",
+                "summary": "Busca uma inscrição recorrente",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
                         "description": "ID do usuário",
-                        "name": "user_id",
-                        "in": "query",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID da inscrição",
+                        "name": "subscription_id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -184,6 +1780,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -191,23 +1793,51 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/users": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retorna uma lista de todos os usuários cadastrados",
+                "description": "Altera o agendamento, a localização ou pausa/reativa uma inscrição recorrente",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Usuários"
+                    "Inscrições"
+                ],
+                "summary": "Atualiza uma inscrição recorrente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID da inscrição",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Campos a alterar",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SubscriptionRequest"
+                        }
+                    }
                 ],
-                "summary": "Lista usuários",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -215,6 +1845,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.Response"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -223,37 +1865,41 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Cria um usuário e vincula a uma localização",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Cancela um agendamento recorrente do usuário",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Usuários"
+                    "Inscrições"
                 ],
-                "summary": "Cria um novo usuário",
+                "summary": "Remove uma inscrição recorrente",
                 "parameters": [
                     {
-                        "description": "Dados do usuário",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.CreateUserRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "ID da inscrição",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/handler.Response"
                         }
@@ -264,6 +1910,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -273,14 +1925,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/{id}": {
-            "put": {
+        "/api/users/{user_id}/optout": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Atualiza os dados de um usuário",
+                "description": "Permite ao usuário ativar ou desativar o opt-out de notificações",
                 "consumes": [
                     "application/json"
                 ],
@@ -290,23 +1942,23 @@ const docTemplate = `{
                 "tags": [
                     "Usuários"
                 ],
-                "summary": "Atualiza um usuário",
+                "summary": "Ativa ou desativa o opt-out do usuário",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
                         "description": "ID do usuário",
-                        "name": "id",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dados para atualização",
+                        "description": "Novo status do opt-out",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.UpdateUserRequest"
+                            "$ref": "#/definitions/handler.ToggleOptOutRequest"
                         }
                     }
                 ],
@@ -338,41 +1990,85 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/{user_id}/optout": {
-            "patch": {
+        "/api/weather/cache/stats": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Permite ao usuário ativar ou desativar o opt-out de notificações",
-                "consumes": [
+                "description": "Retorna a quantidade de acertos e falhas do cache de previsões do CPTEC",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Clima"
+                ],
+                "summary": "Estatísticas do cache de previsões",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/weather/conditions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna o catálogo de códigos de condição do CPTEC com descrição, severidade e ícone",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Usuários"
+                    "Clima"
                 ],
-                "summary": "Ativa ou desativa o opt-out do usuário",
+                "summary": "Lista as condições do tempo do CPTEC",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/weather/forecast": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna a previsão do tempo para uma localidade. Com days acima de 4 usa a previsão de 7 dias ou a estendida do CPTEC, informada em horizon",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clima"
+                ],
+                "summary": "Busca previsão do tempo",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "ID do usuário",
-                        "name": "user_id",
-                        "in": "path",
+                        "description": "ID da localidade",
+                        "name": "location_id",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Novo status do opt-out",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.ToggleOptOutRequest"
-                        }
+                        "type": "integer",
+                        "description": "Dias de previsão, de 1 a 11 (padrão 4)",
+                        "name": "days",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -397,29 +2093,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/weather/forecast": {
+        "/api/weather/nearest": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retorna a previsão do tempo para uma localidade",
+                "description": "Retorna as cidades do catálogo mais próximas das coordenadas, ordenadas pela distância em quilômetros (fórmula de haversine). Só participam cidades importadas com latitude e longitude",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Clima"
+                    "Localizações"
                 ],
-                "summary": "Busca previsão do tempo",
+                "summary": "Busca as cidades mais próximas",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "ID da localidade",
-                        "name": "location_id",
+                        "type": "number",
+                        "example": -23.4336,
+                        "description": "Latitude",
+                        "name": "lat",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "number",
+                        "example": -45.0838,
+                        "description": "Longitude",
+                        "name": "lon",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Quantidade de cidades (padrão 5, máximo 20)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -435,6 +2145,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -451,7 +2167,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Busca uma cidade no CPTEC por nome",
+                "description": "Busca uma cidade por nome, opcionalmente restrita a uma UF, no catálogo local (tolerante a acentos e erros de digitação) e, sem correspondência exata, sem acentos ou por prefixo, no CPTEC. Os resultados vêm ordenados pela correspondência com o nome (EXATA, SEM_ACENTO, PREFIXO, PARCIAL, APROXIMADA)",
                 "produces": [
                     "application/json"
                 ],
@@ -466,6 +2182,13 @@ const docTemplate = `{
                         "name": "city",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "RS",
+                        "description": "UF da cidade",
+                        "name": "state",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -538,6 +2261,53 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "entity.AlertMetric": {
+            "type": "string",
+            "enum": [
+                "TEMPERATURA_MAXIMA",
+                "TEMPERATURA_MINIMA",
+                "UV",
+                "ALTURA_ONDA",
+                "CONDICAO"
+            ],
+            "x-enum-varnames": [
+                "MetricMaxTemp",
+                "MetricMinTemp",
+                "MetricUV",
+                "MetricWaveHeight",
+                "MetricCondition"
+            ]
+        },
+        "entity.AlertOperator": {
+            "type": "string",
+            "enum": [
+                "MAIOR",
+                "MAIOR_IGUAL",
+                "MENOR",
+                "MENOR_IGUAL"
+            ],
+            "x-enum-varnames": [
+                "OperatorGreater",
+                "OperatorGreaterEqual",
+                "OperatorLess",
+                "OperatorLessEqual"
+            ]
+        },
+        "entity.Channel": {
+            "type": "string",
+            "enum": [
+                "EMAIL",
+                "WEBHOOK",
+                "SLACK",
+                "SMS"
+            ],
+            "x-enum-varnames": [
+                "ChannelEmail",
+                "ChannelWebhook",
+                "ChannelSlack",
+                "ChannelSMS"
+            ]
+        },
         "entity.Frequency": {
             "type": "string",
             "enum": [
@@ -549,6 +2319,72 @@ const docTemplate = `{
                 "FrequencyWeekly"
             ]
         },
+        "entity.NotificationMode": {
+            "type": "string",
+            "enum": [
+                "SEPARADA",
+                "COMBINADA"
+            ],
+            "x-enum-varnames": [
+                "NotificationModeSeparate",
+                "NotificationModeCombined"
+            ]
+        },
+        "handler.CreateAlertRuleRequest": {
+            "type": "object",
+            "required": [
+                "metric"
+            ],
+            "properties": {
+                "conditions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "t",
+                        "ch"
+                    ]
+                },
+                "location_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "metric": {
+                    "enum": [
+                        "TEMPERATURA_MAXIMA",
+                        "TEMPERATURA_MINIMA",
+                        "UV",
+                        "ALTURA_ONDA",
+                        "CONDICAO"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.AlertMetric"
+                        }
+                    ],
+                    "example": "TEMPERATURA_MAXIMA"
+                },
+                "operator": {
+                    "enum": [
+                        "MAIOR",
+                        "MAIOR_IGUAL",
+                        "MENOR",
+                        "MENOR_IGUAL"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.AlertOperator"
+                        }
+                    ],
+                    "example": "MAIOR"
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 35
+                }
+            }
+        },
         "handler.CreateGlobalNotificationRequest": {
             "type": "object",
             "required": [
@@ -577,7 +2413,6 @@ const docTemplate = `{
         "handler.CreateNotificationRequest": {
             "type": "object",
             "required": [
-                "location_id",
                 "schedule_for",
                 "user_id"
             ],
@@ -599,22 +2434,67 @@ const docTemplate = `{
         "handler.CreateUserRequest": {
             "type": "object",
             "required": [
-                "city",
                 "email",
                 "name"
             ],
             "properties": {
                 "city": {
                     "type": "string",
-                    "example": "São Paulo"
+                    "example": "Santa Maria"
+                },
+                "cptec_code": {
+                    "type": "integer",
+                    "example": 4598
                 },
                 "email": {
                     "type": "string",
                     "example": "matheus@exemplo.com"
                 },
+                "forecast_days": {
+                    "type": "integer",
+                    "maximum": 11,
+                    "minimum": 1,
+                    "example": 7
+                },
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90,
+                    "example": -29.6842
+                },
+                "location_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180,
+                    "example": -53.8069
+                },
                 "name": {
                     "type": "string",
                     "example": "Matheus"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "RS"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "America/Sao_Paulo"
+                }
+            }
+        },
+        "handler.RescheduleNotificationRequest": {
+            "type": "object",
+            "required": [
+                "schedule_for"
+            ],
+            "properties": {
+                "schedule_for": {
+                    "type": "string",
+                    "example": "2025-02-04T08:00:00-03:00"
                 }
             }
         },
@@ -630,6 +2510,44 @@ const docTemplate = `{
                 }
             }
         },
+        "handler.SubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "cron": {
+                    "type": "string",
+                    "example": "30 6 * * 1-5"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "SEG",
+                        "TER",
+                        "QUA",
+                        "QUI",
+                        "SEX"
+                    ]
+                },
+                "location_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "time": {
+                    "type": "string",
+                    "example": "06:30"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "America/Manaus"
+                }
+            }
+        },
         "handler.ToggleOptOutRequest": {
             "type": "object",
             "properties": {
@@ -638,14 +2556,125 @@ const docTemplate = `{
                 }
             }
         },
+        "handler.UpdateChannelsRequest": {
+            "type": "object",
+            "required": [
+                "channels"
+            ],
+            "properties": {
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.UserChannelRequest"
+                    }
+                }
+            }
+        },
         "handler.UpdateUserRequest": {
             "type": "object",
             "properties": {
                 "city": {
                     "type": "string"
                 },
+                "cptec_code": {
+                    "type": "integer",
+                    "example": 4598
+                },
+                "forecast_days": {
+                    "type": "integer",
+                    "maximum": 11,
+                    "minimum": 1,
+                    "example": 7
+                },
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90,
+                    "example": -3.119
+                },
+                "location_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180,
+                    "example": -60.0217
+                },
                 "name": {
                     "type": "string"
+                },
+                "notification_mode": {
+                    "enum": [
+                        "SEPARADA",
+                        "COMBINADA"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.NotificationMode"
+                        }
+                    ],
+                    "example": "COMBINADA"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "RS"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "America/Manaus"
+                }
+            }
+        },
+        "handler.UserChannelRequest": {
+            "type": "object",
+            "required": [
+                "channel"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "matheus@exemplo.com"
+                },
+                "channel": {
+                    "enum": [
+                        "EMAIL",
+                        "WEBHOOK",
+                        "SLACK",
+                        "SMS"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.Channel"
+                        }
+                    ],
+                    "example": "EMAIL"
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.UserLocationRequest": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "example": "Casa de praia"
+                },
+                "location_id": {
+                    "type": "string",
+                    "example": "550e8400-e29b-41d4-a716-446655440000"
+                },
+                "notify": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "primary": {
+                    "type": "boolean",
+                    "example": false
                 }
             }
         }
@@ -0,0 +1,174 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/google/uuid"
+)
+
+type AlertMetric string
+type AlertOperator string
+
+const (
+	MetricMaxTemp    AlertMetric = "TEMPERATURA_MAXIMA"
+	MetricMinTemp    AlertMetric = "TEMPERATURA_MINIMA"
+	MetricUV         AlertMetric = "UV"
+	MetricWaveHeight AlertMetric = "ALTURA_ONDA"
+	MetricCondition  AlertMetric = "CONDICAO"
+
+	OperatorGreater      AlertOperator = "MAIOR"
+	OperatorGreaterEqual AlertOperator = "MAIOR_IGUAL"
+	OperatorLess         AlertOperator = "MENOR"
+	OperatorLessEqual    AlertOperator = "MENOR_IGUAL"
+)
+
+type AlertRule struct {
+	ID         uuid.UUID     `json:"id"`
+	UserID     uuid.UUID     `json:"user_id"`
+	LocationID uuid.UUID     `json:"location_id"`
+	Metric     AlertMetric   `json:"metric"`
+	Operator   AlertOperator `json:"operator,omitempty"`
+	Threshold  float64       `json:"threshold"`
+	Conditions []string      `json:"conditions,omitempty"`
+	Active     bool          `json:"active"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+func NewAlertRule(userID, locationID uuid.UUID, metric AlertMetric, operator AlertOperator, threshold float64, conditions []string) (*AlertRule, error) {
+	if userID == uuid.Nil {
+		return nil, handler.ErrInvalidUserID
+	}
+	if locationID == uuid.Nil {
+		return nil, handler.ErrInvalidLocationID
+	}
+
+	switch metric {
+	case MetricMaxTemp, MetricMinTemp, MetricUV, MetricWaveHeight:
+		if !operator.IsValid() {
+			return nil, handler.ErrInvalidAlertOperator
+		}
+		if (metric == MetricUV || metric == MetricWaveHeight) && threshold < 0 {
+			return nil, handler.ErrInvalidAlertThreshold
+		}
+		conditions = nil
+	case MetricCondition:
+		if len(conditions) == 0 {
+			conditions = SevereConditionCodes()
+		}
+		operator = ""
+		threshold = 0
+	default:
+		return nil, handler.ErrInvalidAlertMetric
+	}
+
+	now := time.Now()
+	return &AlertRule{
+		ID:         uuid.New(),
+		UserID:     userID,
+		LocationID: locationID,
+		Metric:     metric,
+		Operator:   operator,
+		Threshold:  threshold,
+		Conditions: conditions,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+func (o AlertOperator) IsValid() bool {
+	switch o {
+	case OperatorGreater, OperatorGreaterEqual, OperatorLess, OperatorLessEqual:
+		return true
+	default:
+		return false
+	}
+}
+
+func (o AlertOperator) compare(value, threshold float64) bool {
+	switch o {
+	case OperatorGreater:
+		return value > threshold
+	case OperatorGreaterEqual:
+		return value >= threshold
+	case OperatorLess:
+		return value < threshold
+	case OperatorLessEqual:
+		return value <= threshold
+	default:
+		return false
+	}
+}
+
+func (o AlertOperator) symbol() string {
+	switch o {
+	case OperatorGreater:
+		return ">"
+	case OperatorGreaterEqual:
+		return ">="
+	case OperatorLess:
+		return "<"
+	case OperatorLessEqual:
+		return "<="
+	default:
+		return string(o)
+	}
+}
+
+func (r *AlertRule) value(f WeatherForecast) (float64, bool) {
+	switch r.Metric {
+	case MetricMaxTemp:
+		return f.MaxTemp, true
+	case MetricMinTemp:
+		return f.MinTemp, true
+	case MetricUV:
+		return f.UV, true
+	case MetricWaveHeight:
+		if !f.HasWaveForecast() {
+			return 0, false
+		}
+		return max(f.Wave.Morning.Height, f.Wave.Afternoon.Height, f.Wave.Night.Height), true
+	default:
+		return 0, false
+	}
+}
+
+func (r *AlertRule) Matches(f WeatherForecast) bool {
+	if !r.Active {
+		return false
+	}
+
+	if r.Metric == MetricCondition {
+		return slices.Contains(r.Conditions, f.Forecast)
+	}
+
+	value, ok := r.value(f)
+	if !ok {
+		return false
+	}
+
+	return r.Operator.compare(value, r.Threshold)
+}
+
+func (r *AlertRule) Describe(f WeatherForecast) string {
+	date := f.Date.Format("02/01")
+
+	switch r.Metric {
+	case MetricMaxTemp:
+		return fmt.Sprintf("Alerta: temperatura máxima de %.1f°C em %s (%s %.1f°C)", f.MaxTemp, date, r.Operator.symbol(), r.Threshold)
+	case MetricMinTemp:
+		return fmt.Sprintf("Alerta: temperatura mínima de %.1f°C em %s (%s %.1f°C)", f.MinTemp, date, r.Operator.symbol(), r.Threshold)
+	case MetricUV:
+		return fmt.Sprintf("Alerta: índice UV %.1f em %s (%s %.1f)", f.UV, date, r.Operator.symbol(), r.Threshold)
+	case MetricWaveHeight:
+		height, _ := r.value(f)
+		return fmt.Sprintf("Alerta: ondas de %.1fm em %s (%s %.1fm)", height, date, r.Operator.symbol(), r.Threshold)
+	default:
+		return fmt.Sprintf("Alerta: %s prevista para %s", strings.ToLower(f.Description()), date)
+	}
+}
@@ -0,0 +1,65 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertRule_Matches(t *testing.T) {
+	forecast := entity.WeatherForecast{
+		Date:     time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+		MinTemp:  22,
+		MaxTemp:  36.5,
+		Forecast: "t",
+		UV:       8,
+		Wave: &entity.WaveInfo{
+			Morning:   entity.WavePeriod{Height: 1.5},
+			Afternoon: entity.WavePeriod{Height: 2.7},
+			Night:     entity.WavePeriod{Height: 1.8},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		metric     entity.AlertMetric
+		operator   entity.AlertOperator
+		threshold  float64
+		conditions []string
+		expected   bool
+	}{
+		{"máxima acima de 35", entity.MetricMaxTemp, entity.OperatorGreater, 35, nil, true},
+		{"máxima acima de 37", entity.MetricMaxTemp, entity.OperatorGreater, 37, nil, false},
+		{"UV maior ou igual a 8", entity.MetricUV, entity.OperatorGreaterEqual, 8, nil, true},
+		{"mínima abaixo de 10", entity.MetricMinTemp, entity.OperatorLess, 10, nil, false},
+		{"onda acima de 2.5m", entity.MetricWaveHeight, entity.OperatorGreater, 2.5, nil, true},
+		{"condição de tempestade padrão", entity.MetricCondition, "", 0, nil, true},
+		{"condição não prevista", entity.MetricCondition, "", 0, []string{"g"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := entity.NewAlertRule(uuid.New(), uuid.New(), tt.metric, tt.operator, tt.threshold, tt.conditions)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, rule.Matches(forecast))
+		})
+	}
+}
+
+func TestNewAlertRule_Validation(t *testing.T) {
+	_, err := entity.NewAlertRule(uuid.New(), uuid.New(), "VENTO", entity.OperatorGreater, 10, nil)
+	assert.Equal(t, handler.ErrInvalidAlertMetric, err)
+
+	_, err = entity.NewAlertRule(uuid.New(), uuid.New(), entity.MetricUV, "", 8, nil)
+	assert.Equal(t, handler.ErrInvalidAlertOperator, err)
+
+	_, err = entity.NewAlertRule(uuid.New(), uuid.New(), entity.MetricWaveHeight, entity.OperatorGreater, -1, nil)
+	assert.Equal(t, handler.ErrInvalidAlertThreshold, err)
+
+	_, err = entity.NewAlertRule(uuid.New(), uuid.Nil, entity.MetricUV, entity.OperatorGreater, 8, nil)
+	assert.Equal(t, handler.ErrInvalidLocationID, err)
+}
@@ -0,0 +1,132 @@
+package entity
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/google/uuid"
+)
+
+type Channel string
+
+const (
+	ChannelEmail   Channel = "EMAIL"
+	ChannelWebhook Channel = "WEBHOOK"
+	ChannelSlack   Channel = "SLACK"
+	ChannelSMS     Channel = "SMS"
+)
+
+type UserChannel struct {
+	Channel Channel `json:"channel"`
+	Address string  `json:"address,omitempty"`
+	Enabled bool    `json:"enabled"`
+}
+
+type DeliveryResult struct {
+	ID             uuid.UUID          `json:"id"`
+	NotificationID uuid.UUID          `json:"notification_id"`
+	Channel        Channel            `json:"channel"`
+	Address        string             `json:"address,omitempty"`
+	Status         NotificationStatus `json:"status"`
+	Error          string             `json:"error,omitempty"`
+	HTTPStatus     int                `json:"http_status,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+func (c Channel) IsValid() bool {
+	switch c {
+	case ChannelEmail, ChannelWebhook, ChannelSlack, ChannelSMS:
+		return true
+	default:
+		return false
+	}
+}
+
+func NewUserChannel(channel Channel, address string, enabled bool) (*UserChannel, error) {
+	if !channel.IsValid() {
+		return nil, handler.ErrInvalidChannel
+	}
+
+	if address == "" && channel == ChannelSMS {
+		return nil, handler.ErrEmptyChannelAddress
+	}
+	if err := validateChannelAddress(channel, address); err != nil {
+		return nil, err
+	}
+
+	return &UserChannel{
+		Channel: channel,
+		Address: address,
+		Enabled: enabled,
+	}, nil
+}
+
+// validateChannelAddress confere o formato do endereço informado pelo
+// usuário. Webhooks e Slack precisam de uma URL http ou https absoluta que
+// não aponte para a própria rede do serviço, já que o worker faz a
+// requisição em nome do usuário.
+func validateChannelAddress(channel Channel, address string) error {
+	if address == "" {
+		return nil
+	}
+
+	switch channel {
+	case ChannelEmail:
+		if !IsEmailAddress(address) {
+			return fmt.Errorf("%w: email %q", handler.ErrInvalidChannelAddress, address)
+		}
+	case ChannelWebhook, ChannelSlack:
+		target, err := url.Parse(address)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+			return fmt.Errorf("%w: informe uma URL http ou https", handler.ErrInvalidChannelAddress)
+		}
+		if isInternalHost(target.Hostname()) {
+			return fmt.Errorf("%w: a URL não pode apontar para um endereço interno", handler.ErrInvalidChannelAddress)
+		}
+	}
+
+	return nil
+}
+
+// IsEmailAddress indica se o valor é um endereço de email simples, sem nome
+// de exibição.
+func IsEmailAddress(value string) bool {
+	address, err := mail.ParseAddress(value)
+	return err == nil && address.Address == value
+}
+
+func isInternalHost(host string) bool {
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
+
+func NewDeliveryResult(notificationID uuid.UUID, channel Channel, address string, err error) *DeliveryResult {
+	result := &DeliveryResult{
+		ID:             uuid.New(),
+		NotificationID: notificationID,
+		Channel:        channel,
+		Address:        address,
+		Status:         StatusSent,
+		CreatedAt:      time.Now(),
+	}
+
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		result.HTTPStatus = handler.StatusCode(err)
+	}
+
+	return result
+}
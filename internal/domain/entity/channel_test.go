@@ -0,0 +1,47 @@
+package entity_test
+
+import (
+	"testing"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUserChannel(t *testing.T) {
+	tests := []struct {
+		name        string
+		channel     entity.Channel
+		address     string
+		expectError error
+	}{
+		{"email válido", entity.ChannelEmail, "ana@exemplo.com", nil},
+		{"email do cadastro", entity.ChannelEmail, "", nil},
+		{"email inválido", entity.ChannelEmail, "ana.exemplo.com", handler.ErrInvalidChannelAddress},
+		{"email com nome de exibição", entity.ChannelEmail, "Ana <ana@exemplo.com>", handler.ErrInvalidChannelAddress},
+		{"webhook https", entity.ChannelWebhook, "https://exemplo.com/hooks/clima", nil},
+		{"webhook configurado", entity.ChannelWebhook, "", nil},
+		{"webhook sem esquema", entity.ChannelWebhook, "exemplo.com/hooks", handler.ErrInvalidChannelAddress},
+		{"webhook com outro esquema", entity.ChannelWebhook, "ftp://exemplo.com/hooks", handler.ErrInvalidChannelAddress},
+		{"webhook para metadados da nuvem", entity.ChannelWebhook, "http://169.254.169.254/latest/meta-data", handler.ErrInvalidChannelAddress},
+		{"webhook para rede privada", entity.ChannelWebhook, "http://10.0.0.5:8080/", handler.ErrInvalidChannelAddress},
+		{"slack para localhost", entity.ChannelSlack, "http://localhost:15672/api", handler.ErrInvalidChannelAddress},
+		{"slack válido", entity.ChannelSlack, "https://hooks.slack.com/services/T000/B000/XXX", nil},
+		{"sms sem número", entity.ChannelSMS, "", handler.ErrEmptyChannelAddress},
+		{"canal desconhecido", entity.Channel("FAX"), "", handler.ErrInvalidChannel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel, err := entity.NewUserChannel(tt.channel, tt.address, true)
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, channel)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.address, channel.Address)
+		})
+	}
+}
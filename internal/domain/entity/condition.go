@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+)
+
+type ConditionSeverity string
+
+const (
+	SeverityLow      ConditionSeverity = "BAIXA"
+	SeverityModerate ConditionSeverity = "MODERADA"
+	SeverityHigh     ConditionSeverity = "ALTA"
+)
+
+type WeatherCondition struct {
+	Code          string            `json:"code"`
+	Description   string            `json:"description"`
+	DescriptionEN string            `json:"description_en"`
+	Severity      ConditionSeverity `json:"severity"`
+	Icon          string            `json:"icon"`
+}
+
+var conditionCatalog = map[string]WeatherCondition{
+	"ec":  {"ec", "Encoberto com chuvas isoladas", "Overcast with isolated showers", SeverityModerate, "🌦️"},
+	"ci":  {"ci", "Chuvas isoladas", "Isolated showers", SeverityModerate, "🌦️"},
+	"c":   {"c", "Chuva", "Rain", SeverityHigh, "🌧️"},
+	"in":  {"in", "Instável", "Unstable", SeverityModerate, "🌦️"},
+	"pp":  {"pp", "Possibilidade de pancadas de chuva", "Chance of showers", SeverityModerate, "🌦️"},
+	"cm":  {"cm", "Chuva pela manhã", "Rain in the morning", SeverityModerate, "🌧️"},
+	"cn":  {"cn", "Chuva à noite", "Rain at night", SeverityModerate, "🌧️"},
+	"pt":  {"pt", "Pancadas de chuva à tarde", "Afternoon showers", SeverityHigh, "🌧️"},
+	"pm":  {"pm", "Pancadas de chuva pela manhã", "Morning showers", SeverityHigh, "🌧️"},
+	"np":  {"np", "Nublado e pancadas de chuva", "Cloudy with showers", SeverityHigh, "🌧️"},
+	"pc":  {"pc", "Pancadas de chuva", "Showers", SeverityHigh, "🌧️"},
+	"pn":  {"pn", "Parcialmente nublado", "Partly cloudy", SeverityLow, "⛅"},
+	"cv":  {"cv", "Chuvisco", "Drizzle", SeverityModerate, "🌦️"},
+	"ch":  {"ch", "Chuvoso", "Rainy", SeverityHigh, "🌧️"},
+	"t":   {"t", "Tempestade", "Storm", SeverityHigh, "⛈️"},
+	"ps":  {"ps", "Predomínio de sol", "Mostly sunny", SeverityLow, "🌤️"},
+	"e":   {"e", "Encoberto", "Overcast", SeverityLow, "☁️"},
+	"n":   {"n", "Nublado", "Cloudy", SeverityLow, "☁️"},
+	"cl":  {"cl", "Céu claro", "Clear sky", SeverityLow, "☀️"},
+	"nv":  {"nv", "Nevoeiro", "Fog", SeverityModerate, "🌫️"},
+	"g":   {"g", "Geada", "Frost", SeverityHigh, "❄️"},
+	"ne":  {"ne", "Neve", "Snow", SeverityHigh, "❄️"},
+	"nd":  {"nd", "Não definido", "Not defined", SeverityLow, "❔"},
+	"pnt": {"pnt", "Pancadas de chuva à noite", "Showers at night", SeverityHigh, "🌧️"},
+	"psc": {"psc", "Possibilidade de chuva", "Chance of rain", SeverityModerate, "🌦️"},
+	"pcm": {"pcm", "Possibilidade de chuva pela manhã", "Chance of rain in the morning", SeverityModerate, "🌦️"},
+	"pct": {"pct", "Possibilidade de chuva à tarde", "Chance of rain in the afternoon", SeverityModerate, "🌦️"},
+	"pcn": {"pcn", "Possibilidade de chuva à noite", "Chance of rain at night", SeverityModerate, "🌦️"},
+	"npt": {"npt", "Nublado com pancadas à tarde", "Cloudy with afternoon showers", SeverityHigh, "🌧️"},
+	"npn": {"npn", "Nublado com pancadas à noite", "Cloudy with showers at night", SeverityHigh, "🌧️"},
+	"ncn": {"ncn", "Nublado com possibilidade de chuva à noite", "Cloudy with chance of rain at night", SeverityModerate, "🌥️"},
+	"nct": {"nct", "Nublado com possibilidade de chuva à tarde", "Cloudy with chance of rain in the afternoon", SeverityModerate, "🌥️"},
+	"ncm": {"ncm", "Nublado com possibilidade de chuva pela manhã", "Cloudy with chance of rain in the morning", SeverityModerate, "🌥️"},
+	"npm": {"npm", "Nublado com pancadas pela manhã", "Cloudy with morning showers", SeverityHigh, "🌧️"},
+	"npp": {"npp", "Nublado com possibilidade de chuva", "Cloudy with chance of rain", SeverityModerate, "🌥️"},
+	"vn":  {"vn", "Variação de nebulosidade", "Variable cloudiness", SeverityLow, "⛅"},
+	"ct":  {"ct", "Chuva à tarde", "Rain in the afternoon", SeverityModerate, "🌧️"},
+	"ppn": {"ppn", "Possibilidade de pancadas de chuva à noite", "Chance of showers at night", SeverityModerate, "🌦️"},
+	"ppt": {"ppt", "Possibilidade de pancadas de chuva à tarde", "Chance of showers in the afternoon", SeverityModerate, "🌦️"},
+	"ppm": {"ppm", "Possibilidade de pancadas de chuva pela manhã", "Chance of showers in the morning", SeverityModerate, "🌦️"},
+}
+
+func LookupCondition(code string) WeatherCondition {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if condition, ok := conditionCatalog[code]; ok {
+		return condition
+	}
+
+	return WeatherCondition{
+		Code:          code,
+		Description:   code,
+		DescriptionEN: code,
+		Severity:      SeverityLow,
+		Icon:          "❔",
+	}
+}
+
+func ConditionCatalog() []WeatherCondition {
+	conditions := make([]WeatherCondition, 0, len(conditionCatalog))
+	for _, condition := range conditionCatalog {
+		conditions = append(conditions, condition)
+	}
+
+	sort.Slice(conditions, func(i, j int) bool {
+		return conditions[i].Code < conditions[j].Code
+	})
+
+	return conditions
+}
+
+func SevereConditionCodes() []string {
+	var codes []string
+	for _, condition := range ConditionCatalog() {
+		if condition.Severity == SeverityHigh {
+			codes = append(codes, condition.Code)
+		}
+	}
+	return codes
+}
@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	handler "weather-notification/internal/domain/error_handler"
+)
+
+const earthRadiusKm = 6371.0
+
+type Coordinates struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func NewCoordinates(latitude, longitude float64) (*Coordinates, error) {
+	if math.IsNaN(latitude) || math.IsNaN(longitude) ||
+		latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return nil, fmt.Errorf("%w: %v, %v", handler.ErrInvalidCoordinates, latitude, longitude)
+	}
+
+	return &Coordinates{Latitude: latitude, Longitude: longitude}, nil
+}
+
+// DistanceKm calcula a distância em quilômetros até outro ponto pela fórmula
+// de haversine.
+func (c Coordinates) DistanceKm(other Coordinates) float64 {
+	lat1, lat2 := radians(c.Latitude), radians(other.Latitude)
+	dLat := lat2 - lat1
+	dLon := radians(other.Longitude - c.Longitude)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Bounds retorna o retângulo que contém todos os pontos a até radiusKm de
+// distância, usado para filtrar o catálogo antes do cálculo exato.
+func (c Coordinates) Bounds(radiusKm float64) CoordinateBounds {
+	latDelta := radiusKm / earthRadiusKm * 180 / math.Pi
+	lonDelta := 180.0
+	if cos := math.Cos(radians(c.Latitude)); cos > 1e-6 {
+		lonDelta = math.Min(180, latDelta/cos)
+	}
+
+	return CoordinateBounds{
+		MinLatitude:  math.Max(-90, c.Latitude-latDelta),
+		MaxLatitude:  math.Min(90, c.Latitude+latDelta),
+		MinLongitude: math.Max(-180, c.Longitude-lonDelta),
+		MaxLongitude: math.Min(180, c.Longitude+lonDelta),
+	}
+}
+
+type CoordinateBounds struct {
+	MinLatitude  float64
+	MaxLatitude  float64
+	MinLongitude float64
+	MaxLongitude float64
+}
+
+// NearbyLocation é uma cidade do catálogo junto com a distância até o ponto
+// pesquisado.
+type NearbyLocation struct {
+	Location
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// NearestLocations ordena as cidades com coordenadas da mais próxima para a
+// mais distante da origem, limitadas a limit resultados.
+func NearestLocations(origin Coordinates, locations []Location, limit int) []NearbyLocation {
+	nearby := []NearbyLocation{}
+	for _, location := range locations {
+		if location.Coordinates == nil {
+			continue
+		}
+		nearby = append(nearby, NearbyLocation{
+			Location:   location,
+			DistanceKm: math.Round(origin.DistanceKm(*location.Coordinates)*100) / 100,
+		})
+	}
+
+	sort.SliceStable(nearby, func(i, j int) bool {
+		return nearby[i].DistanceKm < nearby[j].DistanceKm
+	})
+
+	if limit > 0 && len(nearby) > limit {
+		nearby = nearby[:limit]
+	}
+
+	return nearby
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,23 @@
+package entity_test
+
+import (
+	"testing"
+	"weather-notification/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoordinates_DistanceKm(t *testing.T) {
+	saoPaulo := entity.Coordinates{Latitude: -23.5505, Longitude: -46.6333}
+	rio := entity.Coordinates{Latitude: -22.9068, Longitude: -43.1729}
+
+	assert.InDelta(t, 361, saoPaulo.DistanceKm(rio), 2)
+	assert.Zero(t, saoPaulo.DistanceKm(saoPaulo))
+
+	bounds := saoPaulo.Bounds(400)
+	assert.True(t, rio.Latitude <= bounds.MaxLatitude && rio.Longitude <= bounds.MaxLongitude)
+	assert.False(t, rio.Longitude <= saoPaulo.Bounds(100).MaxLongitude)
+
+	_, err := entity.NewCoordinates(-23.5, 181)
+	assert.Error(t, err)
+}
@@ -0,0 +1,200 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	handler "weather-notification/internal/domain/error_handler"
+)
+
+type CronSchedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+	anyDay      bool
+	anyWeekday  bool
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField     = cronField{min: 0, max: 59}
+	hourField       = cronField{min: 0, max: 23}
+	dayOfMonthField = cronField{min: 1, max: 31}
+	monthField      = cronField{min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}}
+	dayOfWeekField = cronField{min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+		"DOM": 0, "SEG": 1, "TER": 2, "QUA": 3, "QUI": 4, "SEX": 5, "SAB": 6,
+	}}
+)
+
+func ParseCronExpression(expression string) (*CronSchedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: esperado 5 campos, recebido %d", handler.ErrInvalidCronExpression, len(fields))
+	}
+
+	var schedule CronSchedule
+	var err error
+
+	if schedule.minutes, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if schedule.hours, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if schedule.daysOfMonth, err = dayOfMonthField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if schedule.months, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if schedule.daysOfWeek, err = dayOfWeekField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+
+	if schedule.daysOfWeek&(1<<7) != 0 {
+		schedule.daysOfWeek |= 1
+	}
+	schedule.anyDay = fields[2] == "*"
+	schedule.anyWeekday = fields[4] == "*"
+
+	if schedule.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("%w: a combinação de dia e mês nunca ocorre", handler.ErrInvalidCronExpression)
+	}
+
+	return &schedule, nil
+}
+
+func CronExpressionFromDays(timeOfDay string, days []string) (string, error) {
+	t, err := time.Parse("15:04", timeOfDay)
+	if err != nil {
+		return "", fmt.Errorf("%w: horário deve estar no formato HH:MM", handler.ErrInvalidCronExpression)
+	}
+
+	weekdays := "*"
+	if len(days) > 0 {
+		values := make([]string, 0, len(days))
+		for _, day := range days {
+			value, ok := dayOfWeekField.names[strings.ToUpper(day)]
+			if !ok {
+				return "", fmt.Errorf("%w: dia da semana %q", handler.ErrInvalidCronExpression, day)
+			}
+			values = append(values, strconv.Itoa(value))
+		}
+		weekdays = strings.Join(values, ",")
+	}
+
+	return fmt.Sprintf("%d %d * * %s", t.Minute(), t.Hour(), weekdays), nil
+}
+
+func (f cronField) parse(expression string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(expression, ",") {
+		step := 1
+		if rangePart, stepPart, ok := strings.Cut(part, "/"); ok {
+			value, err := strconv.Atoi(stepPart)
+			if err != nil || value <= 0 {
+				return 0, fmt.Errorf("%w: passo inválido em %q", handler.ErrInvalidCronExpression, part)
+			}
+			step = value
+			part = rangePart
+		}
+
+		start, end := f.min, f.max
+		if part != "*" {
+			startPart, endPart, isRange := strings.Cut(part, "-")
+
+			var err error
+			if start, err = f.value(startPart); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = f.value(endPart); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				end = f.max
+			}
+		}
+
+		if start > end {
+			return 0, fmt.Errorf("%w: intervalo inválido em %q", handler.ErrInvalidCronExpression, part)
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func (f cronField) value(expression string) (int, error) {
+	if value, ok := f.names[strings.ToUpper(expression)]; ok {
+		return value, nil
+	}
+
+	value, err := strconv.Atoi(expression)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("%w: valor %q fora do intervalo %d-%d", handler.ErrInvalidCronExpression, expression, f.min, f.max)
+	}
+
+	return value, nil
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	dayOfMonth := s.daysOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.daysOfWeek&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return dayOfWeek
+	case s.anyWeekday:
+		return dayOfMonth
+	default:
+		return dayOfMonth || dayOfWeek
+	}
+}
+
+// Next retorna o primeiro horário após after que satisfaz a expressão, ou
+// o tempo zero quando nenhum ocorre nos próximos cinco anos.
+func (s *CronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
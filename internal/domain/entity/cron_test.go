@@ -0,0 +1,70 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCronSchedule_Next(t *testing.T) {
+	// 2024-02-02 é uma sexta-feira
+	after := time.Date(2024, 2, 2, 7, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		expression string
+		expected   time.Time
+	}{
+		{"dias úteis às 06:30", "30 6 * * 1-5", time.Date(2024, 2, 5, 6, 30, 0, 0, time.UTC)},
+		{"todo domingo às 18:00", "0 18 * * SUN", time.Date(2024, 2, 4, 18, 0, 0, 0, time.UTC)},
+		{"domingo como 7", "0 18 * * 7", time.Date(2024, 2, 4, 18, 0, 0, 0, time.UTC)},
+		{"a cada 15 minutos", "*/15 * * * *", time.Date(2024, 2, 2, 7, 15, 0, 0, time.UTC)},
+		{"primeiro dia do mês", "0 8 1 * *", time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)},
+		{"29 de fevereiro", "0 0 29 FEB *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := entity.ParseCronExpression(tt.expression)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, schedule.Next(after))
+		})
+	}
+}
+
+func TestParseCronExpression_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+	}{
+		{"campos insuficientes", "30 6 * *"},
+		{"minuto fora do intervalo", "60 6 * * *"},
+		{"dia da semana desconhecido", "0 6 * * XYZ"},
+		{"passo inválido", "*/0 * * * *"},
+		{"30 de fevereiro", "0 0 30 2 *"},
+		{"31 de abril", "0 0 31 4 *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := entity.ParseCronExpression(tt.expression)
+			assert.ErrorIs(t, err, handler.ErrInvalidCronExpression)
+		})
+	}
+}
+
+func TestCronExpressionFromDays(t *testing.T) {
+	expression, err := entity.CronExpressionFromDays("06:30", []string{"seg", "TER", "qua", "QUI", "SEX"})
+	assert.NoError(t, err)
+	assert.Equal(t, "30 6 * * 1,2,3,4,5", expression)
+
+	expression, err = entity.CronExpressionFromDays("18:00", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "0 18 * * *", expression)
+
+	_, err = entity.CronExpressionFromDays("25:00", nil)
+	assert.ErrorIs(t, err, handler.ErrInvalidCronExpression)
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DeliveryAttempt struct {
+	Attempt  int       `json:"attempt"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+type DeadLetter struct {
+	ID             string            `json:"id"`
+	NotificationID uuid.UUID         `json:"notification_id"`
+	UserID         uuid.UUID         `json:"user_id"`
+	LocationID     uuid.UUID         `json:"location_id"`
+	ScheduledFor   time.Time         `json:"scheduled_for"`
+	LastError      string            `json:"last_error"`
+	Attempts       []DeliveryAttempt `json:"attempts"`
+	DeadAt         time.Time         `json:"dead_at"`
+	Payload        json.RawMessage   `json:"payload,omitempty"`
+}
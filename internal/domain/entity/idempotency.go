@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeliveryIdempotency registra o envio de uma notificação por um canal. O
+// registro é reivindicado antes do envio e marcado como enviado depois, para
+// que reentregas da fila não repitam a notificação ao usuário. ClaimedAt
+// marca a posse exclusiva do envio enquanto ele está em andamento.
+type DeliveryIdempotency struct {
+	Key            string             `json:"key"`
+	NotificationID uuid.UUID          `json:"notification_id"`
+	Channel        Channel            `json:"channel"`
+	Status         NotificationStatus `json:"status"`
+	Attempts       int                `json:"attempts"`
+	CreatedAt      time.Time          `json:"created_at"`
+	ClaimedAt      *time.Time         `json:"claimed_at,omitempty"`
+	SentAt         *time.Time         `json:"sent_at,omitempty"`
+}
+
+// DeliveryKey é estável entre tentativas e enviada como Idempotency-Key,
+// permitindo que o destino descarte duplicatas.
+func DeliveryKey(notificationID uuid.UUID, channel Channel) string {
+	return notificationID.String() + ":" + strings.ToLower(string(channel))
+}
+
+func NewDeliveryIdempotency(notificationID uuid.UUID, channel Channel) *DeliveryIdempotency {
+	return &DeliveryIdempotency{
+		Key:            DeliveryKey(notificationID, channel),
+		NotificationID: notificationID,
+		Channel:        channel,
+		Status:         StatusPending,
+		Attempts:       1,
+		CreatedAt:      time.Now(),
+	}
+}
+
+func (d *DeliveryIdempotency) AlreadySent() bool {
+	return d.Status == StatusSent
+}
@@ -0,0 +1,17 @@
+package entity_test
+
+import (
+	"testing"
+	"weather-notification/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveryKey(t *testing.T) {
+	id := uuid.MustParse("7f1c9a62-3b1e-4d55-9a4e-2f9d3c1b8e10")
+
+	assert.Equal(t, "7f1c9a62-3b1e-4d55-9a4e-2f9d3c1b8e10:webhook", entity.DeliveryKey(id, entity.ChannelWebhook))
+	assert.Equal(t, entity.DeliveryKey(id, entity.ChannelWebhook), entity.NewDeliveryIdempotency(id, entity.ChannelWebhook).Key)
+	assert.NotEqual(t, entity.DeliveryKey(id, entity.ChannelWebhook), entity.DeliveryKey(id, entity.ChannelEmail))
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type Lease struct {
+	Name       string    `json:"name"`
+	HolderID   string    `json:"holder_id"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	RenewedAt  time.Time `json:"renewed_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func (l *Lease) IsHeldBy(holderID string, now time.Time) bool {
+	return l.HolderID == holderID && now.Before(l.ExpiresAt)
+}
@@ -2,9 +2,14 @@ package entity
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"unicode"
 	handler "weather-notification/internal/domain/error_handler"
 
 	"github.com/google/uuid"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/unicode/norm"
 )
 
 type Location struct {
@@ -12,6 +17,9 @@ type Location struct {
 	CPTECCode int       `json:"cptecCode"`
 	Name      string    `json:"name"`
 	State     string    `json:"state"`
+	Timezone  string    `json:"timezone"`
+	// Coordinates fica vazio nas cidades importadas sem latitude e longitude.
+	Coordinates *Coordinates `json:"coordinates,omitempty"`
 }
 
 func NewLocation(cptecCode int, name, state string) (*Location, error) {
@@ -32,5 +40,183 @@ func NewLocation(cptecCode int, name, state string) (*Location, error) {
 		CPTECCode: cptecCode,
 		Name:      name,
 		State:     state,
+		Timezone:  TimezoneForLocation(name, state),
 	}, nil
 }
+
+type LocationMatch string
+
+const (
+	MatchExact             LocationMatch = "EXATA"
+	MatchAccentInsensitive LocationMatch = "SEM_ACENTO"
+	MatchPrefix            LocationMatch = "PREFIXO"
+	MatchPartial           LocationMatch = "PARCIAL"
+	MatchApproximate       LocationMatch = "APROXIMADA"
+)
+
+var locationMatchRank = map[LocationMatch]int{
+	MatchExact:             0,
+	MatchAccentInsensitive: 1,
+	MatchPrefix:            2,
+	MatchPartial:           3,
+	MatchApproximate:       4,
+}
+
+// IsTextual indica uma correspondência exata, sem acentos ou por prefixo,
+// suficiente para dispensar a consulta ao CPTEC.
+func (m LocationMatch) IsTextual() bool {
+	return m == MatchExact || m == MatchAccentInsensitive || m == MatchPrefix
+}
+
+// RankedLocation é uma cidade encontrada na busca junto com o tipo de
+// correspondência com o nome pesquisado.
+type RankedLocation struct {
+	Location
+	Match LocationMatch `json:"match"`
+}
+
+// LocationQuery identifica uma localização pelo ID, pelo código CPTEC, pelas
+// coordenadas (a cidade mais próxima) ou pelo nome da cidade, opcionalmente
+// restrito a uma UF.
+type LocationQuery struct {
+	ID          uuid.UUID
+	CPTECCode   int
+	Coordinates *Coordinates
+	City        string
+	State       string
+}
+
+func (q LocationQuery) IsEmpty() bool {
+	return q.ID == uuid.Nil && q.CPTECCode == 0 && q.Coordinates == nil && strings.TrimSpace(q.City) == ""
+}
+
+// AmbiguousLocationError indica que mais de uma cidade corresponde ao nome
+// pesquisado com a mesma relevância.
+type AmbiguousLocationError struct {
+	City       string
+	Candidates []RankedLocation
+}
+
+func (e *AmbiguousLocationError) Error() string {
+	names := make([]string, 0, len(e.Candidates))
+	for _, candidate := range e.Candidates {
+		names = append(names, candidate.Name+"/"+candidate.State)
+	}
+	return fmt.Sprintf("%s: %q corresponde a %s; informe a UF, o location_id ou o cptec_code",
+		handler.ErrAmbiguousLocation, e.City, strings.Join(names, ", "))
+}
+
+func (e *AmbiguousLocationError) Unwrap() error {
+	return handler.ErrAmbiguousLocation
+}
+
+// RemoveAccents remove os acentos do texto, como exige a busca de cidades
+// do CPTEC.
+func RemoveAccents(value string) string {
+	return strings.Map(func(r rune) rune {
+		if runes.In(unicode.Mn).Contains(r) {
+			return -1
+		}
+		return r
+	}, norm.NFD.String(value))
+}
+
+func normalizeLocationName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(RemoveAccents(name)), " "))
+}
+
+// MatchLocationName compara o nome da cidade com o texto pesquisado. Retorna
+// vazio quando o nome não contém o texto.
+func MatchLocationName(name, query string) LocationMatch {
+	if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(query)) {
+		return MatchExact
+	}
+
+	normalizedName, normalizedQuery := normalizeLocationName(name), normalizeLocationName(query)
+	switch {
+	case normalizedQuery == "":
+		return ""
+	case normalizedName == normalizedQuery:
+		return MatchAccentInsensitive
+	case strings.HasPrefix(normalizedName, normalizedQuery):
+		return MatchPrefix
+	case strings.Contains(normalizedName, normalizedQuery):
+		return MatchPartial
+	default:
+		return ""
+	}
+}
+
+// RankLocations filtra as cidades pela UF e pelo nome pesquisado e as
+// ordena da correspondência mais exata para a mais parcial.
+func RankLocations(locations []Location, query, state string) []RankedLocation {
+	return rankLocations(locations, query, state, false)
+}
+
+// RankApproximateLocations ordena as cidades como RankLocations, mas mantém
+// as que não contêm o texto pesquisado (erros de digitação encontrados pela
+// busca por similaridade) como correspondência aproximada, na ordem
+// recebida.
+func RankApproximateLocations(locations []Location, query, state string) []RankedLocation {
+	return rankLocations(locations, query, state, true)
+}
+
+func rankLocations(locations []Location, query, state string, approximate bool) []RankedLocation {
+	ranked := []RankedLocation{}
+	for _, location := range locations {
+		if state != "" && !strings.EqualFold(location.State, state) {
+			continue
+		}
+
+		match := MatchLocationName(location.Name, query)
+		if match == "" {
+			if !approximate {
+				continue
+			}
+			match = MatchApproximate
+		}
+		ranked = append(ranked, RankedLocation{Location: location, Match: match})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Match != ranked[j].Match {
+			return locationMatchRank[ranked[i].Match] < locationMatchRank[ranked[j].Match]
+		}
+		if ranked[i].Match == MatchApproximate {
+			return false
+		}
+		if ranked[i].Name != ranked[j].Name {
+			return ranked[i].Name < ranked[j].Name
+		}
+		return ranked[i].State < ranked[j].State
+	})
+
+	return ranked
+}
+
+// BestLocation escolhe a cidade de maior relevância. Quando mais de uma
+// cidade empata na melhor correspondência, ou quando só há correspondências
+// aproximadas, retorna AmbiguousLocationError com as candidatas.
+func BestLocation(ranked []RankedLocation, query string) (*Location, error) {
+	if len(ranked) == 0 {
+		return nil, fmt.Errorf("%w: localização %q", handler.ErrNotFound, query)
+	}
+	if ranked[0].Match == MatchApproximate {
+		return nil, &AmbiguousLocationError{City: query, Candidates: ranked}
+	}
+
+	best := ranked[:1]
+	for _, candidate := range ranked[1:] {
+		if candidate.Match != ranked[0].Match {
+			break
+		}
+		best = append(best, candidate)
+	}
+
+	if len(best) > 1 {
+		return nil, &AmbiguousLocationError{City: query, Candidates: best}
+	}
+
+	location := best[0].Location
+	return &location, nil
+}
@@ -0,0 +1,84 @@
+package entity_test
+
+import (
+	"testing"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchLocationName(t *testing.T) {
+	tests := []struct {
+		name     string
+		city     string
+		query    string
+		expected entity.LocationMatch
+	}{
+		{"nome idêntico", "São Paulo", "são paulo", entity.MatchExact},
+		{"sem acentos", "São Paulo", "Sao  Paulo", entity.MatchAccentInsensitive},
+		{"prefixo", "São Paulo de Olivença", "sao paulo", entity.MatchPrefix},
+		{"parte do nome", "Balneário Camboriú", "camboriu", entity.MatchPartial},
+		{"outra cidade", "Campinas", "Ubatuba", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, entity.MatchLocationName(tt.city, tt.query))
+		})
+	}
+}
+
+func TestRankLocations(t *testing.T) {
+	locations := []entity.Location{
+		{Name: "Santa Maria de Jetibá", State: "ES"},
+		{Name: "Santa Maria", State: "RS"},
+		{Name: "Campinas", State: "SP"},
+		{Name: "Santa Maria", State: "RN"},
+	}
+
+	ranked := entity.RankLocations(locations, "Santa Maria", "")
+	assert.Len(t, ranked, 3)
+	assert.Equal(t, "RN", ranked[0].State)
+	assert.Equal(t, "RS", ranked[1].State)
+	assert.Equal(t, entity.MatchPrefix, ranked[2].Match)
+
+	byState := entity.RankLocations(locations, "Santa Maria", "rs")
+	assert.Len(t, byState, 1)
+	assert.Equal(t, entity.MatchExact, byState[0].Match)
+}
+
+func TestRankApproximateLocations(t *testing.T) {
+	locations := []entity.Location{
+		{Name: "Campinas", State: "SP"},
+		{Name: "Campina Grande", State: "PB"},
+		{Name: "Campina Grande do Sul", State: "PR"},
+	}
+
+	ranked := entity.RankApproximateLocations(locations, "Campina", "")
+	assert.Len(t, ranked, 3)
+	assert.Equal(t, "PB", ranked[0].State)
+	assert.Equal(t, entity.MatchPrefix, ranked[2].Match)
+
+	typo := entity.RankApproximateLocations(locations, "Campinsa", "SP")
+	assert.Len(t, typo, 1)
+	assert.Equal(t, entity.MatchApproximate, typo[0].Match)
+	assert.Len(t, entity.RankLocations(locations, "Campinsa", "SP"), 0)
+}
+
+func TestBestLocation(t *testing.T) {
+	campinas := entity.Location{Name: "Campinas", State: "SP"}
+
+	location, err := entity.BestLocation([]entity.RankedLocation{{Location: campinas, Match: entity.MatchPrefix}}, "Campina")
+	assert.NoError(t, err)
+	assert.Equal(t, "Campinas", location.Name)
+
+	_, err = entity.BestLocation([]entity.RankedLocation{{Location: campinas, Match: entity.MatchApproximate}}, "Campinsa")
+	var ambiguous *entity.AmbiguousLocationError
+	if assert.ErrorAs(t, err, &ambiguous) {
+		assert.Len(t, ambiguous.Candidates, 1)
+	}
+
+	_, err = entity.BestLocation(nil, "Atlântida")
+	assert.ErrorIs(t, err, handler.ErrNotFound)
+}
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"fmt"
+	"net/url"
 	"time"
 	handler "weather-notification/internal/domain/error_handler"
 
@@ -9,34 +11,113 @@ import (
 
 type NotificationStatus string
 type Frequency string
+type GlobalNotificationRunStatus string
 
 const (
-	StatusPending   NotificationStatus = "PENDENTE"
-	StatusSent      NotificationStatus = "ENVIADA"
-	StatusFailed    NotificationStatus = "FALHA"
-	FrequencyDaily  Frequency          = "DIARIA"
-	FrequencyWeekly Frequency          = "SEMANAL"
+	StatusPending    NotificationStatus = "PENDENTE"
+	StatusProcessing NotificationStatus = "EM_PROCESSAMENTO"
+	StatusSent       NotificationStatus = "ENVIADA"
+	StatusFailed     NotificationStatus = "FALHA"
+	StatusCanceled   NotificationStatus = "CANCELADA"
+	StatusExpired    NotificationStatus = "EXPIRADA"
+	FrequencyDaily   Frequency          = "DIARIA"
+	FrequencyWeekly  Frequency          = "SEMANAL"
 )
 
+// notificationTransitions lista os status de destino permitidos a partir de
+// cada status. ENVIADA, CANCELADA e EXPIRADA são finais; uma notificação em
+// FALHA volta a ser processada pelas retentativas da fila.
+var notificationTransitions = map[NotificationStatus][]NotificationStatus{
+	StatusPending:    {StatusProcessing, StatusCanceled, StatusExpired},
+	StatusProcessing: {StatusSent, StatusFailed, StatusExpired},
+	StatusFailed:     {StatusProcessing, StatusCanceled, StatusExpired},
+}
+
+const (
+	RunExecuted GlobalNotificationRunStatus = "EXECUTADA"
+	RunSkipped  GlobalNotificationRunStatus = "IGNORADA"
+)
+
+const maxCatchUpWindow = 7 * 24 * time.Hour
+
 type GlobalNotification struct {
 	ID            uuid.UUID  `json:"id"`
 	TimeOfDay     time.Time  `json:"time_of_day"`
 	Frequency     Frequency  `json:"frequency"`
 	Active        bool       `json:"active"`
 	LastExecution *time.Time `json:"last_execution"`
-	CreatedAt     time.Time  `json:"created_at"`
+	// Executions guarda a última execução por fuso horário (IANA), já que o
+	// horário configurado é aplicado ao relógio local de cada usuário.
+	Executions map[string]time.Time `json:"executions,omitempty"`
+	CreatedAt  time.Time            `json:"created_at"`
+}
+
+type GlobalNotificationRun struct {
+	ID                   uuid.UUID                   `json:"id"`
+	GlobalNotificationID uuid.UUID                   `json:"global_notification_id"`
+	Timezone             string                      `json:"timezone"`
+	ScheduledFor         time.Time                   `json:"scheduled_for"`
+	Status               GlobalNotificationRunStatus `json:"status"`
+	Reason               string                      `json:"reason,omitempty"`
+	CreatedAt            time.Time                   `json:"created_at"`
+}
+
+func NewGlobalNotificationRun(globalNotificationID uuid.UUID, timezone string, scheduledFor time.Time, status GlobalNotificationRunStatus, reason string) *GlobalNotificationRun {
+	return &GlobalNotificationRun{
+		ID:                   uuid.New(),
+		GlobalNotificationID: globalNotificationID,
+		Timezone:             timezone,
+		ScheduledFor:         scheduledFor.UTC(),
+		Status:               status,
+		Reason:               reason,
+		CreatedAt:            time.Now().UTC(),
+	}
 }
 
 type Notification struct {
-	ID           uuid.UUID                 `json:"id"`
-	UserID       uuid.UUID                 `json:"user_id"`
-	LocationID   uuid.UUID                 `json:"location_id"`
-	Content      WeatherForecastCollection `json:"content"`
-	Status       NotificationStatus        `json:"status"`
-	ScheduledFor time.Time                 `json:"scheduled_for"`
-	SentAt       *time.Time                `json:"sent_at"`
-	CreatedAt    time.Time                 `json:"created_at"`
-	UpdatedAt    time.Time                 `json:"updated_at"`
+	ID         uuid.UUID                 `json:"id"`
+	UserID     uuid.UUID                 `json:"user_id"`
+	LocationID uuid.UUID                 `json:"location_id"`
+	Content    WeatherForecastCollection `json:"content"`
+	// LocationLabel é o rótulo dado pelo usuário à localização, quando a
+	// notificação vem de uma localização salva. AdditionalLocations traz as
+	// demais localizações de uma notificação combinada.
+	LocationLabel       string             `json:"location_label,omitempty"`
+	AdditionalLocations []LocationForecast `json:"additional_locations,omitempty"`
+	Message             string             `json:"message,omitempty"`
+	Status              NotificationStatus `json:"status"`
+	ScheduledFor        time.Time          `json:"scheduled_for"`
+	SentAt              *time.Time         `json:"sent_at"`
+	// Attempts conta as tentativas de envio; LastError e LastHTTPStatus
+	// descrevem a última falha e são limpos quando o envio é concluído.
+	Attempts       int        `json:"attempts"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastHTTPStatus int        `json:"last_http_status,omitempty"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// LocationForecast é a previsão de uma das localizações incluídas em uma
+// notificação.
+type LocationForecast struct {
+	LocationID uuid.UUID                 `json:"location_id"`
+	Label      string                    `json:"label,omitempty"`
+	Content    WeatherForecastCollection `json:"content"`
+}
+
+// Title identifica a localização na mensagem, como "Casa de praia - Ubatuba/SP".
+func (l LocationForecast) Title() string {
+	city, err := url.QueryUnescape(l.Content.Nome)
+	if err != nil {
+		city = l.Content.Nome
+	}
+
+	title := city + "/" + l.Content.UF
+	if l.Label != "" {
+		title = l.Label + " - " + title
+	}
+	return title
 }
 
 func NewNotification(userID, locationID uuid.UUID, content WeatherForecastCollection, scheduledFor time.Time) (*Notification, error) {
@@ -47,8 +128,8 @@ func NewNotification(userID, locationID uuid.UUID, content WeatherForecastCollec
 		return nil, handler.ErrInvalidLocationID
 	}
 
-	now := time.Now().Truncate(time.Second)
-	scheduledFor = scheduledFor.Truncate(time.Second)
+	now := time.Now().UTC().Truncate(time.Second)
+	scheduledFor = scheduledFor.UTC().Truncate(time.Second)
 
 	if scheduledFor.Before(now) {
 		return nil, handler.ErrInvalidScheduleTime
@@ -66,29 +147,72 @@ func NewNotification(userID, locationID uuid.UUID, content WeatherForecastCollec
 	}, nil
 }
 
-func (g *GlobalNotification) ShouldExecute(now time.Time) bool {
+// DueRuns calcula as ocorrências agendadas desde a última execução no fuso
+// informado. A ocorrência mais recente é devolvida em due quando ainda está
+// dentro do período de tolerância; as demais são devolvidas como perdidas.
+// Um fuso ainda sem execução registrada, como os migrados do modelo com uma
+// única last_execution ou o de um usuário novo, parte da última execução
+// geral, para não recuperar dias anteriores a ela.
+func (g *GlobalNotification) DueRuns(now time.Time, location *time.Location, gracePeriod time.Duration) (due *time.Time, missed []time.Time) {
 	if !g.Active {
-		return false
+		return nil, nil
+	}
+
+	from := g.CreatedAt
+	if lastExecution, ok := g.Executions[location.String()]; ok {
+		from = lastExecution
+	} else if g.LastExecution != nil {
+		from = *g.LastExecution
+	}
+	if limit := now.Add(-maxCatchUpWindow); from.Before(limit) {
+		from = limit
 	}
 
-	currentTime := now.Format("15:04")
-	scheduledTime := g.TimeOfDay.Format("15:04")
+	runs := g.runsBetween(from, now, location)
+	if len(runs) == 0 {
+		return nil, nil
+	}
 
-	if currentTime != scheduledTime {
-		return false
+	latest := runs[len(runs)-1]
+	if now.Sub(latest) > gracePeriod {
+		return nil, runs
 	}
 
-	if g.LastExecution == nil {
-		return true
+	return &latest, runs[:len(runs)-1]
+}
+
+func (g *GlobalNotification) runsBetween(from, to time.Time, location *time.Location) []time.Time {
+	start := from.In(location)
+	weekday := g.CreatedAt.In(location).Weekday()
+
+	var runs []time.Time
+	for day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, location); !day.After(to); day = day.AddDate(0, 0, 1) {
+		if g.Frequency == FrequencyWeekly && day.Weekday() != weekday {
+			continue
+		}
+
+		run := time.Date(day.Year(), day.Month(), day.Day(), g.TimeOfDay.Hour(), g.TimeOfDay.Minute(), 0, 0, location)
+		if run.After(from) && !run.After(to) {
+			runs = append(runs, run.UTC())
+		}
 	}
 
-	switch g.Frequency {
-	case FrequencyDaily:
-		return g.LastExecution.Day() != now.Day()
-	case FrequencyWeekly:
-		_, lastWeek := g.LastExecution.ISOWeek()
-		_, currentWeek := now.ISOWeek()
-		return lastWeek != currentWeek
+	return runs
+}
+
+func (g *GlobalNotification) MarkExecuted(location *time.Location, at time.Time) {
+	at = at.UTC()
+	if g.Executions == nil {
+		g.Executions = make(map[string]time.Time)
+	}
+	g.Executions[location.String()] = at
+	g.LastExecution = &at
+}
+
+func (s NotificationStatus) IsValid() bool {
+	switch s {
+	case StatusPending, StatusProcessing, StatusSent, StatusFailed, StatusCanceled, StatusExpired:
+		return true
 	default:
 		return false
 	}
@@ -98,24 +222,114 @@ func (n *Notification) IsReadyToSend() bool {
 	return n.Status == StatusPending && time.Now().After(n.ScheduledFor)
 }
 
-func (n *Notification) MarkAsSent() {
-	now := time.Now()
-	n.Status = StatusSent
-	n.SentAt = &now
-	n.UpdatedAt = now
+func (n *Notification) CanTransitionTo(status NotificationStatus) bool {
+	for _, allowed := range notificationTransitions[n.Status] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo altera o status respeitando as transições permitidas e
+// retorna o evento que registra a mudança no histórico.
+func (n *Notification) TransitionTo(status NotificationStatus, actor string, cause error) (*NotificationEvent, error) {
+	if !n.CanTransitionTo(status) {
+		return nil, fmt.Errorf("%w: %s para %s", handler.ErrInvalidStatusTransition, n.Status, status)
+	}
+
+	event := NewNotificationEvent(n.ID, n.Status, status, actor, cause)
+
+	n.Status = status
+	n.UpdatedAt = event.CreatedAt
+
+	switch status {
+	case StatusProcessing:
+		attemptAt := event.CreatedAt
+		n.Attempts++
+		n.LastAttemptAt = &attemptAt
+	case StatusSent:
+		sentAt := event.CreatedAt
+		n.SentAt = &sentAt
+		n.LastError = ""
+		n.LastHTTPStatus = 0
+	case StatusFailed:
+		n.LastError = event.Error
+		n.LastHTTPStatus = handler.StatusCode(cause)
+	}
+
+	return event, nil
+}
+
+func (n *Notification) MarkAsSent(actor string) (*NotificationEvent, error) {
+	return n.TransitionTo(StatusSent, actor, nil)
+}
+
+func (n *Notification) MarkAsFailed(actor string, cause error) (*NotificationEvent, error) {
+	return n.TransitionTo(StatusFailed, actor, cause)
+}
+
+func (n *Notification) Cancel(actor string) (*NotificationEvent, error) {
+	return n.TransitionTo(StatusCanceled, actor, nil)
+}
+
+// Reschedule altera o horário de uma notificação ainda pendente. O status
+// não muda, mas a alteração fica registrada no histórico.
+func (n *Notification) Reschedule(scheduledFor time.Time, actor string) (*NotificationEvent, error) {
+	if n.Status != StatusPending {
+		return nil, fmt.Errorf("%w: %s não pode ser reagendada", handler.ErrInvalidStatusTransition, n.Status)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	scheduledFor = scheduledFor.UTC().Truncate(time.Second)
+	if scheduledFor.Before(now) {
+		return nil, handler.ErrInvalidScheduleTime
+	}
+
+	event := NewNotificationEvent(n.ID, n.Status, n.Status, actor, nil)
+	event.Details = fmt.Sprintf("reagendada de %s para %s", n.ScheduledFor.Format(time.RFC3339), scheduledFor.Format(time.RFC3339))
+
+	n.ScheduledFor = scheduledFor
+	n.UpdatedAt = event.CreatedAt
+
+	return event, nil
+}
+
+// IsExpired indica se a notificação passou tempo demais além do horário
+// agendado para que a previsão ainda seja útil.
+func (n *Notification) IsExpired(now time.Time, maxDelay time.Duration) bool {
+	return maxDelay > 0 && now.Sub(n.ScheduledFor) > maxDelay
 }
 
-func (n *Notification) MarkAsFailed() {
-	n.Status = StatusFailed
-	n.UpdatedAt = time.Now()
+// Locations retorna a previsão da localização da notificação seguida das
+// localizações adicionais de uma notificação combinada.
+func (n *Notification) Locations() []LocationForecast {
+	locations := []LocationForecast{{
+		LocationID: n.LocationID,
+		Label:      n.LocationLabel,
+		Content:    n.Content,
+	}}
+	return append(locations, n.AdditionalLocations...)
 }
 
 func (n *Notification) FormatNotificationContent() string {
-	forecasts := n.Content.GetNext4Days()
 	result := "Previsão do tempo para os próximos dias:\n\n"
+	if n.Message != "" {
+		result = n.Message + "\n\n" + result
+	}
+
+	locations := n.Locations()
+	for i, location := range locations {
+		if len(locations) > 1 || location.Label != "" {
+			if i > 0 {
+				result += "\n"
+			}
+			result += location.Title() + ":\n"
+		}
 
-	for _, forecast := range forecasts {
-		result += forecast.AsNotificationText() + "\n"
+		for _, forecast := range location.Content.Upcoming() {
+			result += forecast.AsNotificationText() + "\n"
+		}
 	}
 
 	return result
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ActorAPI    = "api"
+	ActorWorker = "worker"
+)
+
+// NotificationEvent registra uma mudança no ciclo de vida da notificação.
+type NotificationEvent struct {
+	ID             uuid.UUID          `json:"id"`
+	NotificationID uuid.UUID          `json:"notification_id"`
+	FromStatus     NotificationStatus `json:"from_status"`
+	ToStatus       NotificationStatus `json:"to_status"`
+	Actor          string             `json:"actor"`
+	Error          string             `json:"error,omitempty"`
+	Details        string             `json:"details,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+func NewNotificationEvent(notificationID uuid.UUID, from, to NotificationStatus, actor string, cause error) *NotificationEvent {
+	event := &NotificationEvent{
+		ID:             uuid.New(),
+		NotificationID: notificationID,
+		FromStatus:     from,
+		ToStatus:       to,
+		Actor:          actor,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if cause != nil {
+		event.Error = cause.Error()
+	}
+	return event
+}
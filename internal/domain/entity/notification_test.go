@@ -0,0 +1,323 @@
+package entity_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobalNotification_DueRuns(t *testing.T) {
+	saoPaulo, _ := time.LoadLocation("America/Sao_Paulo")
+	rioBranco, _ := time.LoadLocation("America/Rio_Branco")
+	timeOfDay, _ := time.Parse("15:04", "07:00")
+	grace := 30 * time.Minute
+
+	// 10:00 UTC = 07:00 em São Paulo e 05:00 no Acre
+	run := func(day int) time.Time { return time.Date(2024, 2, day, 10, 0, 0, 0, time.UTC) }
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		location      *time.Location
+		frequency     entity.Frequency
+		createdAt     time.Time
+		lastExecution *time.Time
+		now           time.Time
+		expectedDue   *time.Time
+		expectedMiss  []time.Time
+	}{
+		{
+			name:          "horário local coincide",
+			location:      saoPaulo,
+			frequency:     entity.FrequencyDaily,
+			createdAt:     createdAt,
+			lastExecution: ptr(run(1).Add(time.Second)),
+			now:           run(2),
+			expectedDue:   ptr(run(2)),
+		},
+		{
+			name:          "horário local ainda não chegou no Acre",
+			location:      rioBranco,
+			frequency:     entity.FrequencyDaily,
+			createdAt:     createdAt,
+			lastExecution: ptr(run(1).Add(2 * time.Hour)),
+			now:           run(2),
+		},
+		{
+			name:          "já executada hoje",
+			location:      saoPaulo,
+			frequency:     entity.FrequencyDaily,
+			createdAt:     createdAt,
+			lastExecution: ptr(run(2).Add(30 * time.Second)),
+			now:           run(2).Add(5 * time.Minute),
+		},
+		{
+			name:          "reinício dentro da tolerância",
+			location:      saoPaulo,
+			frequency:     entity.FrequencyDaily,
+			createdAt:     createdAt,
+			lastExecution: ptr(run(1).Add(time.Minute)),
+			now:           run(2).Add(20 * time.Minute),
+			expectedDue:   ptr(run(2)),
+		},
+		{
+			name:          "reinício fora da tolerância",
+			location:      saoPaulo,
+			frequency:     entity.FrequencyDaily,
+			createdAt:     createdAt,
+			lastExecution: ptr(run(1).Add(time.Minute)),
+			now:           run(2).Add(2 * time.Hour),
+			expectedMiss:  []time.Time{run(2)},
+		},
+		{
+			name:          "serviço fora do ar por dias",
+			location:      saoPaulo,
+			frequency:     entity.FrequencyDaily,
+			createdAt:     createdAt,
+			lastExecution: ptr(time.Date(2024, 1, 30, 10, 1, 0, 0, time.UTC)),
+			now:           run(2).Add(10 * time.Minute),
+			expectedDue:   ptr(run(2)),
+			expectedMiss:  []time.Time{time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC), run(1)},
+		},
+		{
+			name:        "semanal no dia da criação",
+			location:    saoPaulo,
+			frequency:   entity.FrequencyWeekly,
+			createdAt:   time.Date(2024, 1, 26, 12, 0, 0, 0, time.UTC),
+			now:         run(2),
+			expectedDue: ptr(run(2)),
+		},
+		{
+			name:          "semanal perdida no dia da criação",
+			location:      saoPaulo,
+			frequency:     entity.FrequencyWeekly,
+			createdAt:     time.Date(2024, 1, 25, 12, 0, 0, 0, time.UTC),
+			lastExecution: ptr(time.Date(2024, 1, 25, 12, 0, 0, 0, time.UTC)),
+			now:           run(2),
+			expectedMiss:  []time.Time{run(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &entity.GlobalNotification{
+				ID:        uuid.New(),
+				TimeOfDay: timeOfDay,
+				Frequency: tt.frequency,
+				Active:    true,
+				CreatedAt: tt.createdAt,
+			}
+			if tt.lastExecution != nil {
+				g.MarkExecuted(tt.location, *tt.lastExecution)
+			}
+
+			due, missed := g.DueRuns(tt.now, tt.location, grace)
+			assert.Equal(t, tt.expectedDue, due)
+			assert.ElementsMatch(t, tt.expectedMiss, missed)
+		})
+	}
+}
+
+func TestGlobalNotification_DueRunsPerTimezone(t *testing.T) {
+	saoPaulo, _ := time.LoadLocation("America/Sao_Paulo")
+	rioBranco, _ := time.LoadLocation("America/Rio_Branco")
+	timeOfDay, _ := time.Parse("15:04", "07:00")
+
+	g := &entity.GlobalNotification{
+		ID:        uuid.New(),
+		TimeOfDay: timeOfDay,
+		Frequency: entity.FrequencyDaily,
+		Active:    true,
+		CreatedAt: time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC),
+	}
+	g.MarkExecuted(saoPaulo, time.Date(2024, 2, 2, 10, 0, 0, 0, time.UTC))
+
+	due, _ := g.DueRuns(time.Date(2024, 2, 2, 12, 0, 0, 0, time.UTC), rioBranco, 30*time.Minute)
+	assert.Equal(t, ptr(time.Date(2024, 2, 2, 12, 0, 0, 0, time.UTC)), due)
+}
+
+func TestGlobalNotification_DueRunsWithoutTimezoneExecution(t *testing.T) {
+	saoPaulo, _ := time.LoadLocation("America/Sao_Paulo")
+	timeOfDay, _ := time.Parse("15:04", "07:00")
+	lastExecution := time.Date(2024, 2, 9, 10, 0, 0, 0, time.UTC)
+
+	// Registro migrado do modelo com uma única last_execution
+	g := &entity.GlobalNotification{
+		ID:            uuid.New(),
+		TimeOfDay:     timeOfDay,
+		Frequency:     entity.FrequencyDaily,
+		Active:        true,
+		LastExecution: &lastExecution,
+		Executions:    map[string]time.Time{},
+		CreatedAt:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	due, missed := g.DueRuns(time.Date(2024, 2, 9, 12, 0, 0, 0, time.UTC), saoPaulo, 30*time.Minute)
+	assert.Nil(t, due)
+	assert.Empty(t, missed)
+
+	due, missed = g.DueRuns(time.Date(2024, 2, 10, 10, 0, 0, 0, time.UTC), saoPaulo, 30*time.Minute)
+	assert.Equal(t, ptr(time.Date(2024, 2, 10, 10, 0, 0, 0, time.UTC)), due)
+	assert.Empty(t, missed)
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestGlobalNotification_MarkExecuted(t *testing.T) {
+	saoPaulo, _ := time.LoadLocation("America/Sao_Paulo")
+	g := &entity.GlobalNotification{ID: uuid.New(), Active: true}
+
+	at := time.Date(2024, 2, 2, 7, 0, 0, 0, saoPaulo)
+	g.MarkExecuted(saoPaulo, at)
+
+	assert.Equal(t, at.UTC(), g.Executions["America/Sao_Paulo"])
+	assert.Equal(t, time.UTC, g.LastExecution.Location())
+}
+
+func TestNotification_TransitionTo(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    entity.NotificationStatus
+		to      entity.NotificationStatus
+		allowed bool
+	}{
+		{"pendente para em processamento", entity.StatusPending, entity.StatusProcessing, true},
+		{"pendente para cancelada", entity.StatusPending, entity.StatusCanceled, true},
+		{"pendente para enviada", entity.StatusPending, entity.StatusSent, false},
+		{"em processamento para enviada", entity.StatusProcessing, entity.StatusSent, true},
+		{"em processamento para falha", entity.StatusProcessing, entity.StatusFailed, true},
+		{"em processamento para cancelada", entity.StatusProcessing, entity.StatusCanceled, false},
+		{"falha para em processamento", entity.StatusFailed, entity.StatusProcessing, true},
+		{"falha para cancelada", entity.StatusFailed, entity.StatusCanceled, true},
+		{"enviada é final", entity.StatusSent, entity.StatusProcessing, false},
+		{"cancelada é final", entity.StatusCanceled, entity.StatusPending, false},
+		{"expirada é final", entity.StatusExpired, entity.StatusProcessing, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notification := &entity.Notification{ID: uuid.New(), Status: tt.from}
+
+			event, err := notification.TransitionTo(tt.to, entity.ActorWorker, errors.New("timeout"))
+			if !tt.allowed {
+				assert.ErrorIs(t, err, handler.ErrInvalidStatusTransition)
+				assert.Nil(t, event)
+				assert.Equal(t, tt.from, notification.Status)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.to, notification.Status)
+			assert.Equal(t, notification.ID, event.NotificationID)
+			assert.Equal(t, tt.from, event.FromStatus)
+			assert.Equal(t, tt.to, event.ToStatus)
+			assert.Equal(t, entity.ActorWorker, event.Actor)
+			assert.Equal(t, "timeout", event.Error)
+			assert.Equal(t, tt.to == entity.StatusSent, notification.SentAt != nil)
+		})
+	}
+}
+
+func TestNotification_Reschedule(t *testing.T) {
+	scheduledFor := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	t.Run("reagenda notificação pendente", func(t *testing.T) {
+		notification := &entity.Notification{ID: uuid.New(), Status: entity.StatusPending, ScheduledFor: time.Now()}
+
+		event, err := notification.Reschedule(scheduledFor, entity.ActorAPI)
+		assert.NoError(t, err)
+		assert.Equal(t, scheduledFor, notification.ScheduledFor)
+		assert.Equal(t, entity.StatusPending, event.FromStatus)
+		assert.Equal(t, entity.StatusPending, event.ToStatus)
+		assert.Contains(t, event.Details, "reagendada")
+	})
+
+	t.Run("horário no passado", func(t *testing.T) {
+		notification := &entity.Notification{ID: uuid.New(), Status: entity.StatusPending}
+
+		_, err := notification.Reschedule(time.Now().Add(-time.Hour), entity.ActorAPI)
+		assert.ErrorIs(t, err, handler.ErrInvalidScheduleTime)
+	})
+
+	t.Run("notificação já enviada", func(t *testing.T) {
+		notification := &entity.Notification{ID: uuid.New(), Status: entity.StatusSent}
+
+		_, err := notification.Reschedule(scheduledFor, entity.ActorAPI)
+		assert.ErrorIs(t, err, handler.ErrInvalidStatusTransition)
+	})
+}
+
+func TestNotification_IsExpired(t *testing.T) {
+	now := time.Now()
+	notification := &entity.Notification{ScheduledFor: now.Add(-2 * time.Hour)}
+
+	assert.True(t, notification.IsExpired(now, time.Hour))
+	assert.False(t, notification.IsExpired(now, 3*time.Hour))
+	assert.False(t, notification.IsExpired(now, 0))
+}
+
+func TestNotification_RecordsAttempts(t *testing.T) {
+	notification := &entity.Notification{ID: uuid.New(), Status: entity.StatusPending}
+
+	_, err := notification.TransitionTo(entity.StatusProcessing, entity.ActorWorker, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, notification.Attempts)
+	assert.NotNil(t, notification.LastAttemptAt)
+
+	cause := fmt.Errorf("WEBHOOK: %w", &handler.StatusCodeError{Message: "erro ao enviar notificação", StatusCode: 502})
+	_, err = notification.MarkAsFailed(entity.ActorWorker, cause)
+	assert.NoError(t, err)
+	assert.Equal(t, cause.Error(), notification.LastError)
+	assert.Equal(t, 502, notification.LastHTTPStatus)
+
+	_, err = notification.TransitionTo(entity.StatusProcessing, entity.ActorWorker, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, notification.Attempts)
+
+	_, err = notification.MarkAsSent(entity.ActorWorker)
+	assert.NoError(t, err)
+	assert.Empty(t, notification.LastError)
+	assert.Zero(t, notification.LastHTTPStatus)
+	assert.Equal(t, 2, notification.Attempts)
+}
+
+func TestNotification_FormatCombinedLocations(t *testing.T) {
+	day := time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC)
+	forecast := func(nome string) entity.WeatherForecastCollection {
+		return entity.WeatherForecastCollection{
+			Nome:      nome,
+			UF:        "SP",
+			Forecasts: []entity.WeatherForecast{{Date: day, MinTemp: 18, MaxTemp: 28, Forecast: "pn"}},
+		}
+	}
+
+	t.Run("localização única sem rótulo", func(t *testing.T) {
+		notification := &entity.Notification{Content: forecast("Campinas")}
+
+		content := notification.FormatNotificationContent()
+		assert.NotContains(t, content, "Campinas/SP")
+	})
+
+	t.Run("notificação combinada identifica cada localização", func(t *testing.T) {
+		notification := &entity.Notification{
+			Content:       forecast("Campinas"),
+			LocationLabel: "casa",
+			AdditionalLocations: []entity.LocationForecast{
+				{LocationID: uuid.New(), Label: "casa de praia", Content: forecast("Ubatuba")},
+			},
+		}
+
+		content := notification.FormatNotificationContent()
+		assert.Contains(t, content, "casa - Campinas/SP:\n")
+		assert.Contains(t, content, "\ncasa de praia - Ubatuba/SP:\n")
+		assert.Len(t, notification.Locations(), 2)
+	})
+}
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const EventNotificationScheduled = "notification.scheduled"
+
+type OutboxMessage struct {
+	ID           uuid.UUID       `json:"id"`
+	AggregateID  uuid.UUID       `json:"aggregate_id"`
+	EventType    string          `json:"event_type"`
+	Payload      json.RawMessage `json:"payload"`
+	Attempts     int             `json:"attempts"`
+	LastError    string          `json:"last_error,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	DispatchedAt *time.Time      `json:"dispatched_at"`
+}
+
+func NewNotificationOutboxMessage(notification *Notification) (*OutboxMessage, error) {
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxMessage{
+		ID:          uuid.New(),
+		AggregateID: notification.ID,
+		EventType:   EventNotificationScheduled,
+		Payload:     payload,
+		CreatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+func (m *OutboxMessage) Notification() (*Notification, error) {
+	var notification Notification
+	if err := json.Unmarshal(m.Payload, &notification); err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
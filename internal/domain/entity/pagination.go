@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// PageRequest descreve a página desejada de uma listagem. Sort é o campo de
+// ordenação, com prefixo "-" para ordem decrescente, e Cursor é o valor
+// devolvido em NextCursor pela página anterior.
+type PageRequest struct {
+	Limit  int
+	Cursor string
+	Sort   string
+}
+
+func NewPageRequest(limit int, cursor, sort string) PageRequest {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	return PageRequest{
+		Limit:  limit,
+		Cursor: cursor,
+		Sort:   strings.TrimSpace(sort),
+	}
+}
+
+// SortField retorna o campo de ordenação e se a ordem é decrescente,
+// usando defaultSort quando nenhum campo foi informado.
+func (p PageRequest) SortField(defaultSort string) (string, bool) {
+	sort := p.Sort
+	if sort == "" {
+		sort = defaultSort
+	}
+	if field, ok := strings.CutPrefix(sort, "-"); ok {
+		return field, true
+	}
+	return sort, false
+}
+
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int    `json:"total"`
+}
+
+// Cursor identifica o último item de uma página pelo valor do campo de
+// ordenação e pelo ID, que desempata valores repetidos.
+type Cursor struct {
+	Sort  string    `json:"s"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor lê um cursor gerado por Encode. O cursor só é válido para a
+// mesma ordenação em que foi gerado.
+func DecodeCursor(value, sort string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, handler.ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil || cursor.Sort != sort {
+		return nil, handler.ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+type UserFilter struct {
+	OptOut        *bool
+	LocationID    *uuid.UUID
+	CreatedFrom   *time.Time
+	CreatedBefore *time.Time
+}
+
+type NotificationFilter struct {
+	UserID          uuid.UUID
+	Status          []NotificationStatus
+	LocationID      *uuid.UUID
+	ScheduledFrom   *time.Time
+	ScheduledBefore *time.Time
+}
+
+type GlobalNotificationFilter struct {
+	Active    *bool
+	Frequency Frequency
+}
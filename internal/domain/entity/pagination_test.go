@@ -0,0 +1,65 @@
+package entity_test
+
+import (
+	"testing"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPageRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int
+		sort          string
+		expectedLimit int
+		expectedField string
+		expectedDesc  bool
+	}{
+		{"limite padrão", 0, "", entity.DefaultPageSize, "created_at", false},
+		{"limite acima do máximo", 500, "name", entity.MaxPageSize, "name", false},
+		{"ordem decrescente", 10, "-scheduled_for", 10, "scheduled_for", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := entity.NewPageRequest(tt.limit, "", tt.sort)
+			field, desc := page.SortField("created_at")
+
+			assert.Equal(t, tt.expectedLimit, page.Limit)
+			assert.Equal(t, tt.expectedField, field)
+			assert.Equal(t, tt.expectedDesc, desc)
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	cursor := entity.Cursor{Sort: "-created_at", Value: "2024-02-01T10:00:00Z", ID: uuid.New()}
+
+	tests := []struct {
+		name        string
+		value       string
+		sort        string
+		expectedErr error
+	}{
+		{"cursor válido", cursor.Encode(), "-created_at", nil},
+		{"ordenação diferente", cursor.Encode(), "created_at", handler.ErrInvalidCursor},
+		{"valor adulterado", "não-é-um-cursor", "-created_at", handler.ErrInvalidCursor},
+		{"cursor sem ID", entity.Cursor{Sort: "name", Value: "Ana"}.Encode(), "name", handler.ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := entity.DecodeCursor(tt.value, tt.sort)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, cursor, *decoded)
+		})
+	}
+}
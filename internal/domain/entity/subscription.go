@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/google/uuid"
+)
+
+type Subscription struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	LocationID     uuid.UUID  `json:"location_id"`
+	CronExpression string     `json:"cron_expression"`
+	Timezone       string     `json:"timezone"`
+	Active         bool       `json:"active"`
+	NextRunAt      time.Time  `json:"next_run_at"`
+	LastRunAt      *time.Time `json:"last_run_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func NewSubscription(userID, locationID uuid.UUID, cronExpression, timezone string) (*Subscription, error) {
+	if userID == uuid.Nil {
+		return nil, handler.ErrInvalidUserID
+	}
+	if locationID == uuid.Nil {
+		return nil, handler.ErrInvalidLocationID
+	}
+
+	if _, err := LoadTimezone(timezone); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	subscription := &Subscription{
+		ID:         uuid.New(),
+		UserID:     userID,
+		LocationID: locationID,
+		Timezone:   timezone,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := subscription.Reschedule(cronExpression, now); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (s *Subscription) Reschedule(cronExpression string, now time.Time) error {
+	schedule, err := ParseCronExpression(cronExpression)
+	if err != nil {
+		return err
+	}
+
+	next, err := s.next(schedule, now)
+	if err != nil {
+		return err
+	}
+
+	s.CronExpression = cronExpression
+	s.NextRunAt = next
+	s.UpdatedAt = now.UTC()
+	return nil
+}
+
+func (s *Subscription) IsDue(now time.Time) bool {
+	return s.Active && !s.NextRunAt.After(now)
+}
+
+func (s *Subscription) Advance(now time.Time) error {
+	schedule, err := ParseCronExpression(s.CronExpression)
+	if err != nil {
+		return err
+	}
+
+	next, err := s.next(schedule, now)
+	if err != nil {
+		return err
+	}
+
+	last := s.NextRunAt
+	s.LastRunAt = &last
+	s.NextRunAt = next
+	s.UpdatedAt = now.UTC()
+	return nil
+}
+
+func (s *Subscription) next(schedule *CronSchedule, now time.Time) (time.Time, error) {
+	location, err := LoadTimezone(s.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := schedule.Next(now.In(location))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("%w: nenhuma execução futura", handler.ErrInvalidCronExpression)
+	}
+	return next.UTC(), nil
+}
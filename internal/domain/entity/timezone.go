@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	handler "weather-notification/internal/domain/error_handler"
+)
+
+const DefaultTimezone = "America/Sao_Paulo"
+
+var stateTimezones = map[string]string{
+	"AC": "America/Rio_Branco",
+	"AM": "America/Manaus",
+	"RR": "America/Boa_Vista",
+	"RO": "America/Porto_Velho",
+	"MT": "America/Cuiaba",
+	"MS": "America/Campo_Grande",
+	"PA": "America/Belem",
+	"AP": "America/Belem",
+	"TO": "America/Araguaina",
+	"MA": "America/Fortaleza",
+	"PI": "America/Fortaleza",
+	"CE": "America/Fortaleza",
+	"RN": "America/Fortaleza",
+	"PB": "America/Fortaleza",
+	"PE": "America/Recife",
+	"AL": "America/Maceio",
+	"SE": "America/Maceio",
+	"BA": "America/Bahia",
+}
+
+func TimezoneForLocation(name, state string) string {
+	if strings.EqualFold(name, "Fernando de Noronha") {
+		return "America/Noronha"
+	}
+	if timezone, ok := stateTimezones[strings.ToUpper(state)]; ok {
+		return timezone
+	}
+	return DefaultTimezone
+}
+
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		name = DefaultTimezone
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", handler.ErrInvalidTimezone, name)
+	}
+	return location, nil
+}
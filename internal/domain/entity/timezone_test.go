@@ -0,0 +1,54 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimezoneForLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		city     string
+		state    string
+		expected string
+	}{
+		{"São Paulo", "Sao Paulo", "SP", "America/Sao_Paulo"},
+		{"Acre", "Rio Branco", "AC", "America/Rio_Branco"},
+		{"Amazonas", "Manaus", "AM", "America/Manaus"},
+		{"Fernando de Noronha", "Fernando de Noronha", "PE", "America/Noronha"},
+		{"Pernambuco continental", "Recife", "PE", "America/Recife"},
+		{"estado desconhecido", "Cidade", "XX", entity.DefaultTimezone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, entity.TimezoneForLocation(tt.city, tt.state))
+		})
+	}
+}
+
+func TestLoadTimezone(t *testing.T) {
+	location, err := entity.LoadTimezone("")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.DefaultTimezone, location.String())
+
+	_, err = entity.LoadTimezone("America/Atlantida")
+	assert.ErrorIs(t, err, handler.ErrInvalidTimezone)
+}
+
+func TestSubscription_NextRunInUserTimezone(t *testing.T) {
+	subscription, err := entity.NewSubscription(uuid.New(), uuid.New(), "30 6 * * *", "America/Manaus")
+	assert.NoError(t, err)
+
+	manaus, _ := time.LoadLocation("America/Manaus")
+	next := subscription.NextRunAt.In(manaus)
+
+	assert.Equal(t, time.UTC, subscription.NextRunAt.Location())
+	assert.Equal(t, 6, next.Hour())
+	assert.Equal(t, 30, next.Minute())
+}
@@ -8,13 +8,21 @@ import (
 )
 
 type User struct {
-	ID         uuid.UUID `json:"id"`
-	LocationID uuid.UUID `json:"location_id"`
-	Name       string    `json:"name"`
-	Email      string    `json:"email"`
-	OptOut     bool      `json:"opt_out"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         uuid.UUID     `json:"id"`
+	LocationID uuid.UUID     `json:"location_id"`
+	Name       string        `json:"name"`
+	Email      string        `json:"email"`
+	OptOut     bool          `json:"opt_out"`
+	Channels   []UserChannel `json:"channels"`
+	Timezone   string        `json:"timezone"`
+	// NotificationMode define se as localizações salvas recebem uma
+	// notificação cada ou uma única mensagem combinada.
+	NotificationMode NotificationMode `json:"notification_mode"`
+	// ForecastDays é quantos dias de previsão as notificações do usuário
+	// cobrem, de 1 a MaxForecastDays.
+	ForecastDays int       `json:"forecast_days"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 func NewUser(name, email string, locationID uuid.UUID) (*User, error) {
@@ -24,18 +32,83 @@ func NewUser(name, email string, locationID uuid.UUID) (*User, error) {
 	if email == "" {
 		return nil, handler.ErrEmptyEmail
 	}
+	if !IsEmailAddress(email) {
+		return nil, handler.ErrInvalidEmail
+	}
 	if locationID == uuid.Nil {
 		return nil, handler.ErrInvalidLocationID
 	}
 
 	now := time.Now()
 	return &User{
-		ID:         uuid.New(),
-		Name:       name,
-		Email:      email,
-		LocationID: locationID,
-		OptOut:     false,
-		CreatedAt:  now,
-		UpdatedAt:  now,
+		ID:               uuid.New(),
+		Name:             name,
+		Email:            email,
+		LocationID:       locationID,
+		OptOut:           false,
+		Timezone:         DefaultTimezone,
+		NotificationMode: NotificationModeSeparate,
+		ForecastDays:     DefaultForecastDays,
+		CreatedAt:        now,
+		UpdatedAt:        now,
 	}, nil
 }
+
+func (u *User) EnabledChannels() []UserChannel {
+	if len(u.Channels) == 0 {
+		return []UserChannel{{Channel: ChannelWebhook, Enabled: true}}
+	}
+
+	var channels []UserChannel
+	for _, channel := range u.Channels {
+		if channel.Enabled {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+func (u *User) AddressFor(channel UserChannel) string {
+	if channel.Address == "" && channel.Channel == ChannelEmail {
+		return u.Email
+	}
+	return channel.Address
+}
+
+func (u *User) SetTimezone(timezone string) error {
+	if _, err := LoadTimezone(timezone); err != nil {
+		return err
+	}
+	u.Timezone = timezone
+	return nil
+}
+
+func (u *User) SetForecastDays(days int) error {
+	if err := ValidateForecastDays(days); err != nil {
+		return err
+	}
+	u.ForecastDays = days
+	return nil
+}
+
+func (u *User) TimeLocation() *time.Location {
+	location, err := LoadTimezone(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+func (u *User) SetNotificationMode(mode NotificationMode) error {
+	if !mode.IsValid() {
+		return handler.ErrInvalidNotificationMode
+	}
+	u.NotificationMode = mode
+	return nil
+}
+
+// CombinesLocations indica se as previsões das localizações salvas devem
+// ser enviadas em uma única notificação.
+func (u *User) CombinesLocations() bool {
+	return u.NotificationMode == NotificationModeCombined
+}
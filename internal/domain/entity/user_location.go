@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/google/uuid"
+)
+
+type NotificationMode string
+
+const (
+	NotificationModeSeparate NotificationMode = "SEPARADA"
+	NotificationModeCombined NotificationMode = "COMBINADA"
+)
+
+const (
+	PrimaryLocationLabel   = "principal"
+	maxLocationLabelLength = 100
+)
+
+// UserLocation é uma localização salva pelo usuário. A localização
+// principal espelha User.LocationID; Notify indica se a localização entra
+// nas notificações agendadas.
+type UserLocation struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	LocationID uuid.UUID `json:"location_id"`
+	Label      string    `json:"label"`
+	Primary    bool      `json:"primary"`
+	Notify     bool      `json:"notify"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func NewUserLocation(userID, locationID uuid.UUID, label string) (*UserLocation, error) {
+	if userID == uuid.Nil {
+		return nil, handler.ErrInvalidUserID
+	}
+	if locationID == uuid.Nil {
+		return nil, handler.ErrInvalidLocationID
+	}
+
+	now := time.Now().UTC()
+	location := &UserLocation{
+		ID:         uuid.New(),
+		UserID:     userID,
+		LocationID: locationID,
+		Notify:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := location.SetLabel(label); err != nil {
+		return nil, err
+	}
+
+	return location, nil
+}
+
+func (l *UserLocation) SetLabel(label string) error {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return handler.ErrEmptyLocationLabel
+	}
+	if len([]rune(label)) > maxLocationLabelLength {
+		return handler.ErrInvalidLocationLabel
+	}
+	l.Label = label
+	return nil
+}
+
+func (m NotificationMode) IsValid() bool {
+	return m == NotificationModeSeparate || m == NotificationModeCombined
+}
+
+// NotifiableLocations retorna as localizações que recebem as notificações
+// agendadas, com a principal primeiro. Usuários sem localizações salvas
+// recebem a previsão da localização do cadastro.
+func NotifiableLocations(user User, locations []UserLocation) []UserLocation {
+	if len(locations) == 0 {
+		return []UserLocation{{
+			UserID:     user.ID,
+			LocationID: user.LocationID,
+			Primary:    true,
+			Notify:     true,
+		}}
+	}
+
+	var notifiable []UserLocation
+	for _, location := range locations {
+		if !location.Notify {
+			continue
+		}
+		if location.Primary {
+			notifiable = append([]UserLocation{location}, notifiable...)
+			continue
+		}
+		notifiable = append(notifiable, location)
+	}
+	return notifiable
+}
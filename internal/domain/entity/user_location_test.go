@@ -0,0 +1,78 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUserLocation(t *testing.T) {
+	tests := []struct {
+		name        string
+		locationID  uuid.UUID
+		label       string
+		expectError error
+	}{
+		{"localização válida", uuid.New(), " casa de praia ", nil},
+		{"localização inválida", uuid.Nil, "casa", handler.ErrInvalidLocationID},
+		{"rótulo vazio", uuid.New(), "  ", handler.ErrEmptyLocationLabel},
+		{"rótulo longo demais", uuid.New(), strings.Repeat("a", 101), handler.ErrInvalidLocationLabel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location, err := entity.NewUserLocation(uuid.New(), tt.locationID, tt.label)
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, location)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "casa de praia", location.Label)
+			assert.True(t, location.Notify)
+			assert.False(t, location.Primary)
+		})
+	}
+}
+
+func TestNotifiableLocations(t *testing.T) {
+	user := entity.User{ID: uuid.New(), LocationID: uuid.New()}
+	primary := entity.UserLocation{LocationID: user.LocationID, Label: "casa", Primary: true, Notify: true}
+	beach := entity.UserLocation{LocationID: uuid.New(), Label: "casa de praia", Notify: true}
+	muted := entity.UserLocation{LocationID: uuid.New(), Label: "trabalho", Notify: false}
+
+	t.Run("sem localizações salvas usa a do cadastro", func(t *testing.T) {
+		locations := entity.NotifiableLocations(user, nil)
+		assert.Len(t, locations, 1)
+		assert.Equal(t, user.LocationID, locations[0].LocationID)
+	})
+
+	t.Run("principal primeiro e sem as desativadas", func(t *testing.T) {
+		locations := entity.NotifiableLocations(user, []entity.UserLocation{beach, muted, primary})
+		assert.Equal(t, []entity.UserLocation{primary, beach}, locations)
+	})
+
+	t.Run("todas desativadas", func(t *testing.T) {
+		primary := primary
+		primary.Notify = false
+
+		assert.Empty(t, entity.NotifiableLocations(user, []entity.UserLocation{primary, muted}))
+	})
+}
+
+func TestUser_SetNotificationMode(t *testing.T) {
+	user, err := entity.NewUser("Matheus", "matheus@exemplo.com", uuid.New())
+	assert.NoError(t, err)
+	assert.False(t, user.CombinesLocations())
+
+	assert.NoError(t, user.SetNotificationMode(entity.NotificationModeCombined))
+	assert.True(t, user.CombinesLocations())
+
+	assert.ErrorIs(t, user.SetNotificationMode("TODAS"), handler.ErrInvalidNotificationMode)
+	assert.Equal(t, entity.NotificationModeCombined, user.NotificationMode)
+}
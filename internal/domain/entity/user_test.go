@@ -42,6 +42,13 @@ func TestNewUser(t *testing.T) {
 			locationID:  validLocationID,
 			expectError: handler.ErrEmptyEmail,
 		},
+		{
+			name:        "email inválido",
+			userName:    validName,
+			userEmail:   "matheus.exemplo.com",
+			locationID:  validLocationID,
+			expectError: handler.ErrInvalidEmail,
+		},
 		{
 			name:        "localização inválida",
 			userName:    validName,
@@ -72,3 +79,22 @@ func TestNewUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUser_EnabledChannels(t *testing.T) {
+	user, err := entity.NewUser("Matheus", "matheus@exemplo.com", uuid.New())
+	assert.NoError(t, err)
+
+	channels := user.EnabledChannels()
+	assert.Len(t, channels, 1)
+	assert.Equal(t, entity.ChannelWebhook, channels[0].Channel)
+
+	user.Channels = []entity.UserChannel{
+		{Channel: entity.ChannelEmail, Enabled: true},
+		{Channel: entity.ChannelSMS, Address: "+5511999999999", Enabled: false},
+	}
+
+	channels = user.EnabledChannels()
+	assert.Len(t, channels, 1)
+	assert.Equal(t, entity.ChannelEmail, channels[0].Channel)
+	assert.Equal(t, user.Email, user.AddressFor(channels[0]))
+}
@@ -3,17 +3,19 @@ package entity
 import (
 	"fmt"
 	"time"
+	handler "weather-notification/internal/domain/error_handler"
 
 	"github.com/google/uuid"
 )
 
 type WeatherForecast struct {
-	Date     time.Time `json:"date"`
-	MinTemp  float64   `json:"min_temp"`
-	MaxTemp  float64   `json:"max_temp"`
-	Forecast string    `json:"forecast"`
-	UV       float64   `json:"uv"`
-	Wave     *WaveInfo `json:"wave,omitempty"`
+	Date      time.Time        `json:"date"`
+	MinTemp   float64          `json:"min_temp"`
+	MaxTemp   float64          `json:"max_temp"`
+	Forecast  string           `json:"forecast"`
+	Condition WeatherCondition `json:"condition"`
+	UV        float64          `json:"uv"`
+	Wave      *WaveInfo        `json:"wave,omitempty"`
 }
 
 type WaveInfo struct {
@@ -32,27 +34,159 @@ type WavePeriod struct {
 	WindDir   string  `xml:"vento_dir" json:"wind_dir"`
 }
 
+// ForecastHorizon identifica o produto de previsão do CPTEC: a previsão de 4
+// dias, a de 7 dias ou a estendida, que acrescenta 7 dias após a de 4 dias.
+type ForecastHorizon string
+
+const (
+	ForecastHorizon4Days    ForecastHorizon = "4_DIAS"
+	ForecastHorizon7Days    ForecastHorizon = "7_DIAS"
+	ForecastHorizonExtended ForecastHorizon = "ESTENDIDA"
+)
+
+const (
+	DefaultForecastDays = 4
+	MaxForecastDays     = 11
+)
+
+var forecastHorizonDays = map[ForecastHorizon]int{
+	ForecastHorizon4Days:    4,
+	ForecastHorizon7Days:    7,
+	ForecastHorizonExtended: MaxForecastDays,
+}
+
+func (h ForecastHorizon) IsValid() bool {
+	_, ok := forecastHorizonDays[h]
+	return ok
+}
+
+// Days retorna quantos dias o horizonte cobre. Previsões armazenadas antes
+// do horizonte existir são da previsão de 4 dias.
+func (h ForecastHorizon) Days() int {
+	if days, ok := forecastHorizonDays[h]; ok {
+		return days
+	}
+	return DefaultForecastDays
+}
+
+// ForecastHorizonForDays escolhe o menor horizonte que cobre os dias pedidos.
+func ForecastHorizonForDays(days int) ForecastHorizon {
+	switch {
+	case days <= ForecastHorizon4Days.Days():
+		return ForecastHorizon4Days
+	case days <= ForecastHorizon7Days.Days():
+		return ForecastHorizon7Days
+	default:
+		return ForecastHorizonExtended
+	}
+}
+
+func ValidateForecastDays(days int) error {
+	if days < 1 || days > MaxForecastDays {
+		return fmt.Errorf("%w: %d", handler.ErrInvalidForecastDays, days)
+	}
+	return nil
+}
+
 type WeatherForecastCollection struct {
 	Nome      string            `json:"nome"`
 	UF        string            `json:"uf"`
+	Horizon   ForecastHorizon   `json:"horizon"`
 	Forecasts []WeatherForecast `json:"forecasts"`
+	IssuedAt  time.Time         `json:"issued_at"`
 	UpdatedAt time.Time         `json:"updated_at"`
 }
 
+func NewWeatherForecast(date time.Time, minTemp, maxTemp float64, code string, uv float64) WeatherForecast {
+	condition := LookupCondition(code)
+
+	return WeatherForecast{
+		Date:      date,
+		MinTemp:   minTemp,
+		MaxTemp:   maxTemp,
+		Forecast:  condition.Code,
+		Condition: condition,
+		UV:        uv,
+	}
+}
+
 func NewWeatherForecastCollection(locationID uuid.UUID, nome, uf string, forecasts []WeatherForecast) *WeatherForecastCollection {
 	return &WeatherForecastCollection{
 		Nome:      nome,
 		UF:        uf,
+		Horizon:   ForecastHorizon4Days,
 		Forecasts: forecasts,
 		UpdatedAt: time.Now(),
 	}
 }
 
-func (w *WeatherForecastCollection) GetNext4Days() []WeatherForecast {
-	if len(w.Forecasts) <= 4 {
-		return w.Forecasts
+// O CPTEC emite uma previsão por dia (atualizacao): se ela foi obtida no dia da
+// emissão, expira à meia-noite mesmo que o TTL ainda não tenha vencido.
+func (w *WeatherForecastCollection) ExpiresAt(fetchedAt time.Time, ttl time.Duration) time.Time {
+	expiresAt := fetchedAt.Add(ttl)
+	if w.IssuedAt.IsZero() {
+		return expiresAt
 	}
-	return w.Forecasts[:4]
+
+	fetched := fetchedAt.In(w.IssuedAt.Location())
+	if fetched.Format("2006-01-02") != w.IssuedAt.Format("2006-01-02") {
+		return expiresAt
+	}
+
+	nextIssue := time.Date(fetched.Year(), fetched.Month(), fetched.Day()+1, 0, 0, 0, 0, fetched.Location())
+	if nextIssue.Before(expiresAt) {
+		return nextIssue
+	}
+	return expiresAt
+}
+
+// Upcoming retorna as previsões dentro do horizonte da coleção.
+func (w *WeatherForecastCollection) Upcoming() []WeatherForecast {
+	if days := w.Horizon.Days(); len(w.Forecasts) > days {
+		return w.Forecasts[:days]
+	}
+	return w.Forecasts
+}
+
+// Days retorna quantos dias de previsão a coleção traz.
+func (w *WeatherForecastCollection) Days() int {
+	return len(w.Upcoming())
+}
+
+// Limit retorna uma cópia da coleção com no máximo days dias de previsão.
+func (w *WeatherForecastCollection) Limit(days int) *WeatherForecastCollection {
+	limited := *w
+	forecasts := w.Upcoming()
+	if days > 0 && len(forecasts) > days {
+		forecasts = forecasts[:days]
+	}
+	limited.Forecasts = append([]WeatherForecast(nil), forecasts...)
+	return &limited
+}
+
+// Extend acrescenta as previsões posteriores ao último dia da coleção, como
+// as da previsão estendida do CPTEC, que começa após a previsão de 4 dias.
+func (w *WeatherForecastCollection) Extend(horizon ForecastHorizon, forecasts []WeatherForecast) {
+	var last time.Time
+	if len(w.Forecasts) > 0 {
+		last = w.Forecasts[len(w.Forecasts)-1].Date
+	}
+
+	for _, forecast := range forecasts {
+		if forecast.Date.After(last) {
+			w.Forecasts = append(w.Forecasts, forecast)
+			last = forecast.Date
+		}
+	}
+	w.Horizon = horizon
+}
+
+func (w *WeatherForecast) Description() string {
+	return LookupCondition(w.Forecast).Description
+}
+
+func (w *WeatherForecast) Icon() string {
+	return LookupCondition(w.Forecast).Icon
 }
 
 func (w *WeatherForecast) HasWaveForecast() bool {
@@ -64,10 +198,11 @@ func (w *WeatherForecast) FormatTemperature() string {
 }
 
 func (w *WeatherForecast) AsNotificationText() string {
-	text := fmt.Sprintf("%s: %s - %s",
+	text := fmt.Sprintf("%s: %s - %s %s",
 		w.Date.Format("02/01"),
 		w.FormatTemperature(),
-		w.Forecast,
+		w.Icon(),
+		w.Description(),
 	)
 
 	if w.HasWaveForecast() {
@@ -0,0 +1,122 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeatherForecastCollection_ExpiresAt(t *testing.T) {
+	issuedAt := time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		issuedAt  time.Time
+		fetchedAt time.Time
+		ttl       time.Duration
+		expected  time.Time
+	}{
+		{
+			name:      "obtida no dia da emissão expira à meia-noite",
+			issuedAt:  issuedAt,
+			fetchedAt: time.Date(2024, 2, 2, 22, 0, 0, 0, time.UTC),
+			ttl:       6 * time.Hour,
+			expected:  time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "obtida no dia da emissão respeita TTL menor",
+			issuedAt:  issuedAt,
+			fetchedAt: time.Date(2024, 2, 2, 8, 0, 0, 0, time.UTC),
+			ttl:       time.Hour,
+			expected:  time.Date(2024, 2, 2, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "emissão atrasada usa apenas o TTL",
+			issuedAt:  issuedAt,
+			fetchedAt: time.Date(2024, 2, 3, 8, 0, 0, 0, time.UTC),
+			ttl:       6 * time.Hour,
+			expected:  time.Date(2024, 2, 3, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "sem data de emissão usa apenas o TTL",
+			fetchedAt: time.Date(2024, 2, 2, 22, 0, 0, 0, time.UTC),
+			ttl:       6 * time.Hour,
+			expected:  time.Date(2024, 2, 3, 4, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast := &entity.WeatherForecastCollection{IssuedAt: tt.issuedAt}
+			assert.Equal(t, tt.expected, forecast.ExpiresAt(tt.fetchedAt, tt.ttl))
+		})
+	}
+}
+
+func TestNewWeatherForecast_Condition(t *testing.T) {
+	date := time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC)
+
+	forecast := entity.NewWeatherForecast(date, 18, 27.5, " PN ", 6)
+	assert.Equal(t, "pn", forecast.Forecast)
+	assert.Equal(t, "Parcialmente nublado", forecast.Condition.Description)
+	assert.Equal(t, "Partly cloudy", forecast.Condition.DescriptionEN)
+	assert.Equal(t, entity.SeverityLow, forecast.Condition.Severity)
+	assert.Equal(t, "02/02: 18.0°C / 27.5°C - ⛅ Parcialmente nublado", forecast.AsNotificationText())
+
+	storm := entity.NewWeatherForecast(date, 20, 30, "t", 5)
+	assert.Equal(t, entity.SeverityHigh, storm.Condition.Severity)
+
+	unknown := entity.NewWeatherForecast(date, 20, 30, "xyz", 5)
+	assert.Equal(t, "xyz", unknown.Condition.Description)
+	assert.Equal(t, entity.SeverityLow, unknown.Condition.Severity)
+}
+
+func TestForecastHorizonForDays(t *testing.T) {
+	tests := []struct {
+		days     int
+		expected entity.ForecastHorizon
+	}{
+		{1, entity.ForecastHorizon4Days},
+		{4, entity.ForecastHorizon4Days},
+		{5, entity.ForecastHorizon7Days},
+		{7, entity.ForecastHorizon7Days},
+		{8, entity.ForecastHorizonExtended},
+		{11, entity.ForecastHorizonExtended},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, entity.ForecastHorizonForDays(tt.days), "%d dias", tt.days)
+	}
+
+	assert.Error(t, entity.ValidateForecastDays(0))
+	assert.Error(t, entity.ValidateForecastDays(12))
+}
+
+func TestWeatherForecastCollection_ExtendAndLimit(t *testing.T) {
+	today := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	days := func(from, count int) []entity.WeatherForecast {
+		forecasts := make([]entity.WeatherForecast, 0, count)
+		for i := from; i < from+count; i++ {
+			forecasts = append(forecasts, entity.WeatherForecast{Date: today.AddDate(0, 0, i)})
+		}
+		return forecasts
+	}
+
+	collection := entity.NewWeatherForecastCollection(uuid.New(), "Ubatuba", "SP", days(0, 4))
+	assert.Equal(t, 4, collection.Days())
+
+	collection.Extend(entity.ForecastHorizonExtended, days(3, 8))
+	assert.Equal(t, entity.ForecastHorizonExtended, collection.Horizon)
+	assert.Equal(t, 11, collection.Days())
+	assert.Equal(t, today.AddDate(0, 0, 10), collection.Forecasts[10].Date)
+
+	limited := collection.Limit(6)
+	assert.Equal(t, 6, limited.Days())
+	assert.Equal(t, 11, collection.Days())
+
+	legacy := entity.WeatherForecastCollection{Forecasts: days(0, 7)}
+	assert.Len(t, legacy.Upcoming(), entity.DefaultForecastDays)
+}
@@ -1,16 +1,32 @@
 package handler
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
 
 var (
 	// User
-	ErrEmptyName  = errors.New("nome não pode ser vazio")
-	ErrEmptyEmail = errors.New("email não pode ser vazio")
+	ErrEmptyName    = errors.New("nome não pode ser vazio")
+	ErrEmptyEmail   = errors.New("email não pode ser vazio")
+	ErrInvalidEmail = errors.New("email inválido")
 
 	// Location
-	ErrInvalidCPTECCode  = errors.New("código CPTEC inválido")
-	ErrEmptyLocationName = errors.New("nome da localização não pode ser vazio")
-	ErrInvalidState      = errors.New("estado deve ter 2 caracteres")
+	ErrInvalidCPTECCode    = errors.New("código CPTEC inválido")
+	ErrEmptyLocationName   = errors.New("nome da localização não pode ser vazio")
+	ErrInvalidState        = errors.New("estado deve ter 2 caracteres")
+	ErrInvalidTimezone     = errors.New("fuso horário inválido")
+	ErrInvalidForecastDays = errors.New("dias de previsão devem estar entre 1 e 11")
+	ErrAmbiguousLocation   = errors.New("mais de uma cidade encontrada")
+	ErrInvalidCoordinates  = errors.New("coordenadas inválidas: latitude deve estar entre -90 e 90 e longitude entre -180 e 180")
+
+	// User location
+	ErrEmptyLocationLabel      = errors.New("rótulo da localização não pode ser vazio")
+	ErrInvalidLocationLabel    = errors.New("rótulo da localização deve ter no máximo 100 caracteres")
+	ErrPrimaryLocationRequired = errors.New("a localização principal não pode ser removida")
+	ErrInvalidNotificationMode = errors.New("modo de notificação inválido")
+	ErrNoNotifiableLocations   = errors.New("nenhuma localização salva com notificação ativa")
 
 	// Notification
 	ErrInvalidUserID             = errors.New("ID do usuário inválido")
@@ -18,14 +34,80 @@ var (
 	ErrInvalidScheduleDate       = errors.New("data de agendamento deve ser futura")
 	ErrInvalidNotificationStatus = errors.New("status da notificação inválido para envio")
 	ErrEmptyForecast             = errors.New("previsão do tempo não pode estar vazia")
+	ErrInvalidStatusTransition   = errors.New("transição de status da notificação inválida")
+	ErrNotificationExpired       = errors.New("notificação expirada")
+	ErrNotificationRescheduled   = errors.New("notificação reagendada")
+	ErrNotificationInProgress    = errors.New("notificação já em processamento")
+	ErrProcessingAbandoned       = errors.New("processamento interrompido sem conclusão")
+
+	// Channel
+	ErrInvalidChannel        = errors.New("canal de notificação inválido")
+	ErrEmptyChannelAddress   = errors.New("endereço do canal não pode ser vazio")
+	ErrInvalidChannelAddress = errors.New("endereço do canal inválido")
+	ErrChannelNotConfigured  = errors.New("canal de notificação não configurado")
+	ErrAllDeliveriesFailed   = errors.New("falha no envio para todos os canais")
+	ErrPartialDelivery       = errors.New("falha no envio para parte dos canais")
+	ErrDeliveryInProgress    = errors.New("envio já em andamento por outro processamento")
+	ErrPermanentDelivery     = errors.New("falha definitiva no envio, sem retentativa")
+
+	// Alert
+	ErrInvalidAlertMetric    = errors.New("métrica do alerta inválida")
+	ErrInvalidAlertOperator  = errors.New("operador do alerta inválido")
+	ErrInvalidAlertThreshold = errors.New("limite do alerta não pode ser negativo para UV ou altura das ondas")
+
+	// Subscription
+	ErrInvalidCronExpression = errors.New("expressão de agendamento inválida")
 
 	// Service
 	ErrUserOptOut          = errors.New("usuário optou por não receber notificações")
 	ErrInvalidScheduleTime = errors.New("horário de agendamento inválido")
 	ErrCPTECUnavailable    = errors.New("serviço CPTEC indisponível")
+	ErrLeaseLost           = errors.New("concessão do agendador pertence a outra instância")
 
 	// Repository
 	ErrNotFound     = errors.New("registro não encontrado")
 	ErrDuplicateKey = errors.New("chave duplicada")
 	ErrInvalidInput = errors.New("entrada inválida")
+
+	// Pagination
+	ErrInvalidCursor = errors.New("cursor de paginação inválido")
+	ErrInvalidSort   = errors.New("campo de ordenação inválido")
 )
+
+// StatusCodeError guarda o status HTTP devolvido pelo destino de um canal.
+type StatusCodeError struct {
+	Message    string
+	StatusCode int
+}
+
+func (e *StatusCodeError) Error() string {
+	return fmt.Sprintf("%s: status %d", e.Message, e.StatusCode)
+}
+
+// IsPermanentDeliveryError indica uma falha de envio que se repetiria em
+// qualquer retentativa: canal não configurado, endereço ausente ou inválido
+// e respostas 4xx do destino, exceto timeout e limite de requisições.
+func IsPermanentDeliveryError(err error) bool {
+	if errors.Is(err, ErrChannelNotConfigured) ||
+		errors.Is(err, ErrEmptyChannelAddress) ||
+		errors.Is(err, ErrInvalidChannelAddress) {
+		return true
+	}
+
+	status := StatusCode(err)
+	switch status {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return false
+	default:
+		return status >= 400 && status < 500
+	}
+}
+
+// StatusCode retorna o status HTTP contido no erro, ou 0 se não houver.
+func StatusCode(err error) int {
+	var statusErr *StatusCodeError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"weather-notification/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type AlertRuleRepository interface {
+	Create(ctx context.Context, rule *entity.AlertRule) error
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]*entity.AlertRule, error)
+	FindActive(ctx context.Context) ([]*entity.AlertRule, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	RegisterTrigger(ctx context.Context, ruleID uuid.UUID, forecastDate time.Time) (bool, error)
+	DeleteTrigger(ctx context.Context, ruleID uuid.UUID, forecastDate time.Time) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"weather-notification/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type DeliveryRepository interface {
+	Create(ctx context.Context, delivery *entity.DeliveryResult) error
+	FindByNotification(ctx context.Context, notificationID uuid.UUID) ([]entity.DeliveryResult, error)
+}
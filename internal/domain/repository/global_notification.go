@@ -11,5 +11,10 @@ import (
 type GlobalNotificationRepository interface {
 	Create(ctx context.Context, notification *entity.GlobalNotification) error
 	FindActive(ctx context.Context) ([]*entity.GlobalNotification, error)
-	UpdateLastExecution(ctx context.Context, id uuid.UUID, executionTime time.Time) error
+	List(ctx context.Context, filter entity.GlobalNotificationFilter, page entity.PageRequest) (*entity.Page[*entity.GlobalNotification], error)
+	// RecordExecution grava a última execução no fuso e as ocorrências
+	// processadas na mesma transação, desde que a concessão do agendador
+	// ainda pertença ao holder de lease. Caso contrário retorna ErrLeaseLost.
+	RecordExecution(ctx context.Context, lease *entity.Lease, id uuid.UUID, timezone string, executionTime time.Time, runs []*entity.GlobalNotificationRun) error
+	FindRuns(ctx context.Context, id uuid.UUID) ([]*entity.GlobalNotificationRun, error)
 }
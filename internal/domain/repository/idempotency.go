@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"weather-notification/internal/domain/entity"
+)
+
+type IdempotencyRepository interface {
+	// Claim reivindica a posse exclusiva do envio e retorna o registro
+	// armazenado, que indica se uma tentativa anterior já concluiu o envio.
+	// Retorna ErrDeliveryInProgress enquanto outro processamento detém a
+	// posse dentro do prazo de concessão.
+	Claim(ctx context.Context, record *entity.DeliveryIdempotency) (*entity.DeliveryIdempotency, error)
+	MarkSent(ctx context.Context, key string) error
+	// Release libera a posse de um envio que falhou, permitindo que a
+	// próxima tentativa o reivindique sem esperar o fim da concessão.
+	Release(ctx context.Context, key string) error
+}
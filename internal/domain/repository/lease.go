@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"weather-notification/internal/domain/entity"
+)
+
+type LeaseRepository interface {
+	// TryAcquire renova a concessão quando já pertence ao holder ou a assume
+	// quando expirada. Retorna nil quando outra instância detém a concessão.
+	TryAcquire(ctx context.Context, name, holderID string, ttl time.Duration) (*entity.Lease, error)
+	Find(ctx context.Context, name string) (*entity.Lease, error)
+	Release(ctx context.Context, name, holderID string) error
+}
@@ -12,4 +12,7 @@ type LocationRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.Location, error)
 	FindByCPTECCode(ctx context.Context, cptecCode int) (*entity.Location, error)
 	FindByNameAndState(ctx context.Context, name, state string) (*entity.Location, error)
+	Search(ctx context.Context, name, state string, limit int) ([]entity.Location, error)
+	Import(ctx context.Context, locations []entity.Location) (int, error)
+	FindWithinBounds(ctx context.Context, bounds entity.CoordinateBounds) ([]entity.Location, error)
 }
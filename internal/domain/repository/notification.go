@@ -10,8 +10,15 @@ import (
 type NotificationRepository interface {
 	Create(ctx context.Context, notification *entity.Notification) error
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error)
-	FindPendingNotifications(ctx context.Context) ([]*entity.Notification, error)
-	UpdateStatus(ctx context.Context, id uuid.UUID, status entity.NotificationStatus) error
+	// FindRecoverableNotifications retorna as notificações PENDENTE, as em
+	// FALHA com menos de maxAttempts tentativas e as EM_PROCESSAMENTO.
+	FindRecoverableNotifications(ctx context.Context, maxAttempts int) ([]*entity.Notification, error)
+	// Transition grava o novo status e o evento, desde que o status atual
+	// ainda seja o de origem do evento.
+	Transition(ctx context.Context, notification *entity.Notification, event *entity.NotificationEvent) error
+	// Reschedule grava o novo horário, o evento e uma nova mensagem no outbox.
+	Reschedule(ctx context.Context, notification *entity.Notification, event *entity.NotificationEvent) error
+	FindEvents(ctx context.Context, id uuid.UUID) ([]*entity.NotificationEvent, error)
 	FindByUserAndLocation(ctx context.Context, userID, locationID uuid.UUID) ([]*entity.Notification, error)
-	FindByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Notification, error)
+	List(ctx context.Context, filter entity.NotificationFilter, page entity.PageRequest) (*entity.Page[*entity.Notification], error)
 }
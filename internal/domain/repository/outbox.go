@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"weather-notification/internal/domain/entity"
+)
+
+type OutboxRepository interface {
+	// DispatchPending reivindica até limit mensagens não despachadas, chama
+	// publish para cada uma e marca como despachadas as publicadas com sucesso.
+	DispatchPending(ctx context.Context, limit int, publish func(*entity.OutboxMessage) error) (int, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"weather-notification/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type SubscriptionRepository interface {
+	Create(ctx context.Context, subscription *entity.Subscription) error
+	Update(ctx context.Context, subscription *entity.Subscription) error
+	FindByID(ctx context.Context, userID, id uuid.UUID) (*entity.Subscription, error)
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Subscription, error)
+	FindDue(ctx context.Context, now time.Time) ([]*entity.Subscription, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
@@ -10,9 +10,10 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user *entity.User) error
 	Update(ctx context.Context, user *entity.User) error
-	FindAll(ctx context.Context) ([]entity.User, error)
+	List(ctx context.Context, filter entity.UserFilter, page entity.PageRequest) (*entity.Page[entity.User], error)
 	FindAllActive(ctx context.Context) ([]entity.User, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
 	FindByEmail(ctx context.Context, email string) (*entity.User, error)
 	UpdateOptOut(ctx context.Context, id uuid.UUID, optOut bool) error
+	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"weather-notification/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type UserLocationRepository interface {
+	Create(ctx context.Context, location *entity.UserLocation) error
+	Update(ctx context.Context, location *entity.UserLocation) error
+	SetPrimary(ctx context.Context, userID, id uuid.UUID) error
+	FindByID(ctx context.Context, userID, id uuid.UUID) (*entity.UserLocation, error)
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]entity.UserLocation, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type AlertService struct {
+	alertRepo        repository.AlertRuleRepository
+	userRepo         repository.UserRepository
+	notificationRepo repository.NotificationRepository
+	weatherService   *WeatherService
+}
+
+func NewAlertService(
+	alertRepo repository.AlertRuleRepository,
+	userRepo repository.UserRepository,
+	notificationRepo repository.NotificationRepository,
+	weatherService *WeatherService,
+) *AlertService {
+	return &AlertService{
+		alertRepo:        alertRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		weatherService:   weatherService,
+	}
+}
+
+func (s *AlertService) Create(
+	ctx context.Context,
+	userID, locationID uuid.UUID,
+	metric entity.AlertMetric,
+	operator entity.AlertOperator,
+	threshold float64,
+	conditions []string,
+) (*entity.AlertRule, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if locationID == uuid.Nil {
+		locationID = user.LocationID
+	}
+
+	rule, err := entity.NewAlertRule(userID, locationID, metric, operator, threshold, conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.alertRepo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *AlertService) ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.AlertRule, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.alertRepo.FindByUser(ctx, userID)
+}
+
+func (s *AlertService) Delete(ctx context.Context, userID, ruleID uuid.UUID) error {
+	return s.alertRepo.Delete(ctx, userID, ruleID)
+}
+
+// EvaluateAlerts confere as regras ativas contra a previsão de cada
+// localização. A falha ao emitir o alerta de uma regra não interrompe as
+// demais; os erros são devolvidos juntos ao final.
+func (s *AlertService) EvaluateAlerts(ctx context.Context) error {
+	rules, err := s.alertRepo.FindActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	rulesByLocation := make(map[uuid.UUID][]*entity.AlertRule)
+	for _, rule := range rules {
+		rulesByLocation[rule.LocationID] = append(rulesByLocation[rule.LocationID], rule)
+	}
+
+	var errs []error
+	for locationID, locationRules := range rulesByLocation {
+		forecast, err := s.weatherService.GetForecast(ctx, locationID)
+		if err != nil {
+			continue
+		}
+
+		for _, rule := range locationRules {
+			user, err := s.userRepo.FindByID(ctx, rule.UserID)
+			if err != nil || user.OptOut {
+				continue
+			}
+
+			for _, day := range forecast.Forecasts {
+				if !rule.Matches(day) {
+					continue
+				}
+
+				if err := s.emitAlert(ctx, rule, day, forecast); err != nil {
+					errs = append(errs, fmt.Errorf("alerta %s: %w", rule.ID, err))
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *AlertService) emitAlert(ctx context.Context, rule *entity.AlertRule, day entity.WeatherForecast, forecast *entity.WeatherForecastCollection) error {
+	isNew, err := s.alertRepo.RegisterTrigger(ctx, rule.ID, day.Date)
+	if err != nil || !isNew {
+		return err
+	}
+
+	notification, err := entity.NewNotification(rule.UserID, rule.LocationID, *forecast, time.Now())
+	if err != nil {
+		_ = s.alertRepo.DeleteTrigger(ctx, rule.ID, day.Date)
+		return err
+	}
+	notification.Message = rule.Describe(day)
+
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		_ = s.alertRepo.DeleteTrigger(ctx, rule.ID, day.Date)
+		return err
+	}
+
+	return nil
+}
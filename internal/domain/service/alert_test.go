@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAlertRuleRepository guarda os disparos por regra e dia, como a
+// restrição única de alert_triggers, e pode falhar o registro de uma regra.
+type fakeAlertRuleRepository struct {
+	rules       []*entity.AlertRule
+	triggers    map[uuid.UUID]map[string]bool
+	registerErr map[uuid.UUID]error
+}
+
+func newFakeAlertRuleRepository(rules ...*entity.AlertRule) *fakeAlertRuleRepository {
+	return &fakeAlertRuleRepository{
+		rules:       rules,
+		triggers:    make(map[uuid.UUID]map[string]bool),
+		registerErr: make(map[uuid.UUID]error),
+	}
+}
+
+func (r *fakeAlertRuleRepository) Create(ctx context.Context, rule *entity.AlertRule) error {
+	r.rules = append(r.rules, rule)
+	return nil
+}
+
+func (r *fakeAlertRuleRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]*entity.AlertRule, error) {
+	var rules []*entity.AlertRule
+	for _, rule := range r.rules {
+		if rule.UserID == userID {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (r *fakeAlertRuleRepository) FindActive(ctx context.Context) ([]*entity.AlertRule, error) {
+	return r.rules, nil
+}
+
+func (r *fakeAlertRuleRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeAlertRuleRepository) RegisterTrigger(ctx context.Context, ruleID uuid.UUID, forecastDate time.Time) (bool, error) {
+	if err := r.registerErr[ruleID]; err != nil {
+		return false, err
+	}
+	day := forecastDate.Format("2006-01-02")
+	if r.triggers[ruleID] == nil {
+		r.triggers[ruleID] = make(map[string]bool)
+	}
+	if r.triggers[ruleID][day] {
+		return false, nil
+	}
+	r.triggers[ruleID][day] = true
+	return true, nil
+}
+
+func (r *fakeAlertRuleRepository) DeleteTrigger(ctx context.Context, ruleID uuid.UUID, forecastDate time.Time) error {
+	delete(r.triggers[ruleID], forecastDate.Format("2006-01-02"))
+	return nil
+}
+
+func newAlertTestService(t *testing.T, alertRepo *fakeAlertRuleRepository, notificationRepo *fakeNotificationRepository, users ...*entity.User) (*service.AlertService, *entity.Location) {
+	t.Helper()
+
+	campinas := &entity.Location{ID: uuid.New(), CPTECCode: 1, Name: "Campinas", State: "SP"}
+	weatherService := service.NewWeatherService(
+		&fakeCPTECClient{cities: map[int]string{1: "Campinas"}},
+		&fakeLocationRepository{locations: map[uuid.UUID]*entity.Location{campinas.ID: campinas}},
+		nil,
+	)
+
+	userRepo := new(MockUserRepository)
+	for _, user := range users {
+		userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+	}
+
+	return service.NewAlertService(alertRepo, userRepo, notificationRepo, weatherService), campinas
+}
+
+func TestAlertService_EvaluateAlerts_DoesNotEmitTwice(t *testing.T) {
+	user := &entity.User{ID: uuid.New(), Name: "Ana", Email: "ana@exemplo.com"}
+	alertRepo := newFakeAlertRuleRepository()
+	notificationRepo := newFakeNotificationRepository()
+	alertService, campinas := newAlertTestService(t, alertRepo, notificationRepo, user)
+
+	rule, err := entity.NewAlertRule(user.ID, campinas.ID, entity.MetricMaxTemp, entity.OperatorGreater, 25, nil)
+	require.NoError(t, err)
+	alertRepo.rules = append(alertRepo.rules, rule)
+
+	require.NoError(t, alertService.EvaluateAlerts(context.Background()))
+	require.Len(t, notificationRepo.notifications, 1)
+
+	require.NoError(t, alertService.EvaluateAlerts(context.Background()))
+	assert.Len(t, notificationRepo.notifications, 1, "a segunda avaliação não deve emitir o mesmo alerta")
+}
+
+func TestAlertService_EvaluateAlerts_ContinuesAfterFailure(t *testing.T) {
+	user := &entity.User{ID: uuid.New(), Name: "Ana", Email: "ana@exemplo.com"}
+	alertRepo := newFakeAlertRuleRepository()
+	notificationRepo := newFakeNotificationRepository()
+	alertService, campinas := newAlertTestService(t, alertRepo, notificationRepo, user)
+
+	failing, err := entity.NewAlertRule(user.ID, campinas.ID, entity.MetricMaxTemp, entity.OperatorGreater, 25, nil)
+	require.NoError(t, err)
+	working, err := entity.NewAlertRule(user.ID, campinas.ID, entity.MetricMinTemp, entity.OperatorLess, 20, nil)
+	require.NoError(t, err)
+	alertRepo.rules = append(alertRepo.rules, failing, working)
+
+	dbErr := errors.New("conexão perdida")
+	alertRepo.registerErr[failing.ID] = dbErr
+
+	err = alertService.EvaluateAlerts(context.Background())
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Len(t, notificationRepo.notifications, 1, "a regra sem erro deve emitir o alerta")
+	assert.Len(t, alertRepo.triggers[working.ID], 1)
+}
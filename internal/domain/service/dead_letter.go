@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+)
+
+type DeadLetterQueue interface {
+	ListDeadLetters(ctx context.Context) ([]*entity.DeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, id string) error
+	PurgeDeadLetters(ctx context.Context) (int, error)
+}
+
+type DeadLetterService struct {
+	queue DeadLetterQueue
+}
+
+func NewDeadLetterService(queue DeadLetterQueue) *DeadLetterService {
+	return &DeadLetterService{
+		queue: queue,
+	}
+}
+
+func (s *DeadLetterService) List(ctx context.Context) ([]*entity.DeadLetter, error) {
+	return s.queue.ListDeadLetters(ctx)
+}
+
+func (s *DeadLetterService) Get(ctx context.Context, id string) (*entity.DeadLetter, error) {
+	letters, err := s.queue.ListDeadLetters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, letter := range letters {
+		if letter.ID == id {
+			return letter, nil
+		}
+	}
+
+	return nil, handler.ErrNotFound
+}
+
+func (s *DeadLetterService) Replay(ctx context.Context, id string) error {
+	return s.queue.ReplayDeadLetter(ctx, id)
+}
+
+func (s *DeadLetterService) Purge(ctx context.Context) (int, error) {
+	return s.queue.PurgeDeadLetters(ctx)
+}
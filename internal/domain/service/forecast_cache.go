@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"weather-notification/internal/domain/entity"
+)
+
+// ForecastCache guarda uma previsão por cidade e horizonte; Set usa o
+// horizonte da própria previsão.
+type ForecastCache interface {
+	Get(ctx context.Context, cptecCode int, horizon entity.ForecastHorizon) (*entity.WeatherForecastCollection, bool)
+	Set(ctx context.Context, cptecCode int, forecast *entity.WeatherForecastCollection) error
+}
+
+type CacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
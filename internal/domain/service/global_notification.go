@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"time"
 	"weather-notification/internal/domain/entity"
 	"weather-notification/internal/domain/repository"
@@ -12,24 +13,31 @@ import (
 type GlobalNotificationService struct {
 	repo             repository.GlobalNotificationRepository
 	userRepo         repository.UserRepository
-	queueService     QueueService
+	userLocationRepo repository.UserLocationRepository
 	weatherService   *WeatherService
 	notificationRepo repository.NotificationRepository
+	gracePeriod      time.Duration
 }
 
 func NewGlobalNotificationService(
 	repo repository.GlobalNotificationRepository,
 	userRepo repository.UserRepository,
-	queueService QueueService,
+	userLocationRepo repository.UserLocationRepository,
 	weatherService *WeatherService,
 	notificationRepo repository.NotificationRepository,
+	gracePeriod time.Duration,
 ) *GlobalNotificationService {
+	if gracePeriod < time.Minute {
+		gracePeriod = time.Minute
+	}
+
 	return &GlobalNotificationService{
 		repo:             repo,
 		userRepo:         userRepo,
-		queueService:     queueService,
+		userLocationRepo: userLocationRepo,
 		weatherService:   weatherService,
 		notificationRepo: notificationRepo,
+		gracePeriod:      gracePeriod,
 	}
 }
 
@@ -45,56 +53,105 @@ func (s *GlobalNotificationService) Create(ctx context.Context, timeOfDay time.T
 	return s.repo.Create(ctx, globalNotification)
 }
 
-func (s *GlobalNotificationService) ListActive(ctx context.Context) ([]*entity.GlobalNotification, error) {
-	return s.repo.FindActive(ctx)
+func (s *GlobalNotificationService) List(ctx context.Context, filter entity.GlobalNotificationFilter, page entity.PageRequest) (*entity.Page[*entity.GlobalNotification], error) {
+	return s.repo.List(ctx, filter, page)
 }
 
-func (s *GlobalNotificationService) ProcessActiveNotifications(ctx context.Context) error {
-	now := time.Now()
+func (s *GlobalNotificationService) ListRuns(ctx context.Context, id uuid.UUID) ([]*entity.GlobalNotificationRun, error) {
+	return s.repo.FindRuns(ctx, id)
+}
+
+// ProcessActiveNotifications dispara as notificações globais devidas em cada
+// fuso. A concessão do agendador é renovada antes de cada fuso e a execução
+// só é registrada, e as notificações enviadas, enquanto ela pertencer a esta
+// instância, para que um líder antigo e o novo não disparem a mesma
+// execução.
+func (s *GlobalNotificationService) ProcessActiveNotifications(ctx context.Context, leader *LeaderElectionService) error {
+	now := time.Now().UTC()
 
 	notifications, err := s.repo.FindActive(ctx)
 	if err != nil {
 		return err
 	}
+	if len(notifications) == 0 {
+		return nil
+	}
 
-	for _, globalNotif := range notifications {
-		if !globalNotif.ShouldExecute(now) {
-			continue
-		}
-		users, err := s.userRepo.FindAllActive(ctx)
-		if err != nil {
-			continue
-		}
+	users, err := s.userRepo.FindAllActive(ctx)
+	if err != nil {
+		return err
+	}
+	usersByTimezone := groupUsersByTimezone(users)
 
-		for _, user := range users {
-			forecast, err := s.weatherService.GetForecast(ctx, user.LocationID)
+	for _, globalNotif := range notifications {
+		for timezone, zoneUsers := range usersByTimezone {
+			location, err := entity.LoadTimezone(timezone)
 			if err != nil {
 				continue
 			}
 
-			notification := &entity.Notification{
-				ID:           uuid.New(),
-				UserID:       user.ID,
-				LocationID:   user.LocationID,
-				Content:      *forecast,
-				Status:       entity.StatusPending,
-				ScheduledFor: now.Add(2 * time.Minute),
-				CreatedAt:    now,
+			due, missed := globalNotif.DueRuns(now, location, s.gracePeriod)
+			if due == nil && len(missed) == 0 {
+				continue
 			}
 
-			if err := s.notificationRepo.Create(ctx, notification); err != nil {
+			runs := make([]*entity.GlobalNotificationRun, 0, len(missed)+1)
+			for _, scheduledFor := range missed {
+				runs = append(runs, entity.NewGlobalNotificationRun(globalNotif.ID, timezone, scheduledFor, entity.RunSkipped, "execução perdida fora do período de tolerância"))
+			}
+			if due != nil {
+				runs = append(runs, entity.NewGlobalNotificationRun(globalNotif.ID, timezone, *due, entity.RunExecuted, ""))
+			}
+
+			lease, err := leader.Renew(ctx)
+			if err != nil {
 				return err
 			}
 
-			if err := s.queueService.PublishNotification(ctx, notification); err != nil {
-				continue
+			// A execução é registrada antes do envio: se o registro falhar
+			// nada é enviado e o próximo ciclo tenta de novo, e uma falha no
+			// envio não faz a mesma execução disparar outra vez
+			if err := s.repo.RecordExecution(ctx, lease, globalNotif.ID, location.String(), now, runs); err != nil {
+				return fmt.Errorf("erro ao registrar execução da notificação global %s no fuso %s: %w", globalNotif.ID, timezone, err)
+			}
+			globalNotif.MarkExecuted(location, now)
+
+			if due != nil {
+				if err := s.notifyUsers(ctx, zoneUsers, now); err != nil {
+					return err
+				}
 			}
 		}
+	}
 
-		if err := s.repo.UpdateLastExecution(ctx, globalNotif.ID, now); err != nil {
+	return nil
+}
+
+func (s *GlobalNotificationService) notifyUsers(ctx context.Context, users []entity.User, now time.Time) error {
+	for _, user := range users {
+		notifications, err := newUserLocationNotifications(ctx, s.weatherService, s.userLocationRepo, &user, now.Add(2*time.Minute))
+		if err != nil {
 			continue
 		}
+
+		for _, notification := range notifications {
+			if err := s.notificationRepo.Create(ctx, notification); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
+
+func groupUsersByTimezone(users []entity.User) map[string][]entity.User {
+	groups := make(map[string][]entity.User)
+	for _, user := range users {
+		timezone := user.Timezone
+		if timezone == "" {
+			timezone = entity.DefaultTimezone
+		}
+		groups[timezone] = append(groups[timezone], user)
+	}
+	return groups
+}
@@ -0,0 +1,116 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGlobalNotificationRepository guarda as execuções registradas e pode
+// falhar o registro para simular uma queda do banco após o cálculo.
+type fakeGlobalNotificationRepository struct {
+	notifications []*entity.GlobalNotification
+	runs          []*entity.GlobalNotificationRun
+	recordErr     error
+}
+
+func (r *fakeGlobalNotificationRepository) Create(ctx context.Context, notification *entity.GlobalNotification) error {
+	r.notifications = append(r.notifications, notification)
+	return nil
+}
+
+func (r *fakeGlobalNotificationRepository) FindActive(ctx context.Context) ([]*entity.GlobalNotification, error) {
+	return r.notifications, nil
+}
+
+func (r *fakeGlobalNotificationRepository) List(ctx context.Context, filter entity.GlobalNotificationFilter, page entity.PageRequest) (*entity.Page[*entity.GlobalNotification], error) {
+	return &entity.Page[*entity.GlobalNotification]{Items: r.notifications}, nil
+}
+
+func (r *fakeGlobalNotificationRepository) RecordExecution(ctx context.Context, lease *entity.Lease, id uuid.UUID, timezone string, executionTime time.Time, runs []*entity.GlobalNotificationRun) error {
+	if r.recordErr != nil {
+		return r.recordErr
+	}
+	r.runs = append(r.runs, runs...)
+	return nil
+}
+
+func (r *fakeGlobalNotificationRepository) FindRuns(ctx context.Context, id uuid.UUID) ([]*entity.GlobalNotificationRun, error) {
+	return r.runs, nil
+}
+
+func TestGlobalNotificationService_ProcessActiveNotifications(t *testing.T) {
+	campinas := &entity.Location{ID: uuid.New(), CPTECCode: 1, Name: "Campinas", State: "SP"}
+	weatherService := service.NewWeatherService(
+		&fakeCPTECClient{cities: map[int]string{1: "Campinas"}},
+		&fakeLocationRepository{locations: map[uuid.UUID]*entity.Location{campinas.ID: campinas}},
+		nil,
+	)
+
+	dbErr := errors.New("conexão perdida")
+	held := &entity.Lease{Name: service.GlobalSchedulerLease, HolderID: "instancia-a", ExpiresAt: time.Now().Add(time.Minute)}
+	tests := []struct {
+		name          string
+		lease         *entity.Lease
+		recordErr     error
+		expectedSent  int
+		expectedError error
+	}{
+		{"registra a execução e envia", held, nil, 1, nil},
+		{"falha ao registrar a execução não envia", held, dbErr, 0, dbErr},
+		{"concessão assumida por outra instância não registra nem envia", nil, nil, 0, handler.ErrLeaseLost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := entity.NewUser("Matheus", "matheus@exemplo.com", campinas.ID)
+			require.NoError(t, err)
+			userRepo := new(MockUserRepository)
+			userRepo.On("FindAllActive", mock.Anything).Return([]entity.User{*user}, nil)
+
+			// O horário configurado é o minuto atual no fuso do usuário
+			now := time.Now().In(user.TimeLocation())
+			timeOfDay := time.Date(0, 1, 1, now.Hour(), now.Minute(), 0, 0, time.UTC)
+			globalRepo := &fakeGlobalNotificationRepository{
+				notifications: []*entity.GlobalNotification{{
+					ID:        uuid.New(),
+					TimeOfDay: timeOfDay,
+					Frequency: entity.FrequencyDaily,
+					Active:    true,
+					CreatedAt: now.Add(-time.Hour),
+				}},
+				recordErr: tt.recordErr,
+			}
+			userLocationRepo := &fakeUserLocationRepository{locations: []entity.UserLocation{
+				{ID: uuid.New(), UserID: user.ID, LocationID: campinas.ID, Label: entity.PrimaryLocationLabel, Primary: true, Notify: true},
+			}}
+			notificationRepo := newFakeNotificationRepository()
+
+			leaseRepo := new(MockLeaseRepository)
+			leaseRepo.On("TryAcquire", mock.Anything, service.GlobalSchedulerLease, "instancia-a", 30*time.Second).Return(tt.lease, nil)
+			leader := service.NewLeaderElectionService(leaseRepo, service.GlobalSchedulerLease, "instancia-a", 30*time.Second)
+
+			globalService := service.NewGlobalNotificationService(globalRepo, userRepo, userLocationRepo, weatherService, notificationRepo, 30*time.Minute)
+			err = globalService.ProcessActiveNotifications(context.Background(), leader)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Empty(t, globalRepo.runs)
+			} else {
+				assert.NoError(t, err)
+				require.Len(t, globalRepo.runs, 1)
+				assert.Equal(t, entity.RunExecuted, globalRepo.runs[0].Status)
+			}
+			assert.Len(t, notificationRepo.notifications, tt.expectedSent)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/repository"
+)
+
+const GlobalSchedulerLease = "global-scheduler"
+
+type LeaderStatus struct {
+	InstanceID string        `json:"instance_id"`
+	IsLeader   bool          `json:"is_leader"`
+	Lease      *entity.Lease `json:"lease,omitempty"`
+}
+
+type LeaderElectionService struct {
+	repo       repository.LeaseRepository
+	name       string
+	instanceID string
+	ttl        time.Duration
+	leader     atomic.Bool
+}
+
+func NewLeaderElectionService(
+	repo repository.LeaseRepository,
+	name string,
+	instanceID string,
+	ttl time.Duration,
+) *LeaderElectionService {
+	return &LeaderElectionService{
+		repo:       repo,
+		name:       name,
+		instanceID: instanceID,
+		ttl:        ttl,
+	}
+}
+
+func (s *LeaderElectionService) TTL() time.Duration {
+	return s.ttl
+}
+
+func (s *LeaderElectionService) IsLeader() bool {
+	return s.leader.Load()
+}
+
+// Heartbeat tenta adquirir ou renovar a concessão. Em caso de erro a
+// instância deixa de se considerar líder, pois não consegue provar que a
+// concessão ainda é sua.
+func (s *LeaderElectionService) Heartbeat(ctx context.Context) (bool, error) {
+	_, err := s.Renew(ctx)
+	if errors.Is(err, handler.ErrLeaseLost) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Renew adquire ou renova a concessão e a retorna para que as escritas do
+// líder sejam condicionadas a ela. Retorna ErrLeaseLost quando outra
+// instância detém a concessão.
+func (s *LeaderElectionService) Renew(ctx context.Context) (*entity.Lease, error) {
+	lease, err := s.repo.TryAcquire(ctx, s.name, s.instanceID, s.ttl)
+	if err != nil {
+		s.leader.Store(false)
+		return nil, err
+	}
+
+	if lease == nil || lease.HolderID != s.instanceID {
+		s.leader.Store(false)
+		return nil, handler.ErrLeaseLost
+	}
+
+	s.leader.Store(true)
+	return lease, nil
+}
+
+func (s *LeaderElectionService) Release(ctx context.Context) error {
+	if !s.leader.Swap(false) {
+		return nil
+	}
+	return s.repo.Release(ctx, s.name, s.instanceID)
+}
+
+func (s *LeaderElectionService) Status(ctx context.Context) (*LeaderStatus, error) {
+	status := &LeaderStatus{
+		InstanceID: s.instanceID,
+		IsLeader:   s.IsLeader(),
+	}
+
+	lease, err := s.repo.Find(ctx, s.name)
+	if errors.Is(err, handler.ErrNotFound) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Now().Before(lease.ExpiresAt) {
+		status.Lease = lease
+	}
+	return status, nil
+}
@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLeaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockLeaseRepository) TryAcquire(ctx context.Context, name, holderID string, ttl time.Duration) (*entity.Lease, error) {
+	args := m.Called(ctx, name, holderID, ttl)
+	if lease, ok := args.Get(0).(*entity.Lease); ok {
+		return lease, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockLeaseRepository) Find(ctx context.Context, name string) (*entity.Lease, error) {
+	args := m.Called(ctx, name)
+	if lease, ok := args.Get(0).(*entity.Lease); ok {
+		return lease, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockLeaseRepository) Release(ctx context.Context, name, holderID string) error {
+	args := m.Called(ctx, name, holderID)
+	return args.Error(0)
+}
+
+func TestLeaderElectionService_Heartbeat(t *testing.T) {
+	ttl := 30 * time.Second
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		lease        *entity.Lease
+		repoErr      error
+		expectLeader bool
+		expectError  bool
+	}{
+		{
+			name:         "adquire a concessão",
+			lease:        &entity.Lease{Name: service.GlobalSchedulerLease, HolderID: "instancia-a", ExpiresAt: now.Add(ttl)},
+			expectLeader: true,
+		},
+		{
+			name:         "concessão pertence a outra instância",
+			lease:        nil,
+			expectLeader: false,
+		},
+		{
+			name:         "erro no banco remove a liderança",
+			repoErr:      errors.New("conexão recusada"),
+			expectLeader: false,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockLeaseRepository)
+			repo.On("TryAcquire", mock.Anything, service.GlobalSchedulerLease, "instancia-a", ttl).Return(tt.lease, tt.repoErr)
+
+			leader := service.NewLeaderElectionService(repo, service.GlobalSchedulerLease, "instancia-a", ttl)
+			isLeader, err := leader.Heartbeat(context.Background())
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectLeader, isLeader)
+			assert.Equal(t, tt.expectLeader, leader.IsLeader())
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLeaderElectionService_ReleaseOnlyWhenLeader(t *testing.T) {
+	ttl := 30 * time.Second
+	repo := new(MockLeaseRepository)
+	leader := service.NewLeaderElectionService(repo, service.GlobalSchedulerLease, "instancia-a", ttl)
+
+	assert.NoError(t, leader.Release(context.Background()))
+	repo.AssertNotCalled(t, "Release", mock.Anything, mock.Anything, mock.Anything)
+
+	repo.On("TryAcquire", mock.Anything, service.GlobalSchedulerLease, "instancia-a", ttl).
+		Return(&entity.Lease{HolderID: "instancia-a", ExpiresAt: time.Now().Add(ttl)}, nil)
+	repo.On("Release", mock.Anything, service.GlobalSchedulerLease, "instancia-a").Return(nil)
+
+	_, _ = leader.Heartbeat(context.Background())
+	assert.NoError(t, leader.Release(context.Background()))
+	assert.False(t, leader.IsLeader())
+	repo.AssertExpectations(t)
+}
+
+func TestLeaderElectionService_Status(t *testing.T) {
+	ttl := 30 * time.Second
+	repo := new(MockLeaseRepository)
+	repo.On("Find", mock.Anything, service.GlobalSchedulerLease).Return(nil, handler.ErrNotFound).Once()
+	repo.On("Find", mock.Anything, service.GlobalSchedulerLease).
+		Return(&entity.Lease{HolderID: "instancia-b", ExpiresAt: time.Now().Add(ttl)}, nil).Once()
+
+	leader := service.NewLeaderElectionService(repo, service.GlobalSchedulerLease, "instancia-a", ttl)
+
+	status, err := leader.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, status.Lease)
+
+	status, err = leader.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "instancia-b", status.Lease.HolderID)
+	assert.False(t, status.IsLeader)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/domain/repository"
+)
+
+type LocationCatalogService struct {
+	locationRepo repository.LocationRepository
+}
+
+func NewLocationCatalogService(locationRepo repository.LocationRepository) *LocationCatalogService {
+	return &LocationCatalogService{
+		locationRepo: locationRepo,
+	}
+}
+
+// Import grava a lista de cidades do CPTEC no catálogo local. Códigos
+// repetidos no arquivo ficam com a última ocorrência.
+func (s *LocationCatalogService) Import(ctx context.Context, locations []entity.Location) (int, error) {
+	positions := make(map[int]int, len(locations))
+	unique := make([]entity.Location, 0, len(locations))
+	for _, location := range locations {
+		if i, ok := positions[location.CPTECCode]; ok {
+			unique[i] = location
+			continue
+		}
+		positions[location.CPTECCode] = len(unique)
+		unique = append(unique, location)
+	}
+
+	if len(unique) == 0 {
+		return 0, nil
+	}
+
+	return s.locationRepo.Import(ctx, unique)
+}
@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
 	"time"
 	"weather-notification/internal/domain/entity"
 	handler "weather-notification/internal/domain/error_handler"
@@ -13,21 +16,33 @@ import (
 type NotificationService struct {
 	notificationRepo repository.NotificationRepository
 	userRepo         repository.UserRepository
+	userLocationRepo repository.UserLocationRepository
+	deliveryRepo     repository.DeliveryRepository
+	idempotencyRepo  repository.IdempotencyRepository
 	weatherService   *WeatherService
-	queueService     QueueService
+	notifiers        *NotifierRegistry
+	rateLimiter      *DeliveryRateLimiter
 }
 
 func NewNotificationService(
 	notificationRepo repository.NotificationRepository,
 	userRepo repository.UserRepository,
+	userLocationRepo repository.UserLocationRepository,
+	deliveryRepo repository.DeliveryRepository,
+	idempotencyRepo repository.IdempotencyRepository,
 	weatherService *WeatherService,
-	queueService QueueService,
+	notifiers *NotifierRegistry,
+	rateLimiter *DeliveryRateLimiter,
 ) *NotificationService {
 	return &NotificationService{
 		notificationRepo: notificationRepo,
 		userRepo:         userRepo,
+		userLocationRepo: userLocationRepo,
+		deliveryRepo:     deliveryRepo,
+		idempotencyRepo:  idempotencyRepo,
 		weatherService:   weatherService,
-		queueService:     queueService,
+		notifiers:        notifiers,
+		rateLimiter:      rateLimiter,
 	}
 }
 
@@ -40,7 +55,11 @@ func (s *NotificationService) Schedule(ctx context.Context, userID, locationID u
 		return handler.ErrUserOptOut
 	}
 
-	forecast, err := s.weatherService.GetForecast(ctx, locationID)
+	if locationID == uuid.Nil {
+		return s.scheduleSavedLocations(ctx, user, scheduledFor)
+	}
+
+	forecast, err := s.weatherService.GetForecastForDays(ctx, locationID, user.ForecastDays)
 	if err != nil {
 		return err
 	}
@@ -55,55 +74,264 @@ func (s *NotificationService) Schedule(ctx context.Context, userID, locationID u
 		return err
 	}
 
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	return s.notificationRepo.Create(ctx, notification)
+}
+
+// scheduleSavedLocations agenda a previsão das localizações salvas do
+// usuário, combinadas ou separadas conforme a preferência dele.
+func (s *NotificationService) scheduleSavedLocations(ctx context.Context, user *entity.User, scheduledFor time.Time) error {
+	notifications, err := newUserLocationNotifications(ctx, s.weatherService, s.userLocationRepo, user, scheduledFor)
+	if err != nil {
 		return err
 	}
 
-	return s.queueService.PublishNotification(ctx, notification)
+	for _, notification := range notifications {
+		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (s *NotificationService) ProcessPendingNotifications(ctx context.Context) error {
-	notifications, err := s.notificationRepo.FindPendingNotifications(ctx)
+func (s *NotificationService) ListNotifications(ctx context.Context, filter entity.NotificationFilter, page entity.PageRequest) (*entity.Page[*entity.Notification], error) {
+	_, err := s.userRepo.FindByID(ctx, filter.UserID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, notification := range notifications {
-		user, err := s.userRepo.FindByID(ctx, notification.UserID)
+	return s.notificationRepo.List(ctx, filter, page)
+}
+
+func (s *NotificationService) Cancel(ctx context.Context, id uuid.UUID, actor string) (*entity.Notification, error) {
+	notification, err := s.notificationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := notification.Cancel(actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.notificationRepo.Transition(ctx, notification, event); err != nil {
+		return nil, err
+	}
+
+	return notification, nil
+}
+
+// Reschedule altera o horário de uma notificação pendente. Uma nova mensagem
+// é publicada pelo outbox e a mensagem anterior é descartada pelo worker ao
+// perceber que o horário não confere.
+func (s *NotificationService) Reschedule(ctx context.Context, id uuid.UUID, scheduledFor time.Time, actor string) (*entity.Notification, error) {
+	notification, err := s.notificationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := notification.Reschedule(scheduledFor, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.notificationRepo.Reschedule(ctx, notification, event); err != nil {
+		return nil, err
+	}
+
+	return notification, nil
+}
+
+// processingLease é o tempo sem atualização após o qual uma notificação em
+// processamento é considerada abandonada.
+const processingLease = 15 * time.Minute
+
+// StartProcessing confere a notificação recebida da fila com o estado atual
+// no banco e a marca como em processamento. A troca de status é condicionada
+// ao status lido, então entre entregas concorrentes apenas uma inicia o
+// processamento. Notificações canceladas, reagendadas, já concluídas, em
+// processamento ou atrasadas além de maxDelay não devem ser enviadas; nesses
+// casos é retornado o erro correspondente.
+func (s *NotificationService) StartProcessing(ctx context.Context, queued *entity.Notification, maxDelay time.Duration) (*entity.Notification, error) {
+	notification, err := s.notificationRepo.FindByID(ctx, queued.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// O banco guarda microssegundos, então a comparação é feita por segundo
+	if !notification.ScheduledFor.Truncate(time.Second).Equal(queued.ScheduledFor.Truncate(time.Second)) {
+		return nil, handler.ErrNotificationRescheduled
+	}
+
+	// Outro worker está processando a notificação e ela não deve ser
+	// enviada de novo. Um processamento sem atualização além de
+	// processingLease foi abandonado por uma queda e é retomado passando por
+	// FALHA, de modo que cada passo continue condicionado ao status no banco.
+	if notification.Status == entity.StatusProcessing {
+		if time.Since(notification.UpdatedAt) < processingLease {
+			return nil, handler.ErrNotificationInProgress
+		}
+
+		event, err := notification.TransitionTo(entity.StatusFailed, entity.ActorWorker, handler.ErrProcessingAbandoned)
 		if err != nil {
-			continue
+			return nil, err
 		}
-		if user.OptOut {
-			notification.MarkAsFailed()
-			s.notificationRepo.UpdateStatus(ctx, notification.ID, entity.StatusFailed)
-			continue
+		if err := s.notificationRepo.Transition(ctx, notification, event); err != nil {
+			return nil, err
 		}
+	}
 
-		forecast, err := s.weatherService.GetForecast(ctx, notification.LocationID)
+	if notification.IsExpired(time.Now(), maxDelay) && notification.CanTransitionTo(entity.StatusExpired) {
+		event, err := notification.TransitionTo(entity.StatusExpired, entity.ActorWorker, handler.ErrNotificationExpired)
 		if err != nil {
-			notification.MarkAsFailed()
-			s.notificationRepo.UpdateStatus(ctx, notification.ID, entity.StatusFailed)
-			continue
+			return nil, err
+		}
+		if err := s.notificationRepo.Transition(ctx, notification, event); err != nil {
+			return nil, err
 		}
+		return nil, handler.ErrNotificationExpired
+	}
 
-		notification.Content = *forecast
+	event, err := notification.TransitionTo(entity.StatusProcessing, entity.ActorWorker, nil)
+	if err != nil {
+		return nil, err
+	}
 
-		notification.MarkAsSent()
-		s.notificationRepo.UpdateStatus(ctx, notification.ID, entity.StatusSent)
+	if err := s.notificationRepo.Transition(ctx, notification, event); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return notification, nil
+}
+
+func (s *NotificationService) CompleteProcessing(ctx context.Context, notification *entity.Notification) error {
+	event, err := notification.MarkAsSent(entity.ActorWorker)
+	if err != nil {
+		return err
+	}
+
+	return s.notificationRepo.Transition(ctx, notification, event)
 }
 
-func (s *NotificationService) GetUserNotifications(ctx context.Context, userID uuid.UUID) ([]*entity.Notification, error) {
-	_, err := s.userRepo.FindByID(ctx, userID)
+func (s *NotificationService) FailProcessing(ctx context.Context, notification *entity.Notification, cause error) error {
+	event, err := notification.MarkAsFailed(entity.ActorWorker, cause)
 	if err != nil {
+		return err
+	}
+
+	return s.notificationRepo.Transition(ctx, notification, event)
+}
+
+func (s *NotificationService) GetEvents(ctx context.Context, id uuid.UUID) ([]*entity.NotificationEvent, error) {
+	if _, err := s.notificationRepo.FindByID(ctx, id); err != nil {
 		return nil, err
 	}
 
-	return s.notificationRepo.FindByUser(ctx, userID)
+	return s.notificationRepo.FindEvents(ctx, id)
+}
+
+// Deliver envia a notificação por cada canal habilitado do usuário e
+// registra o resultado de cada um. Só envia por um canal quem reivindica sua
+// chave de idempotência. Uma falha temporária em qualquer canal faz a
+// entrega retornar erro, para que a notificação seja retentada. Falhas
+// definitivas não são retentadas: com outro canal entregue a notificação é
+// concluída, e sem nenhum a entrega retorna ErrPermanentDelivery.
+func (s *NotificationService) Deliver(ctx context.Context, notification *entity.Notification) ([]entity.DeliveryResult, error) {
+	user, err := s.userRepo.FindByID(ctx, notification.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := user.EnabledChannels()
+	var results []entity.DeliveryResult
+	var errs []error
+	permanent := 0
+
+	for _, channel := range channels {
+		address := user.AddressFor(channel)
+
+		record, err := s.idempotencyRepo.Claim(ctx, entity.NewDeliveryIdempotency(notification.ID, channel.Channel))
+		if errors.Is(err, handler.ErrDeliveryInProgress) {
+			// Outro processamento detém o envio por este canal; a
+			// notificação é retentada depois que ele concluir ou expirar
+			errs = append(errs, fmt.Errorf("%s: %w", channel.Channel, err))
+			continue
+		}
+		if err == nil && record.AlreadySent() {
+			// Reentrega da fila após um envio concluído
+			results = append(results, *entity.NewDeliveryResult(notification.ID, channel.Channel, address, nil))
+			continue
+		}
+
+		sendErr := err
+		if sendErr == nil {
+			sendErr = s.send(ctx, notification, channel.Channel, address)
+			if sendErr != nil {
+				if err := s.idempotencyRepo.Release(ctx, record.Key); err != nil {
+					log.Printf("Erro ao liberar envio da notificação %s pelo canal %s: %v", notification.ID, channel.Channel, err)
+				}
+			} else if err := s.idempotencyRepo.MarkSent(ctx, record.Key); err != nil {
+				// Sem o registro uma reentrega repetiria o envio; a posse
+				// continua com esta tentativa até o fim da concessão
+				sendErr = fmt.Errorf("erro ao registrar envio: %w", err)
+			}
+		}
+
+		result := entity.NewDeliveryResult(notification.ID, channel.Channel, address, sendErr)
+		_ = s.deliveryRepo.Create(ctx, result)
+		results = append(results, *result)
+
+		if sendErr != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", channel.Channel, sendErr))
+			if handler.IsPermanentDeliveryError(sendErr) {
+				permanent++
+			}
+		}
+	}
+
+	switch {
+	case len(errs) == 0:
+		return results, nil
+	case permanent == len(channels):
+		return results, fmt.Errorf("%w: %w: %w", handler.ErrAllDeliveriesFailed, handler.ErrPermanentDelivery, errors.Join(errs...))
+	case permanent == len(errs):
+		// Os canais restantes foram entregues; retentar não mudaria o
+		// resultado dos que falharam, que fica registrado nas entregas
+		log.Printf("Notificação %s entregue com falha definitiva em parte dos canais: %v", notification.ID, errors.Join(errs...))
+		return results, nil
+	case len(errs) == len(channels):
+		return results, fmt.Errorf("%w: %w", handler.ErrAllDeliveriesFailed, errors.Join(errs...))
+	default:
+		// A notificação volta para a fila de retentativas; os canais já
+		// enviados são pulados pela idempotência na próxima entrega.
+		return results, fmt.Errorf("%w: %w", handler.ErrPartialDelivery, errors.Join(errs...))
+	}
+}
+
+func (s *NotificationService) send(ctx context.Context, notification *entity.Notification, channel entity.Channel, address string) error {
+	notifier, ok := s.notifiers.Get(channel)
+	if !ok {
+		return fmt.Errorf("%w: %s", handler.ErrChannelNotConfigured, channel)
+	}
+
+	if err := s.waitRateLimit(ctx, channel, address); err != nil {
+		return err
+	}
+
+	return notifier.Send(ctx, notification, address)
 }
 
-func (s *NotificationService) UpdateStatus(ctx context.Context, notification *entity.Notification) error {
-	return s.notificationRepo.UpdateStatus(ctx, notification.ID, notification.Status)
+func (s *NotificationService) waitRateLimit(ctx context.Context, channel entity.Channel, address string) error {
+	if s.rateLimiter == nil {
+		return nil
+	}
+	return s.rateLimiter.Wait(ctx, channel, address)
+}
+
+func (s *NotificationService) GetDeliveries(ctx context.Context, notificationID uuid.UUID) ([]entity.DeliveryResult, error) {
+	if _, err := s.notificationRepo.FindByID(ctx, notificationID); err != nil {
+		return nil, err
+	}
+
+	return s.deliveryRepo.FindByNotification(ctx, notificationID)
 }
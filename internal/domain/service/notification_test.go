@@ -0,0 +1,534 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeNotificationRepository guarda as notificações em memória e rejeita
+// transições cujo status de origem não confere, como o repositório real.
+type fakeNotificationRepository struct {
+	notifications map[uuid.UUID]entity.Notification
+	events        []*entity.NotificationEvent
+}
+
+func newFakeNotificationRepository(notifications ...entity.Notification) *fakeNotificationRepository {
+	r := &fakeNotificationRepository{notifications: make(map[uuid.UUID]entity.Notification)}
+	for _, n := range notifications {
+		r.notifications[n.ID] = n
+	}
+	return r
+}
+
+func (r *fakeNotificationRepository) Create(ctx context.Context, notification *entity.Notification) error {
+	r.notifications[notification.ID] = *notification
+	return nil
+}
+
+func (r *fakeNotificationRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error) {
+	n, ok := r.notifications[id]
+	if !ok {
+		return nil, handler.ErrNotFound
+	}
+	return &n, nil
+}
+
+func (r *fakeNotificationRepository) FindRecoverableNotifications(ctx context.Context, maxAttempts int) ([]*entity.Notification, error) {
+	var recoverable []*entity.Notification
+	for _, n := range r.notifications {
+		switch {
+		case n.Status == entity.StatusPending,
+			n.Status == entity.StatusFailed && n.Attempts < maxAttempts,
+			n.Status == entity.StatusProcessing:
+			recoverable = append(recoverable, &n)
+		}
+	}
+	return recoverable, nil
+}
+
+func (r *fakeNotificationRepository) Transition(ctx context.Context, notification *entity.Notification, event *entity.NotificationEvent) error {
+	if r.notifications[notification.ID].Status != event.FromStatus {
+		return handler.ErrInvalidStatusTransition
+	}
+	r.notifications[notification.ID] = *notification
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *fakeNotificationRepository) Reschedule(ctx context.Context, notification *entity.Notification, event *entity.NotificationEvent) error {
+	return r.Transition(ctx, notification, event)
+}
+
+func (r *fakeNotificationRepository) FindEvents(ctx context.Context, id uuid.UUID) ([]*entity.NotificationEvent, error) {
+	return r.events, nil
+}
+
+func (r *fakeNotificationRepository) FindByUserAndLocation(ctx context.Context, userID, locationID uuid.UUID) ([]*entity.Notification, error) {
+	return nil, nil
+}
+
+func (r *fakeNotificationRepository) List(ctx context.Context, filter entity.NotificationFilter, page entity.PageRequest) (*entity.Page[*entity.Notification], error) {
+	return &entity.Page[*entity.Notification]{}, nil
+}
+
+// fakeIdempotencyRepository reproduz a reivindicação exclusiva do
+// repositório real: só quem recebe o registro pode enviar pelo canal.
+type fakeIdempotencyRepository struct {
+	mu          sync.Mutex
+	records     map[string]*entity.DeliveryIdempotency
+	markSentErr error
+}
+
+func newFakeIdempotencyRepository() *fakeIdempotencyRepository {
+	return &fakeIdempotencyRepository{records: make(map[string]*entity.DeliveryIdempotency)}
+}
+
+func (r *fakeIdempotencyRepository) Claim(ctx context.Context, record *entity.DeliveryIdempotency) (*entity.DeliveryIdempotency, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.records[record.Key]
+	switch {
+	case !ok:
+		stored = record
+		r.records[record.Key] = stored
+	case stored.AlreadySent():
+	case stored.ClaimedAt != nil:
+		return nil, handler.ErrDeliveryInProgress
+	default:
+		stored.Attempts++
+	}
+	if !stored.AlreadySent() {
+		claimedAt := record.CreatedAt
+		stored.ClaimedAt = &claimedAt
+	}
+	copied := *stored
+	return &copied, nil
+}
+
+func (r *fakeIdempotencyRepository) MarkSent(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.markSentErr != nil {
+		return r.markSentErr
+	}
+	r.records[key].Status = entity.StatusSent
+	r.records[key].ClaimedAt = nil
+	return nil
+}
+
+func (r *fakeIdempotencyRepository) Release(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records[key].ClaimedAt = nil
+	return nil
+}
+
+type fakeDeliveryRepository struct {
+	deliveries []entity.DeliveryResult
+}
+
+func (r *fakeDeliveryRepository) Create(ctx context.Context, delivery *entity.DeliveryResult) error {
+	r.deliveries = append(r.deliveries, *delivery)
+	return nil
+}
+
+func (r *fakeDeliveryRepository) FindByNotification(ctx context.Context, notificationID uuid.UUID) ([]entity.DeliveryResult, error) {
+	return r.deliveries, nil
+}
+
+type fakeNotifier struct {
+	channel entity.Channel
+	sent    int
+	err     error
+}
+
+func (n *fakeNotifier) Channel() entity.Channel {
+	return n.channel
+}
+
+func (n *fakeNotifier) Send(ctx context.Context, notification *entity.Notification, address string) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent++
+	return nil
+}
+
+func TestNotificationService_Deliver_Idempotency(t *testing.T) {
+	user := &entity.User{
+		ID:    uuid.New(),
+		Email: "ana@exemplo.com",
+		Channels: []entity.UserChannel{
+			{Channel: entity.ChannelWebhook, Address: "http://exemplo.com/hook", Enabled: true},
+			{Channel: entity.ChannelEmail, Enabled: true},
+		},
+	}
+	notification := &entity.Notification{ID: uuid.New(), UserID: user.ID}
+
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+
+	webhook := &fakeNotifier{channel: entity.ChannelWebhook}
+	email := &fakeNotifier{channel: entity.ChannelEmail, err: errors.New("smtp indisponível")}
+	idempotencyRepo := newFakeIdempotencyRepository()
+	deliveryRepo := &fakeDeliveryRepository{}
+
+	notificationService := service.NewNotificationService(
+		nil,
+		userRepo,
+		nil,
+		deliveryRepo,
+		idempotencyRepo,
+		nil,
+		service.NewNotifierRegistry(webhook, email),
+		nil,
+	)
+	ctx := context.Background()
+
+	t.Run("primeira entrega envia e registra o canal", func(t *testing.T) {
+		results, err := notificationService.Deliver(ctx, notification)
+		assert.ErrorIs(t, err, handler.ErrPartialDelivery)
+		assert.Len(t, results, 2)
+		assert.Equal(t, 1, webhook.sent)
+
+		webhookKey := entity.DeliveryKey(notification.ID, entity.ChannelWebhook)
+		emailKey := entity.DeliveryKey(notification.ID, entity.ChannelEmail)
+		assert.Equal(t, entity.StatusSent, idempotencyRepo.records[webhookKey].Status)
+		assert.Equal(t, entity.StatusPending, idempotencyRepo.records[emailKey].Status)
+	})
+
+	t.Run("reentrega não repete canais já enviados", func(t *testing.T) {
+		email.err = nil
+
+		results, err := notificationService.Deliver(ctx, notification)
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, 1, webhook.sent)
+		assert.Equal(t, 1, email.sent)
+		assert.Len(t, deliveryRepo.deliveries, 3)
+	})
+
+	t.Run("nova reentrega não envia nada", func(t *testing.T) {
+		_, err := notificationService.Deliver(ctx, notification)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, webhook.sent)
+		assert.Equal(t, 1, email.sent)
+	})
+}
+
+func TestNotificationService_Deliver(t *testing.T) {
+	tests := []struct {
+		name        string
+		webhookErr  error
+		emailErr    error
+		expectedErr error
+		failed      []entity.Channel
+		permanent   bool
+	}{
+		{"todos os canais enviados", nil, nil, nil, nil, false},
+		{"falha em parte dos canais", nil, errors.New("smtp indisponível"), handler.ErrPartialDelivery, []entity.Channel{entity.ChannelEmail}, false},
+		{"falha em todos os canais", errors.New("timeout"), errors.New("smtp indisponível"), handler.ErrAllDeliveriesFailed, []entity.Channel{entity.ChannelWebhook, entity.ChannelEmail}, false},
+		{"falha definitiva com outro canal entregue", nil, &handler.StatusCodeError{Message: "recusado", StatusCode: 400}, nil, []entity.Channel{entity.ChannelEmail}, false},
+		{"limite de requisições é retentado", &handler.StatusCodeError{Message: "limite", StatusCode: 429}, nil, handler.ErrPartialDelivery, []entity.Channel{entity.ChannelWebhook}, false},
+		{"falha definitiva em todos os canais", &handler.StatusCodeError{Message: "não encontrado", StatusCode: 404}, handler.ErrChannelNotConfigured, handler.ErrPermanentDelivery, []entity.Channel{entity.ChannelWebhook, entity.ChannelEmail}, true},
+		{"falha definitiva e temporária", errors.New("timeout"), handler.ErrEmptyChannelAddress, handler.ErrAllDeliveriesFailed, []entity.Channel{entity.ChannelWebhook, entity.ChannelEmail}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &entity.User{
+				ID:    uuid.New(),
+				Email: "ana@exemplo.com",
+				Channels: []entity.UserChannel{
+					{Channel: entity.ChannelWebhook, Address: "http://exemplo.com/hook", Enabled: true},
+					{Channel: entity.ChannelEmail, Enabled: true},
+				},
+			}
+			notification := &entity.Notification{ID: uuid.New(), UserID: user.ID}
+
+			userRepo := new(MockUserRepository)
+			userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+			deliveryRepo := &fakeDeliveryRepository{}
+
+			notificationService := service.NewNotificationService(
+				nil,
+				userRepo,
+				nil,
+				deliveryRepo,
+				newFakeIdempotencyRepository(),
+				nil,
+				service.NewNotifierRegistry(
+					&fakeNotifier{channel: entity.ChannelWebhook, err: tt.webhookErr},
+					&fakeNotifier{channel: entity.ChannelEmail, err: tt.emailErr},
+				),
+				nil,
+			)
+
+			results, err := notificationService.Deliver(context.Background(), notification)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.permanent, errors.Is(err, handler.ErrPermanentDelivery))
+
+			var failed []entity.Channel
+			for _, result := range results {
+				if result.Status == entity.StatusFailed {
+					failed = append(failed, result.Channel)
+				}
+			}
+			assert.Equal(t, tt.failed, failed)
+			assert.Len(t, deliveryRepo.deliveries, 2)
+		})
+	}
+}
+
+// blockingNotifier segura o envio até o teste liberar, mantendo a
+// reivindicação do canal enquanto outra entrega concorre por ela.
+type blockingNotifier struct {
+	started chan struct{}
+	release chan struct{}
+	mu      sync.Mutex
+	sent    int
+}
+
+func (n *blockingNotifier) Channel() entity.Channel {
+	return entity.ChannelWebhook
+}
+
+func (n *blockingNotifier) Send(ctx context.Context, notification *entity.Notification, address string) error {
+	n.started <- struct{}{}
+	<-n.release
+	n.mu.Lock()
+	n.sent++
+	n.mu.Unlock()
+	return nil
+}
+
+func TestNotificationService_Deliver_ConcurrentClaims(t *testing.T) {
+	user := &entity.User{
+		ID: uuid.New(),
+		Channels: []entity.UserChannel{
+			{Channel: entity.ChannelWebhook, Address: "http://exemplo.com/hook", Enabled: true},
+		},
+	}
+	notification := &entity.Notification{ID: uuid.New(), UserID: user.ID}
+
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+	webhook := &blockingNotifier{started: make(chan struct{}, 2), release: make(chan struct{})}
+	idempotencyRepo := newFakeIdempotencyRepository()
+
+	notificationService := service.NewNotificationService(
+		nil,
+		userRepo,
+		nil,
+		&fakeDeliveryRepository{},
+		idempotencyRepo,
+		nil,
+		service.NewNotifierRegistry(webhook),
+		nil,
+	)
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := notificationService.Deliver(context.Background(), notification)
+		first <- err
+	}()
+	<-webhook.started
+
+	// A segunda entrega encontra o canal reivindicado e não envia
+	_, err := notificationService.Deliver(context.Background(), notification)
+	assert.ErrorIs(t, err, handler.ErrDeliveryInProgress)
+
+	close(webhook.release)
+	assert.NoError(t, <-first)
+	assert.Equal(t, 1, webhook.sent)
+
+	// Concluído o envio, uma nova reentrega apenas o reconhece
+	_, err = notificationService.Deliver(context.Background(), notification)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, webhook.sent)
+}
+
+func TestNotificationService_Deliver_MarkSentFailure(t *testing.T) {
+	user := &entity.User{
+		ID: uuid.New(),
+		Channels: []entity.UserChannel{
+			{Channel: entity.ChannelWebhook, Address: "http://exemplo.com/hook", Enabled: true},
+		},
+	}
+	notification := &entity.Notification{ID: uuid.New(), UserID: user.ID}
+
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+	webhook := &fakeNotifier{channel: entity.ChannelWebhook}
+	dbErr := errors.New("conexão perdida")
+	idempotencyRepo := newFakeIdempotencyRepository()
+	idempotencyRepo.markSentErr = dbErr
+
+	notificationService := service.NewNotificationService(
+		nil,
+		userRepo,
+		nil,
+		&fakeDeliveryRepository{},
+		idempotencyRepo,
+		nil,
+		service.NewNotifierRegistry(webhook),
+		nil,
+	)
+
+	results, err := notificationService.Deliver(context.Background(), notification)
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Equal(t, 1, webhook.sent)
+	assert.Equal(t, entity.StatusFailed, results[0].Status)
+
+	// A posse continua com a tentativa que enviou, evitando o reenvio
+	_, err = notificationService.Deliver(context.Background(), notification)
+	assert.ErrorIs(t, err, handler.ErrDeliveryInProgress)
+	assert.Equal(t, 1, webhook.sent)
+}
+
+func TestNotificationService_StartProcessing(t *testing.T) {
+	scheduledFor := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	expiration := time.Hour
+
+	tests := []struct {
+		name           string
+		status         entity.NotificationStatus
+		scheduledFor   time.Time
+		queuedFor      time.Time
+		expectedErr    error
+		expectedStatus entity.NotificationStatus
+	}{
+		{"pendente inicia processamento", entity.StatusPending, scheduledFor, scheduledFor, nil, entity.StatusProcessing},
+		{"falha volta a processar", entity.StatusFailed, scheduledFor, scheduledFor, nil, entity.StatusProcessing},
+		{"reentrega durante processamento é descartada", entity.StatusProcessing, scheduledFor, scheduledFor, handler.ErrNotificationInProgress, entity.StatusProcessing},
+		{"cancelada é descartada", entity.StatusCanceled, scheduledFor, scheduledFor, handler.ErrInvalidStatusTransition, entity.StatusCanceled},
+		{"enviada é descartada", entity.StatusSent, scheduledFor, scheduledFor, handler.ErrInvalidStatusTransition, entity.StatusSent},
+		{"mensagem de horário anterior ao reagendamento", entity.StatusPending, scheduledFor.Add(time.Hour), scheduledFor, handler.ErrNotificationRescheduled, entity.StatusPending},
+		{"atrasada além do limite expira", entity.StatusPending, scheduledFor.Add(-2 * time.Hour), scheduledFor.Add(-2 * time.Hour), handler.ErrNotificationExpired, entity.StatusExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := entity.Notification{ID: uuid.New(), Status: tt.status, ScheduledFor: tt.scheduledFor, UpdatedAt: time.Now()}
+			repo := newFakeNotificationRepository(stored)
+			notificationService := service.NewNotificationService(repo, nil, nil, nil, nil, nil, nil, nil)
+
+			queued := &entity.Notification{ID: stored.ID, Status: entity.StatusPending, ScheduledFor: tt.queuedFor}
+			notification, err := notificationService.StartProcessing(context.Background(), queued, expiration)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, notification)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, entity.StatusProcessing, notification.Status)
+			}
+			assert.Equal(t, tt.expectedStatus, repo.notifications[stored.ID].Status)
+		})
+	}
+}
+
+// staleNotificationRepository devolve sempre a mesma leitura, como dois
+// workers que consultam a notificação antes de qualquer um alterá-la.
+type staleNotificationRepository struct {
+	*fakeNotificationRepository
+	snapshot entity.Notification
+}
+
+func (r *staleNotificationRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error) {
+	n := r.snapshot
+	return &n, nil
+}
+
+func TestNotificationService_StartProcessing_Concurrent(t *testing.T) {
+	stored := entity.Notification{ID: uuid.New(), Status: entity.StatusPending, ScheduledFor: time.Now().Add(-time.Minute)}
+	repo := &staleNotificationRepository{fakeNotificationRepository: newFakeNotificationRepository(stored), snapshot: stored}
+	notificationService := service.NewNotificationService(repo, nil, nil, nil, nil, nil, nil, nil)
+
+	first, err := notificationService.StartProcessing(context.Background(), &stored, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusProcessing, first.Status)
+
+	// A segunda leu PENDENTE, mas a troca condicionada ao status falha
+	second, err := notificationService.StartProcessing(context.Background(), &stored, time.Hour)
+	assert.ErrorIs(t, err, handler.ErrInvalidStatusTransition)
+	assert.Nil(t, second)
+	assert.Len(t, repo.events, 1)
+}
+
+func TestNotificationService_StartProcessing_Abandoned(t *testing.T) {
+	stored := entity.Notification{
+		ID:           uuid.New(),
+		Status:       entity.StatusProcessing,
+		ScheduledFor: time.Now().Add(-time.Hour).UTC().Truncate(time.Second),
+		UpdatedAt:    time.Now().Add(-time.Hour),
+	}
+	repo := newFakeNotificationRepository(stored)
+	notificationService := service.NewNotificationService(repo, nil, nil, nil, nil, nil, nil, nil)
+
+	notification, err := notificationService.StartProcessing(context.Background(), &stored, 24*time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusProcessing, notification.Status)
+	assert.Len(t, repo.events, 2)
+	assert.Equal(t, entity.StatusFailed, repo.events[0].ToStatus)
+	assert.Equal(t, handler.ErrProcessingAbandoned.Error(), repo.events[0].Error)
+	assert.Equal(t, entity.StatusProcessing, repo.events[1].ToStatus)
+}
+
+func TestNotificationService_Lifecycle(t *testing.T) {
+	stored := entity.Notification{ID: uuid.New(), Status: entity.StatusPending, ScheduledFor: time.Now().Add(time.Hour)}
+	repo := newFakeNotificationRepository(stored)
+	notificationService := service.NewNotificationService(repo, nil, nil, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	t.Run("reagenda notificação pendente", func(t *testing.T) {
+		scheduledFor := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Second)
+
+		notification, err := notificationService.Reschedule(ctx, stored.ID, scheduledFor, entity.ActorAPI)
+		assert.NoError(t, err)
+		assert.Equal(t, scheduledFor, notification.ScheduledFor)
+		assert.Equal(t, entity.StatusPending, repo.notifications[stored.ID].Status)
+	})
+
+	t.Run("cancela notificação pendente", func(t *testing.T) {
+		notification, err := notificationService.Cancel(ctx, stored.ID, entity.ActorAPI)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.StatusCanceled, notification.Status)
+	})
+
+	t.Run("não cancela duas vezes", func(t *testing.T) {
+		_, err := notificationService.Cancel(ctx, stored.ID, entity.ActorAPI)
+		assert.ErrorIs(t, err, handler.ErrInvalidStatusTransition)
+	})
+
+	t.Run("histórico registra as mudanças", func(t *testing.T) {
+		events, err := notificationService.GetEvents(ctx, stored.ID)
+		assert.NoError(t, err)
+		assert.Len(t, events, 2)
+		assert.Equal(t, entity.StatusCanceled, events[1].ToStatus)
+		assert.Equal(t, entity.ActorAPI, events[1].Actor)
+	})
+
+	t.Run("notificação inexistente", func(t *testing.T) {
+		_, err := notificationService.GetEvents(ctx, uuid.New())
+		assert.ErrorIs(t, err, handler.ErrNotFound)
+	})
+}
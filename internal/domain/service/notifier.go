@@ -6,5 +6,31 @@ import (
 )
 
 type Notifier interface {
-	Send(ctx context.Context, notification *entity.Notification) error
+	Channel() entity.Channel
+	Send(ctx context.Context, notification *entity.Notification, address string) error
+}
+
+type NotifierRegistry struct {
+	notifiers map[entity.Channel]Notifier
+}
+
+func NewNotifierRegistry(notifiers ...Notifier) *NotifierRegistry {
+	registry := &NotifierRegistry{
+		notifiers: make(map[entity.Channel]Notifier),
+	}
+
+	for _, notifier := range notifiers {
+		registry.Register(notifier)
+	}
+
+	return registry
+}
+
+func (r *NotifierRegistry) Register(notifier Notifier) {
+	r.notifiers[notifier.Channel()] = notifier
+}
+
+func (r *NotifierRegistry) Get(channel entity.Channel) (Notifier, bool) {
+	notifier, ok := r.notifiers[channel]
+	return notifier, ok
 }
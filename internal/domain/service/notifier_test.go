@@ -0,0 +1,48 @@
+package service_test
+
+import (
+	"testing"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/domain/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifierRegistry(t *testing.T) {
+	webhook := &fakeNotifier{channel: entity.ChannelWebhook}
+	email := &fakeNotifier{channel: entity.ChannelEmail}
+	registry := service.NewNotifierRegistry(webhook)
+
+	tests := []struct {
+		name     string
+		channel  entity.Channel
+		expected service.Notifier
+	}{
+		{"canal registrado", entity.ChannelWebhook, webhook},
+		{"canal não configurado", entity.ChannelSMS, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier, ok := registry.Get(tt.channel)
+			assert.Equal(t, tt.expected != nil, ok)
+			if tt.expected != nil {
+				assert.Same(t, tt.expected, notifier)
+			}
+		})
+	}
+
+	t.Run("registro posterior substitui o canal", func(t *testing.T) {
+		replacement := &fakeNotifier{channel: entity.ChannelWebhook}
+		registry.Register(email)
+		registry.Register(replacement)
+
+		notifier, ok := registry.Get(entity.ChannelWebhook)
+		assert.True(t, ok)
+		assert.Same(t, replacement, notifier)
+
+		notifier, ok = registry.Get(entity.ChannelEmail)
+		assert.True(t, ok)
+		assert.Same(t, email, notifier)
+	})
+}
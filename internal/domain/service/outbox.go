@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/domain/repository"
+)
+
+type OutboxRelayService struct {
+	outboxRepo   repository.OutboxRepository
+	queueService QueueService
+	batchSize    int
+}
+
+func NewOutboxRelayService(
+	outboxRepo repository.OutboxRepository,
+	queueService QueueService,
+	batchSize int,
+) *OutboxRelayService {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return &OutboxRelayService{
+		outboxRepo:   outboxRepo,
+		queueService: queueService,
+		batchSize:    batchSize,
+	}
+}
+
+// RelayPending publica as mensagens pendentes do outbox até esvaziá-lo.
+// Uma mensagem só é marcada como despachada após a publicação, então uma
+// falha entre as duas etapas gera reenvio (entrega at-least-once).
+func (s *OutboxRelayService) RelayPending(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		dispatched, err := s.outboxRepo.DispatchPending(ctx, s.batchSize, func(message *entity.OutboxMessage) error {
+			return s.publish(ctx, message)
+		})
+		total += dispatched
+		if err != nil {
+			return total, err
+		}
+		if dispatched < s.batchSize {
+			return total, nil
+		}
+	}
+}
+
+func (s *OutboxRelayService) publish(ctx context.Context, message *entity.OutboxMessage) error {
+	switch message.EventType {
+	case entity.EventNotificationScheduled:
+		notification, err := message.Notification()
+		if err != nil {
+			return err
+		}
+		return s.queueService.PublishNotification(ctx, notification)
+	default:
+		return fmt.Errorf("tipo de evento desconhecido no outbox: %s", message.EventType)
+	}
+}
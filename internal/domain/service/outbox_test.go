@@ -0,0 +1,90 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockQueueService struct {
+	mock.Mock
+}
+
+func (m *MockQueueService) PublishNotification(ctx context.Context, notification *entity.Notification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+func (m *MockQueueService) ConsumeNotifications(ctx context.Context, handler func(*entity.Notification) error) error {
+	args := m.Called(ctx, handler)
+	return args.Error(0)
+}
+
+func (m *MockQueueService) Close() error {
+	return m.Called().Error(0)
+}
+
+type fakeOutboxRepository struct {
+	pending    []*entity.OutboxMessage
+	dispatched []uuid.UUID
+	attempts   map[uuid.UUID]int
+}
+
+func (r *fakeOutboxRepository) DispatchPending(ctx context.Context, limit int, publish func(*entity.OutboxMessage) error) (int, error) {
+	dispatched := 0
+	var remaining []*entity.OutboxMessage
+	for i, message := range r.pending {
+		if i >= limit {
+			remaining = append(remaining, message)
+			continue
+		}
+		if err := publish(message); err != nil {
+			r.attempts[message.ID]++
+			remaining = append(remaining, message)
+			continue
+		}
+		r.dispatched = append(r.dispatched, message.ID)
+		dispatched++
+	}
+	r.pending = remaining
+	return dispatched, nil
+}
+
+func TestOutboxRelayService_RelayPending(t *testing.T) {
+	first, _ := entity.NewNotificationOutboxMessage(&entity.Notification{ID: uuid.New(), Status: entity.StatusPending})
+	second, _ := entity.NewNotificationOutboxMessage(&entity.Notification{ID: uuid.New(), Status: entity.StatusPending})
+	third, _ := entity.NewNotificationOutboxMessage(&entity.Notification{ID: uuid.New(), Status: entity.StatusPending})
+
+	repo := &fakeOutboxRepository{
+		pending:  []*entity.OutboxMessage{first, second, third},
+		attempts: make(map[uuid.UUID]int),
+	}
+
+	queue := new(MockQueueService)
+	queue.On("PublishNotification", mock.Anything, mock.MatchedBy(func(n *entity.Notification) bool {
+		return n.ID == second.AggregateID
+	})).Return(errors.New("broker indisponível"))
+	queue.On("PublishNotification", mock.Anything, mock.Anything).Return(nil)
+
+	relay := service.NewOutboxRelayService(repo, queue, 2)
+
+	// O lote com falha interrompe o ciclo; o restante sai no próximo
+	dispatched, err := relay.RelayPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dispatched)
+
+	dispatched, err = relay.RelayPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dispatched)
+
+	assert.ElementsMatch(t, []uuid.UUID{first.ID, third.ID}, repo.dispatched)
+	assert.Len(t, repo.pending, 1)
+	assert.Equal(t, second.ID, repo.pending[0].ID)
+	assert.Equal(t, 2, repo.attempts[second.ID])
+}
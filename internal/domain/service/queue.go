@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"time"
 	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/domain/repository"
 )
 
 type QueueService interface {
@@ -10,3 +13,41 @@ type QueueService interface {
 	ConsumeNotifications(ctx context.Context, handler func(*entity.Notification) error) error
 	Close() error
 }
+
+// RecoverableQueue é uma fila sem persistência, como a em memória, que é
+// repovoada a partir do banco na inicialização.
+type RecoverableQueue interface {
+	QueueService
+	// RequeueNotification publica a notificação para ser consumida a partir
+	// de availableAt, considerando as tentativas já registradas nela.
+	RequeueNotification(ctx context.Context, notification *entity.Notification, availableAt time.Time) error
+	// MaxAttempts é o número de tentativas de entrega antes da DLQ.
+	MaxAttempts() int
+}
+
+// RequeueNotifications republica as notificações que a fila perdeu ao
+// reiniciar, enquanto o outbox já as marcou como despachadas: as PENDENTE,
+// as em FALHA que aguardavam uma retentativa e as EM_PROCESSAMENTO
+// abandonadas pela queda. Estas só ficam disponíveis quando vence
+// processingLease, pois antes disso o worker as descartaria como em
+// andamento. Uma notificação publicada duas vezes é processada uma única
+// vez, pois cada início de processamento é condicionado ao status no banco.
+func RequeueNotifications(ctx context.Context, notificationRepo repository.NotificationRepository, queue RecoverableQueue) (int, error) {
+	notifications, err := notificationRepo.FindRecoverableNotifications(ctx, queue.MaxAttempts())
+	if err != nil {
+		return 0, err
+	}
+
+	for i, notification := range notifications {
+		availableAt := notification.ScheduledFor
+		if notification.Status == entity.StatusProcessing {
+			availableAt = notification.UpdatedAt.Add(processingLease)
+		}
+
+		if err := queue.RequeueNotification(ctx, notification, availableAt); err != nil {
+			return i, fmt.Errorf("erro ao republicar notificação %s: %w", notification.ID, err)
+		}
+	}
+
+	return len(notifications), nil
+}
@@ -0,0 +1,71 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecoverableQueue struct {
+	MockQueueService
+}
+
+func (m *MockRecoverableQueue) RequeueNotification(ctx context.Context, notification *entity.Notification, availableAt time.Time) error {
+	args := m.Called(ctx, notification, availableAt)
+	return args.Error(0)
+}
+
+func (m *MockRecoverableQueue) MaxAttempts() int {
+	return m.Called().Int(0)
+}
+
+func TestRequeueNotifications(t *testing.T) {
+	now := time.Now()
+	future := entity.Notification{ID: uuid.New(), Status: entity.StatusPending, ScheduledFor: now.Add(48 * time.Hour)}
+	due := entity.Notification{ID: uuid.New(), Status: entity.StatusPending, ScheduledFor: now.Add(-time.Minute)}
+	sent := entity.Notification{ID: uuid.New(), Status: entity.StatusSent, ScheduledFor: now.Add(-time.Hour)}
+	retrying := entity.Notification{ID: uuid.New(), Status: entity.StatusFailed, Attempts: 2, ScheduledFor: now.Add(-time.Hour)}
+	exhausted := entity.Notification{ID: uuid.New(), Status: entity.StatusFailed, Attempts: 4, ScheduledFor: now.Add(-time.Hour)}
+	abandoned := entity.Notification{ID: uuid.New(), Status: entity.StatusProcessing, Attempts: 1, ScheduledFor: now.Add(-time.Hour), UpdatedAt: now.Add(-5 * time.Minute)}
+
+	t.Run("republica pendentes, falhas com tentativas restantes e processamentos abandonados", func(t *testing.T) {
+		queue := new(MockRecoverableQueue)
+		queue.On("MaxAttempts").Return(4)
+		for _, n := range []entity.Notification{future, due, retrying} {
+			id, scheduledFor := n.ID, n.ScheduledFor
+			queue.On("RequeueNotification", mock.Anything, mock.MatchedBy(func(n *entity.Notification) bool {
+				return n.ID == id
+			}), scheduledFor).Return(nil).Once()
+		}
+		// O processamento abandonado só fica disponível quando vence a
+		// concessão, senão o worker o descartaria como em andamento
+		queue.On("RequeueNotification", mock.Anything, mock.MatchedBy(func(n *entity.Notification) bool {
+			return n.ID == abandoned.ID
+		}), abandoned.UpdatedAt.Add(15*time.Minute)).Return(nil).Once()
+
+		repo := newFakeNotificationRepository(future, due, sent, retrying, exhausted, abandoned)
+		requeued, err := service.RequeueNotifications(context.Background(), repo, queue)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, requeued)
+		queue.AssertExpectations(t)
+	})
+
+	t.Run("falha na publicação", func(t *testing.T) {
+		queue := new(MockRecoverableQueue)
+		queue.On("MaxAttempts").Return(4)
+		queue.On("RequeueNotification", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("fila fechada"))
+
+		requeued, err := service.RequeueNotifications(context.Background(), newFakeNotificationRepository(future), queue)
+
+		assert.Error(t, err)
+		assert.Equal(t, 0, requeued)
+	})
+}
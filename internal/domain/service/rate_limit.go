@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"weather-notification/internal/domain/entity"
+)
+
+const maxIdleRateBuckets = 1024
+
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// ParseRateLimits lê limites no formato "WEBHOOK=10/s,SLACK=1/s,EMAIL=30/m".
+func ParseRateLimits(value string) (map[entity.Channel]RateLimit, error) {
+	limits := make(map[entity.Channel]RateLimit)
+	if strings.TrimSpace(value) == "" {
+		return limits, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		channel, rate, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			return nil, fmt.Errorf("limite de envio inválido: %q", item)
+		}
+
+		requests, unit, ok := strings.Cut(rate, "/")
+		if !ok {
+			return nil, fmt.Errorf("limite de envio inválido: %q", item)
+		}
+
+		n, err := strconv.Atoi(requests)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("limite de envio inválido: %q", item)
+		}
+
+		per, err := parseRateUnit(unit)
+		if err != nil {
+			return nil, fmt.Errorf("limite de envio inválido: %q", item)
+		}
+
+		limits[entity.Channel(strings.ToUpper(channel))] = RateLimit{Requests: n, Per: per}
+	}
+
+	return limits, nil
+}
+
+func parseRateUnit(unit string) (time.Duration, error) {
+	switch unit {
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	default:
+		return time.ParseDuration(unit)
+	}
+}
+
+type tokenBucket struct {
+	limit   RateLimit
+	tokens  float64
+	updated time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	perToken := b.limit.Per / time.Duration(b.limit.Requests)
+	b.tokens += float64(now.Sub(b.updated)) / float64(perToken)
+	if capacity := float64(b.limit.Requests); b.tokens > capacity {
+		b.tokens = capacity
+	}
+	b.updated = now
+}
+
+// DeliveryRateLimiter limita o ritmo de envios para cada destino (canal e
+// endereço), para que um webhook lento ou com cota não seja sobrecarregado
+// quando vários workers entregam ao mesmo tempo.
+type DeliveryRateLimiter struct {
+	mu      sync.Mutex
+	limits  map[entity.Channel]RateLimit
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+func NewDeliveryRateLimiter(limits map[entity.Channel]RateLimit) *DeliveryRateLimiter {
+	return &DeliveryRateLimiter{
+		limits:  limits,
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Wait bloqueia até haver cota para enviar ao destino ou o contexto ser
+// cancelado. Canais sem limite configurado não aguardam.
+func (l *DeliveryRateLimiter) Wait(ctx context.Context, channel entity.Channel, address string) error {
+	delay, ok := l.reserve(channel, address)
+	if !ok || delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve consome uma ficha do balde do destino e retorna quanto tempo falta
+// para ela estar disponível.
+func (l *DeliveryRateLimiter) reserve(channel entity.Channel, address string) (time.Duration, bool) {
+	limit, ok := l.limits[channel]
+	if !ok {
+		return 0, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	key := string(channel) + ":" + address
+	bucket, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxIdleRateBuckets {
+			l.pruneFull(now)
+		}
+		bucket = &tokenBucket{limit: limit, tokens: float64(limit.Requests), updated: now}
+		l.buckets[key] = bucket
+	}
+
+	bucket.refill(now)
+	bucket.tokens--
+
+	if bucket.tokens >= 0 {
+		return 0, true
+	}
+	perToken := limit.Per / time.Duration(limit.Requests)
+	return time.Duration(-bucket.tokens * float64(perToken)), true
+}
+
+// pruneFull descarta os baldes que já recuperaram toda a cota, pois
+// equivalem a um balde novo.
+func (l *DeliveryRateLimiter) pruneFull(now time.Time) {
+	for key, bucket := range l.buckets {
+		bucket.refill(now)
+		if bucket.tokens >= float64(bucket.limit.Requests) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/domain/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected map[entity.Channel]service.RateLimit
+		wantErr  bool
+	}{
+		{
+			name:     "vazio",
+			value:    "",
+			expected: map[entity.Channel]service.RateLimit{},
+		},
+		{
+			name:  "vários canais",
+			value: "WEBHOOK=10/s, slack=1/s,EMAIL=30/m",
+			expected: map[entity.Channel]service.RateLimit{
+				entity.ChannelWebhook: {Requests: 10, Per: time.Second},
+				entity.ChannelSlack:   {Requests: 1, Per: time.Second},
+				entity.ChannelEmail:   {Requests: 30, Per: time.Minute},
+			},
+		},
+		{
+			name:     "intervalo como duração",
+			value:    "SMS=5/10s",
+			expected: map[entity.Channel]service.RateLimit{entity.ChannelSMS: {Requests: 5, Per: 10 * time.Second}},
+		},
+		{name: "sem taxa", value: "WEBHOOK", wantErr: true},
+		{name: "quantidade inválida", value: "WEBHOOK=0/s", wantErr: true},
+		{name: "unidade inválida", value: "WEBHOOK=10/semana", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits, err := service.ParseRateLimits(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, limits)
+		})
+	}
+}
+
+func TestDeliveryRateLimiter_Wait(t *testing.T) {
+	limiter := service.NewDeliveryRateLimiter(map[entity.Channel]service.RateLimit{
+		entity.ChannelWebhook: {Requests: 2, Per: 100 * time.Millisecond},
+	})
+	ctx := context.Background()
+
+	t.Run("cota inicial não aguarda", func(t *testing.T) {
+		start := time.Now()
+		assert.NoError(t, limiter.Wait(ctx, entity.ChannelWebhook, "http://a"))
+		assert.NoError(t, limiter.Wait(ctx, entity.ChannelWebhook, "http://a"))
+		assert.Less(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("destinos têm cotas independentes", func(t *testing.T) {
+		start := time.Now()
+		assert.NoError(t, limiter.Wait(ctx, entity.ChannelWebhook, "http://b"))
+		assert.Less(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("canal sem limite não aguarda", func(t *testing.T) {
+		start := time.Now()
+		for i := 0; i < 10; i++ {
+			assert.NoError(t, limiter.Wait(ctx, entity.ChannelSlack, "#geral"))
+		}
+		assert.Less(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("cota esgotada aguarda a reposição", func(t *testing.T) {
+		start := time.Now()
+		assert.NoError(t, limiter.Wait(ctx, entity.ChannelWebhook, "http://a"))
+		assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+	})
+
+	t.Run("contexto cancelado interrompe a espera", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+		defer cancel()
+		limiter.Wait(ctx, entity.ChannelWebhook, "http://c")
+		limiter.Wait(ctx, entity.ChannelWebhook, "http://c")
+		assert.ErrorIs(t, limiter.Wait(ctx, entity.ChannelWebhook, "http://c"), context.DeadlineExceeded)
+	})
+}
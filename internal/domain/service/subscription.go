@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type SubscriptionService struct {
+	subscriptionRepo repository.SubscriptionRepository
+	userRepo         repository.UserRepository
+	notificationRepo repository.NotificationRepository
+	weatherService   *WeatherService
+}
+
+func NewSubscriptionService(
+	subscriptionRepo repository.SubscriptionRepository,
+	userRepo repository.UserRepository,
+	notificationRepo repository.NotificationRepository,
+	weatherService *WeatherService,
+) *SubscriptionService {
+	return &SubscriptionService{
+		subscriptionRepo: subscriptionRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		weatherService:   weatherService,
+	}
+}
+
+func (s *SubscriptionService) Create(ctx context.Context, userID, locationID uuid.UUID, cronExpression, timezone string) (*entity.Subscription, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if locationID == uuid.Nil {
+		locationID = user.LocationID
+	}
+
+	if timezone == "" {
+		timezone = user.Timezone
+	}
+
+	subscription, err := entity.NewSubscription(userID, locationID, cronExpression, timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepo.Create(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (s *SubscriptionService) Get(ctx context.Context, userID, subscriptionID uuid.UUID) (*entity.Subscription, error) {
+	return s.subscriptionRepo.FindByID(ctx, userID, subscriptionID)
+}
+
+func (s *SubscriptionService) ListByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Subscription, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.subscriptionRepo.FindByUser(ctx, userID)
+}
+
+func (s *SubscriptionService) Update(
+	ctx context.Context,
+	userID, subscriptionID, locationID uuid.UUID,
+	cronExpression, timezone string,
+	active *bool,
+) (*entity.Subscription, error) {
+	subscription, err := s.subscriptionRepo.FindByID(ctx, userID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if locationID != uuid.Nil {
+		subscription.LocationID = locationID
+	}
+
+	if timezone != "" {
+		if _, err := entity.LoadTimezone(timezone); err != nil {
+			return nil, err
+		}
+		subscription.Timezone = timezone
+		if cronExpression == "" {
+			cronExpression = subscription.CronExpression
+		}
+	}
+
+	if cronExpression != "" {
+		if err := subscription.Reschedule(cronExpression, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if active != nil {
+		if *active && !subscription.Active && cronExpression == "" {
+			if err := subscription.Reschedule(subscription.CronExpression, now); err != nil {
+				return nil, err
+			}
+		}
+		subscription.Active = *active
+	}
+	subscription.UpdatedAt = now.UTC()
+
+	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (s *SubscriptionService) Delete(ctx context.Context, userID, subscriptionID uuid.UUID) error {
+	return s.subscriptionRepo.Delete(ctx, userID, subscriptionID)
+}
+
+func (s *SubscriptionService) ProcessDueSubscriptions(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	subscriptions, err := s.subscriptionRepo.FindDue(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		// Expressões gravadas antes da validação de datas impossíveis nunca
+		// avançam; desativá-las evita uma notificação a cada verificação.
+		if _, err := entity.ParseCronExpression(subscription.CronExpression); err != nil {
+			log.Printf("Desativando inscrição %s com agendamento inválido %q: %v", subscription.ID, subscription.CronExpression, err)
+			subscription.Active = false
+			subscription.UpdatedAt = now
+			if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := s.materialize(ctx, subscription, now); err != nil {
+			continue
+		}
+
+		if err := subscription.Advance(now); err != nil {
+			continue
+		}
+
+		if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SubscriptionService) materialize(ctx context.Context, subscription *entity.Subscription, now time.Time) error {
+	user, err := s.userRepo.FindByID(ctx, subscription.UserID)
+	if err != nil {
+		return err
+	}
+	if user.OptOut {
+		return nil
+	}
+
+	forecast, err := s.weatherService.GetForecastForDays(ctx, subscription.LocationID, user.ForecastDays)
+	if err != nil {
+		return err
+	}
+
+	scheduledFor := subscription.NextRunAt
+	if scheduledFor.Before(now) {
+		scheduledFor = now
+	}
+
+	notification, err := entity.NewNotification(user.ID, subscription.LocationID, *forecast, scheduledFor)
+	if err != nil {
+		return err
+	}
+
+	return s.notificationRepo.Create(ctx, notification)
+}
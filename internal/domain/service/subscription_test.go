@@ -0,0 +1,169 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSubscriptionRepository struct {
+	subscriptions map[uuid.UUID]*entity.Subscription
+}
+
+func newFakeSubscriptionRepository(subscriptions ...*entity.Subscription) *fakeSubscriptionRepository {
+	r := &fakeSubscriptionRepository{subscriptions: make(map[uuid.UUID]*entity.Subscription)}
+	for _, subscription := range subscriptions {
+		r.subscriptions[subscription.ID] = subscription
+	}
+	return r
+}
+
+func (r *fakeSubscriptionRepository) Create(ctx context.Context, subscription *entity.Subscription) error {
+	r.subscriptions[subscription.ID] = subscription
+	return nil
+}
+
+func (r *fakeSubscriptionRepository) Update(ctx context.Context, subscription *entity.Subscription) error {
+	r.subscriptions[subscription.ID] = subscription
+	return nil
+}
+
+func (r *fakeSubscriptionRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*entity.Subscription, error) {
+	subscription, ok := r.subscriptions[id]
+	if !ok || subscription.UserID != userID {
+		return nil, handler.ErrNotFound
+	}
+	return subscription, nil
+}
+
+func (r *fakeSubscriptionRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Subscription, error) {
+	var found []*entity.Subscription
+	for _, subscription := range r.subscriptions {
+		if subscription.UserID == userID {
+			found = append(found, subscription)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeSubscriptionRepository) FindDue(ctx context.Context, now time.Time) ([]*entity.Subscription, error) {
+	var due []*entity.Subscription
+	for _, subscription := range r.subscriptions {
+		if subscription.IsDue(now) {
+			due = append(due, subscription)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeSubscriptionRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	delete(r.subscriptions, id)
+	return nil
+}
+
+func TestSubscriptionService_ImpossibleDates(t *testing.T) {
+	user := &entity.User{ID: uuid.New(), LocationID: uuid.New(), Timezone: "America/Sao_Paulo"}
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+
+	tests := []struct {
+		name       string
+		expression string
+	}{
+		{"30 de fevereiro", "0 0 30 2 *"},
+		{"31 de abril", "0 0 31 4 *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing, err := entity.NewSubscription(user.ID, user.LocationID, "0 7 * * *", user.Timezone)
+			assert.NoError(t, err)
+			subscriptionRepo := newFakeSubscriptionRepository(existing)
+			subscriptionService := service.NewSubscriptionService(subscriptionRepo, userRepo, newFakeNotificationRepository(), nil)
+
+			created, err := subscriptionService.Create(context.Background(), user.ID, uuid.Nil, tt.expression, "")
+			assert.ErrorIs(t, err, handler.ErrInvalidCronExpression)
+			assert.Nil(t, created)
+			assert.Len(t, subscriptionRepo.subscriptions, 1)
+
+			nextRunAt := existing.NextRunAt
+			_, err = subscriptionService.Update(context.Background(), user.ID, existing.ID, uuid.Nil, tt.expression, "", nil)
+			assert.ErrorIs(t, err, handler.ErrInvalidCronExpression)
+			assert.Equal(t, "0 7 * * *", existing.CronExpression)
+			assert.Equal(t, nextRunAt, existing.NextRunAt)
+		})
+	}
+}
+
+func TestSubscriptionService_ProcessDueSubscriptions_DisablesImpossibleSchedule(t *testing.T) {
+	user := &entity.User{ID: uuid.New(), LocationID: uuid.New(), Timezone: "America/Sao_Paulo"}
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+
+	legacy, err := entity.NewSubscription(user.ID, user.LocationID, "0 7 * * *", user.Timezone)
+	assert.NoError(t, err)
+	legacy.CronExpression = "0 0 30 2 *"
+	legacy.NextRunAt = time.Now().Add(-time.Minute).UTC()
+
+	subscriptionRepo := newFakeSubscriptionRepository(legacy)
+	notificationRepo := newFakeNotificationRepository()
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, userRepo, notificationRepo, nil)
+
+	assert.NoError(t, subscriptionService.ProcessDueSubscriptions(context.Background()))
+
+	assert.False(t, legacy.Active)
+	assert.Empty(t, notificationRepo.notifications)
+}
+
+func TestSubscriptionService_ProcessDueSubscriptions_UsesForecastDays(t *testing.T) {
+	campinas := &entity.Location{ID: uuid.New(), CPTECCode: 1, Name: "Campinas", State: "SP"}
+	weatherService := service.NewWeatherService(
+		&fakeCPTECClient{cities: map[int]string{1: "Campinas"}},
+		&fakeLocationRepository{locations: map[uuid.UUID]*entity.Location{campinas.ID: campinas}},
+		nil,
+	)
+
+	tests := []struct {
+		name            string
+		forecastDays    int
+		expectedHorizon entity.ForecastHorizon
+		expectedDays    int
+	}{
+		{"horizonte padrão", entity.DefaultForecastDays, entity.ForecastHorizon4Days, 1},
+		{"cinco dias usa a previsão de 7 dias", 5, entity.ForecastHorizon7Days, 5},
+		{"sete dias", 7, entity.ForecastHorizon7Days, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := entity.NewUser("Matheus", "matheus@exemplo.com", campinas.ID)
+			require.NoError(t, err)
+			require.NoError(t, user.SetForecastDays(tt.forecastDays))
+			userRepo := new(MockUserRepository)
+			userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+
+			subscription, err := entity.NewSubscription(user.ID, campinas.ID, "0 7 * * *", user.Timezone)
+			require.NoError(t, err)
+			subscription.NextRunAt = time.Now().Add(-time.Minute).UTC()
+
+			notificationRepo := newFakeNotificationRepository()
+			subscriptionService := service.NewSubscriptionService(newFakeSubscriptionRepository(subscription), userRepo, notificationRepo, weatherService)
+
+			require.NoError(t, subscriptionService.ProcessDueSubscriptions(context.Background()))
+
+			require.Len(t, notificationRepo.notifications, 1)
+			for _, notification := range notificationRepo.notifications {
+				assert.Equal(t, tt.expectedHorizon, notification.Content.Horizon)
+				assert.Len(t, notification.Content.Forecasts, tt.expectedDays)
+			}
+		})
+	}
+}
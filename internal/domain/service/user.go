@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
 	"weather-notification/internal/domain/repository"
 
 	"github.com/google/uuid"
@@ -18,12 +21,50 @@ func NewUserService(userRepo repository.UserRepository) *UserService {
 	}
 }
 
-func (s *UserService) Create(ctx context.Context, name, email string, locationID uuid.UUID) error {
-	user, _ := entity.NewUser(name, email, locationID)
+func (s *UserService) Create(ctx context.Context, name, email string, locationID uuid.UUID, timezone string, forecastDays int) error {
+	user, err := entity.NewUser(name, email, locationID)
+	if err != nil {
+		return err
+	}
+
+	if timezone != "" {
+		if err := user.SetTimezone(timezone); err != nil {
+			return err
+		}
+	}
+
+	if forecastDays != 0 {
+		if err := user.SetForecastDays(forecastDays); err != nil {
+			return err
+		}
+	}
+
+	existing, err := s.userRepo.FindByEmail(ctx, user.Email)
+	if err != nil && !errors.Is(err, handler.ErrNotFound) {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("%w: email %s já cadastrado", handler.ErrDuplicateKey, user.Email)
+	}
+
 	return s.userRepo.Create(ctx, user)
 }
 
-func (s *UserService) Update(ctx context.Context, userID uuid.UUID, name string, locationID uuid.UUID) error {
+func (s *UserService) GetUser(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	return s.userRepo.FindByID(ctx, id)
+}
+
+func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return s.userRepo.FindByEmail(ctx, email)
+}
+
+// Delete remove o usuário junto com suas notificações, inscrições e
+// alertas, cancelando os envios ainda pendentes.
+func (s *UserService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.userRepo.Delete(ctx, id)
+}
+
+func (s *UserService) Update(ctx context.Context, userID uuid.UUID, name string, locationID uuid.UUID, timezone string, mode entity.NotificationMode, forecastDays int) error {
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return err
@@ -37,15 +78,48 @@ func (s *UserService) Update(ctx context.Context, userID uuid.UUID, name string,
 		user.LocationID = locationID
 	}
 
+	if timezone != "" {
+		if err := user.SetTimezone(timezone); err != nil {
+			return err
+		}
+	}
+
+	if mode != "" {
+		if err := user.SetNotificationMode(mode); err != nil {
+			return err
+		}
+	}
+
+	if forecastDays != 0 {
+		if err := user.SetForecastDays(forecastDays); err != nil {
+			return err
+		}
+	}
+
 	return s.userRepo.Update(ctx, user)
 }
 
-func (s *UserService) GetAllUsers(ctx context.Context) ([]entity.User, error) {
-	users, err := s.userRepo.FindAll(ctx)
+func (s *UserService) ListUsers(ctx context.Context, filter entity.UserFilter, page entity.PageRequest) (*entity.Page[entity.User], error) {
+	return s.userRepo.List(ctx, filter, page)
+}
+
+func (s *UserService) UpdateChannels(ctx context.Context, userID uuid.UUID, channels []entity.UserChannel) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return users, nil
+
+	validated := make([]entity.UserChannel, 0, len(channels))
+	for _, c := range channels {
+		channel, err := entity.NewUserChannel(c.Channel, c.Address, c.Enabled)
+		if err != nil {
+			return err
+		}
+		validated = append(validated, *channel)
+	}
+
+	user.Channels = validated
+	return s.userRepo.Update(ctx, user)
 }
 
 func (s *UserService) ToggleOptOut(ctx context.Context, userID uuid.UUID, optOut bool) error {
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type UserLocationService struct {
+	userRepo         repository.UserRepository
+	userLocationRepo repository.UserLocationRepository
+}
+
+func NewUserLocationService(userRepo repository.UserRepository, userLocationRepo repository.UserLocationRepository) *UserLocationService {
+	return &UserLocationService{
+		userRepo:         userRepo,
+		userLocationRepo: userLocationRepo,
+	}
+}
+
+func (s *UserLocationService) Add(ctx context.Context, userID, locationID uuid.UUID, label string, notify *bool, primary bool) (*entity.UserLocation, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	location, err := entity.NewUserLocation(userID, locationID, label)
+	if err != nil {
+		return nil, err
+	}
+	if notify != nil {
+		location.Notify = *notify
+	}
+	location.Primary = primary
+
+	if err := s.userLocationRepo.Create(ctx, location); err != nil {
+		return nil, err
+	}
+
+	return location, nil
+}
+
+func (s *UserLocationService) List(ctx context.Context, userID uuid.UUID) ([]entity.UserLocation, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.userLocationRepo.FindByUser(ctx, userID)
+}
+
+// Update altera o rótulo e a preferência de notificação de uma localização
+// salva. Tornar outra localização principal também troca a localização do
+// cadastro do usuário.
+func (s *UserLocationService) Update(ctx context.Context, userID, id uuid.UUID, label string, notify *bool, primary bool) (*entity.UserLocation, error) {
+	location, err := s.userLocationRepo.FindByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if label != "" {
+		if err := location.SetLabel(label); err != nil {
+			return nil, err
+		}
+	}
+	if notify != nil {
+		location.Notify = *notify
+	}
+	location.UpdatedAt = time.Now().UTC()
+
+	if err := s.userLocationRepo.Update(ctx, location); err != nil {
+		return nil, err
+	}
+
+	if primary && !location.Primary {
+		if err := s.userLocationRepo.SetPrimary(ctx, userID, id); err != nil {
+			return nil, err
+		}
+		location.Primary = true
+	}
+
+	return location, nil
+}
+
+func (s *UserLocationService) Remove(ctx context.Context, userID, id uuid.UUID) error {
+	location, err := s.userLocationRepo.FindByID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if location.Primary {
+		return handler.ErrPrimaryLocationRequired
+	}
+
+	return s.userLocationRepo.Delete(ctx, userID, id)
+}
+
+// newUserLocationNotifications monta as notificações de um usuário para as
+// localizações salvas com notificação ativa: uma por localização ou uma
+// única notificação combinada, conforme a preferência do usuário. Sem
+// nenhuma localização ativa, retorna ErrNoNotifiableLocations.
+func newUserLocationNotifications(
+	ctx context.Context,
+	weatherService *WeatherService,
+	userLocationRepo repository.UserLocationRepository,
+	user *entity.User,
+	scheduledFor time.Time,
+) ([]*entity.Notification, error) {
+	saved, err := userLocationRepo.FindByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := entity.NotifiableLocations(*user, saved)
+	forecasts := make([]entity.LocationForecast, 0, len(locations))
+	for _, location := range locations {
+		forecast, err := weatherService.GetForecastForDays(ctx, location.LocationID, user.ForecastDays)
+		if err != nil {
+			return nil, err
+		}
+
+		label := location.Label
+		if len(locations) == 1 {
+			label = ""
+		}
+
+		forecasts = append(forecasts, entity.LocationForecast{
+			LocationID: location.LocationID,
+			Label:      label,
+			Content:    *forecast,
+		})
+	}
+
+	if len(forecasts) == 0 {
+		return nil, handler.ErrNoNotifiableLocations
+	}
+
+	if user.CombinesLocations() {
+		notification, err := newLocationNotification(user.ID, forecasts[0], scheduledFor)
+		if err != nil {
+			return nil, err
+		}
+		notification.AdditionalLocations = forecasts[1:]
+		return []*entity.Notification{notification}, nil
+	}
+
+	notifications := make([]*entity.Notification, 0, len(forecasts))
+	for _, forecast := range forecasts {
+		notification, err := newLocationNotification(user.ID, forecast, scheduledFor)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+func newLocationNotification(userID uuid.UUID, forecast entity.LocationForecast, scheduledFor time.Time) (*entity.Notification, error) {
+	notification, err := entity.NewNotification(userID, forecast.LocationID, forecast.Content, scheduledFor)
+	if err != nil {
+		return nil, err
+	}
+	notification.LocationLabel = forecast.Label
+	return notification, nil
+}
@@ -0,0 +1,309 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeUserLocationRepository struct {
+	locations []entity.UserLocation
+}
+
+func (r *fakeUserLocationRepository) Create(ctx context.Context, location *entity.UserLocation) error {
+	if location.Primary {
+		for i := range r.locations {
+			r.locations[i].Primary = false
+		}
+	}
+	r.locations = append(r.locations, *location)
+	return nil
+}
+
+func (r *fakeUserLocationRepository) Update(ctx context.Context, location *entity.UserLocation) error {
+	for i := range r.locations {
+		if r.locations[i].ID == location.ID {
+			r.locations[i] = *location
+			return nil
+		}
+	}
+	return handler.ErrNotFound
+}
+
+func (r *fakeUserLocationRepository) SetPrimary(ctx context.Context, userID, id uuid.UUID) error {
+	for i := range r.locations {
+		r.locations[i].Primary = r.locations[i].ID == id
+	}
+	return nil
+}
+
+func (r *fakeUserLocationRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*entity.UserLocation, error) {
+	for _, location := range r.locations {
+		if location.ID == id && location.UserID == userID {
+			return &location, nil
+		}
+	}
+	return nil, handler.ErrNotFound
+}
+
+func (r *fakeUserLocationRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]entity.UserLocation, error) {
+	return r.locations, nil
+}
+
+func (r *fakeUserLocationRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	for i, location := range r.locations {
+		if location.ID == id {
+			r.locations = append(r.locations[:i], r.locations[i+1:]...)
+			return nil
+		}
+	}
+	return handler.ErrNotFound
+}
+
+// fakeLocationRepository e fakeCPTECClient devolvem uma previsão fixa com o
+// nome da cidade de cada localização cadastrada.
+type fakeLocationRepository struct {
+	locations map[uuid.UUID]*entity.Location
+	catalog   []entity.Location
+	imported  []entity.Location
+}
+
+func (r *fakeLocationRepository) Create(ctx context.Context, location *entity.Location) error {
+	r.locations[location.ID] = location
+	return nil
+}
+
+func (r *fakeLocationRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Location, error) {
+	location, ok := r.locations[id]
+	if !ok {
+		return nil, handler.ErrNotFound
+	}
+	return location, nil
+}
+
+func (r *fakeLocationRepository) FindByCPTECCode(ctx context.Context, cptecCode int) (*entity.Location, error) {
+	for _, location := range r.locations {
+		if location.CPTECCode == cptecCode {
+			return location, nil
+		}
+	}
+	return nil, handler.ErrNotFound
+}
+
+func (r *fakeLocationRepository) FindByNameAndState(ctx context.Context, name, state string) (*entity.Location, error) {
+	return nil, handler.ErrNotFound
+}
+
+func (r *fakeLocationRepository) Search(ctx context.Context, name, state string, limit int) ([]entity.Location, error) {
+	return r.catalog, nil
+}
+
+func (r *fakeLocationRepository) FindWithinBounds(ctx context.Context, bounds entity.CoordinateBounds) ([]entity.Location, error) {
+	var found []entity.Location
+	for _, location := range r.catalog {
+		c := location.Coordinates
+		if c != nil && c.Latitude >= bounds.MinLatitude && c.Latitude <= bounds.MaxLatitude &&
+			c.Longitude >= bounds.MinLongitude && c.Longitude <= bounds.MaxLongitude {
+			found = append(found, location)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeLocationRepository) Import(ctx context.Context, locations []entity.Location) (int, error) {
+	r.imported = append(r.imported, locations...)
+	return len(locations), nil
+}
+
+type fakeCPTECClient struct {
+	cities   map[int]string
+	searches []string
+	found    []entity.Location
+}
+
+func (c *fakeCPTECClient) SearchCities(ctx context.Context, cityName string) ([]entity.Location, error) {
+	c.searches = append(c.searches, cityName)
+	return c.found, nil
+}
+
+func (c *fakeCPTECClient) GetWeatherForecast(ctx context.Context, cptecCode int) (*entity.WeatherForecastCollection, error) {
+	return &entity.WeatherForecastCollection{
+		Nome:      c.cities[cptecCode],
+		UF:        "SP",
+		Horizon:   entity.ForecastHorizon4Days,
+		Forecasts: []entity.WeatherForecast{{Date: time.Now(), MinTemp: 18, MaxTemp: 28, Forecast: "pn"}},
+	}, nil
+}
+
+func (c *fakeCPTECClient) GetSevenDayForecast(ctx context.Context, cptecCode int) (*entity.WeatherForecastCollection, error) {
+	return c.forecastDays(cptecCode, entity.ForecastHorizon7Days, 0, 7), nil
+}
+
+func (c *fakeCPTECClient) GetExtendedForecast(ctx context.Context, cptecCode int) (*entity.WeatherForecastCollection, error) {
+	return c.forecastDays(cptecCode, entity.ForecastHorizonExtended, 4, 7), nil
+}
+
+// forecastDays monta days dias de previsão a partir de from dias após hoje.
+func (c *fakeCPTECClient) forecastDays(cptecCode int, horizon entity.ForecastHorizon, from, days int) *entity.WeatherForecastCollection {
+	today := time.Now().Truncate(24 * time.Hour)
+	forecasts := make([]entity.WeatherForecast, 0, days)
+	for i := from; i < from+days; i++ {
+		forecasts = append(forecasts, entity.WeatherForecast{Date: today.AddDate(0, 0, i), MinTemp: 18, MaxTemp: 28, Forecast: "pn"})
+	}
+	return &entity.WeatherForecastCollection{Nome: c.cities[cptecCode], UF: "SP", Horizon: horizon, Forecasts: forecasts}
+}
+
+func (c *fakeCPTECClient) GetWaveForecast(ctx context.Context, cptecCode int, date time.Time) (*entity.WaveInfo, error) {
+	return nil, handler.ErrNotFound
+}
+
+func TestNotificationService_ScheduleSavedLocations(t *testing.T) {
+	campinas := &entity.Location{ID: uuid.New(), CPTECCode: 1, Name: "Campinas", State: "SP"}
+	ubatuba := &entity.Location{ID: uuid.New(), CPTECCode: 2, Name: "Ubatuba", State: "SP"}
+	santos := &entity.Location{ID: uuid.New(), CPTECCode: 3, Name: "Santos", State: "SP"}
+
+	weatherService := service.NewWeatherService(
+		&fakeCPTECClient{cities: map[int]string{1: "Campinas", 2: "Ubatuba", 3: "Santos"}},
+		&fakeLocationRepository{locations: map[uuid.UUID]*entity.Location{campinas.ID: campinas, ubatuba.ID: ubatuba, santos.ID: santos}},
+		nil,
+	)
+
+	tests := []struct {
+		name                    string
+		mode                    entity.NotificationMode
+		expectedLabels          []string
+		expectedAdditional      int
+		expectedAdditionalLabel string
+	}{
+		{
+			name:           "uma notificação por localização",
+			mode:           entity.NotificationModeSeparate,
+			expectedLabels: []string{"casa", "casa de praia"},
+		},
+		{
+			name:                    "notificação combinada",
+			mode:                    entity.NotificationModeCombined,
+			expectedLabels:          []string{"casa"},
+			expectedAdditional:      1,
+			expectedAdditionalLabel: "casa de praia",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := entity.NewUser("Matheus", "matheus@exemplo.com", campinas.ID)
+			assert.NoError(t, err)
+			assert.NoError(t, user.SetNotificationMode(tt.mode))
+
+			userRepo := new(MockUserRepository)
+			userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+
+			muted := entity.UserLocation{ID: uuid.New(), UserID: user.ID, LocationID: santos.ID, Label: "trabalho", Notify: false}
+			userLocationRepo := &fakeUserLocationRepository{locations: []entity.UserLocation{
+				{ID: uuid.New(), UserID: user.ID, LocationID: ubatuba.ID, Label: "casa de praia", Notify: true},
+				{ID: uuid.New(), UserID: user.ID, LocationID: campinas.ID, Label: "casa", Primary: true, Notify: true},
+				muted,
+			}}
+			notificationRepo := newFakeNotificationRepository()
+
+			notificationService := service.NewNotificationService(notificationRepo, userRepo, userLocationRepo, nil, nil, weatherService, nil, nil)
+
+			err = notificationService.Schedule(context.Background(), user.ID, uuid.Nil, time.Now().Add(time.Hour))
+			assert.NoError(t, err)
+
+			var labels []string
+			for _, notification := range notificationRepo.notifications {
+				labels = append(labels, notification.LocationLabel)
+				assert.NotEqual(t, santos.ID, notification.LocationID)
+				assert.Len(t, notification.AdditionalLocations, tt.expectedAdditional)
+				if tt.expectedAdditional > 0 {
+					assert.Equal(t, "casa", notification.LocationLabel)
+					assert.Equal(t, tt.expectedAdditionalLabel, notification.AdditionalLocations[0].Label)
+					assert.Equal(t, "Ubatuba", notification.AdditionalLocations[0].Content.Nome)
+				}
+			}
+			assert.ElementsMatch(t, tt.expectedLabels, labels)
+		})
+	}
+}
+
+func TestUserLocationService_Lifecycle(t *testing.T) {
+	user, err := entity.NewUser("Matheus", "matheus@exemplo.com", uuid.New())
+	assert.NoError(t, err)
+
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+
+	primary := entity.UserLocation{ID: uuid.New(), UserID: user.ID, LocationID: user.LocationID, Label: entity.PrimaryLocationLabel, Primary: true, Notify: true}
+	userLocationRepo := &fakeUserLocationRepository{locations: []entity.UserLocation{primary}}
+	userLocationService := service.NewUserLocationService(userRepo, userLocationRepo)
+	ctx := context.Background()
+
+	notify := false
+	beach, err := userLocationService.Add(ctx, user.ID, uuid.New(), "casa de praia", &notify, false)
+	assert.NoError(t, err)
+	assert.False(t, beach.Notify)
+
+	t.Run("localização principal não pode ser removida", func(t *testing.T) {
+		err := userLocationService.Remove(ctx, user.ID, primary.ID)
+		assert.ErrorIs(t, err, handler.ErrPrimaryLocationRequired)
+	})
+
+	t.Run("outra localização passa a ser a principal", func(t *testing.T) {
+		updated, err := userLocationService.Update(ctx, user.ID, beach.ID, "praia", nil, true)
+		assert.NoError(t, err)
+		assert.True(t, updated.Primary)
+		assert.Equal(t, "praia", updated.Label)
+
+		assert.NoError(t, userLocationService.Remove(ctx, user.ID, primary.ID))
+
+		locations, err := userLocationService.List(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Len(t, locations, 1)
+	})
+
+	t.Run("nova localização principal rebaixa a anterior", func(t *testing.T) {
+		office, err := userLocationService.Add(ctx, user.ID, uuid.New(), "escritório", nil, true)
+		assert.NoError(t, err)
+		assert.True(t, office.Primary)
+
+		locations, err := userLocationService.List(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Len(t, locations, 2)
+		for _, location := range locations {
+			assert.Equal(t, location.ID == office.ID, location.Primary, location.Label)
+		}
+	})
+}
+
+func TestNotificationService_ScheduleAllLocationsMuted(t *testing.T) {
+	campinas := &entity.Location{ID: uuid.New(), CPTECCode: 1, Name: "Campinas", State: "SP"}
+	weatherService := service.NewWeatherService(
+		&fakeCPTECClient{cities: map[int]string{1: "Campinas"}},
+		&fakeLocationRepository{locations: map[uuid.UUID]*entity.Location{campinas.ID: campinas}},
+		nil,
+	)
+
+	user, err := entity.NewUser("Matheus", "matheus@exemplo.com", campinas.ID)
+	assert.NoError(t, err)
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+
+	userLocationRepo := &fakeUserLocationRepository{locations: []entity.UserLocation{
+		{ID: uuid.New(), UserID: user.ID, LocationID: campinas.ID, Label: "casa", Primary: true, Notify: false},
+	}}
+	notificationRepo := newFakeNotificationRepository()
+	notificationService := service.NewNotificationService(notificationRepo, userRepo, userLocationRepo, nil, nil, weatherService, nil, nil)
+
+	err = notificationService.Schedule(context.Background(), user.ID, uuid.Nil, time.Now().Add(time.Hour))
+
+	assert.ErrorIs(t, err, handler.ErrNoNotifiableLocations)
+	assert.Empty(t, notificationRepo.notifications)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
 	"weather-notification/internal/domain/service"
 
 	"github.com/google/uuid"
@@ -25,9 +26,12 @@ func (m *MockUserRepository) Update(ctx context.Context, user *entity.User) erro
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) FindAll(ctx context.Context) ([]entity.User, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]entity.User), args.Error(1)
+func (m *MockUserRepository) List(ctx context.Context, filter entity.UserFilter, page entity.PageRequest) (*entity.Page[entity.User], error) {
+	args := m.Called(ctx, filter, page)
+	if users, ok := args.Get(0).(*entity.Page[entity.User]); ok {
+		return users, args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
 func (m *MockUserRepository) FindAllActive(ctx context.Context) ([]entity.User, error) {
@@ -56,6 +60,11 @@ func (m *MockUserRepository) UpdateOptOut(ctx context.Context, id uuid.UUID, opt
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func TestUserService_Create(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	userService := service.NewUserService(mockRepo)
@@ -70,8 +79,11 @@ func TestUserService_Create(t *testing.T) {
 		userName     string
 		userEmail    string
 		locationID   uuid.UUID
+		timezone     string
+		forecastDays int
 		mockBehavior func(mockRepo *MockUserRepository)
 		expectError  bool
+		expectedErr  error
 	}{
 		{
 			name:       "sucesso ao criar usuário",
@@ -79,6 +91,7 @@ func TestUserService_Create(t *testing.T) {
 			userEmail:  validEmail,
 			locationID: validLocationID,
 			mockBehavior: func(mockRepo *MockUserRepository) {
+				mockRepo.On("FindByEmail", mock.Anything, validEmail).Return(nil, handler.ErrNotFound).Once()
 				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
 					return user.Name == validName &&
 						user.Email == validEmail &&
@@ -87,15 +100,72 @@ func TestUserService_Create(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name:       "usuário com fuso horário do Acre",
+			userName:   "Ana",
+			userEmail:  "ana@exemplo.com",
+			locationID: validLocationID,
+			timezone:   "America/Rio_Branco",
+			mockBehavior: func(mockRepo *MockUserRepository) {
+				mockRepo.On("FindByEmail", mock.Anything, "ana@exemplo.com").Return(nil, handler.ErrNotFound).Once()
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Email == "ana@exemplo.com" && user.Timezone == "America/Rio_Branco"
+				})).Return(nil)
+			},
+			expectError: false,
+		},
+		{
+			name:       "e-mail já cadastrado",
+			userName:   validName,
+			userEmail:  "existente@exemplo.com",
+			locationID: validLocationID,
+			mockBehavior: func(mockRepo *MockUserRepository) {
+				mockRepo.On("FindByEmail", mock.Anything, "existente@exemplo.com").Return(&entity.User{ID: uuid.New()}, nil).Once()
+			},
+			expectedErr: handler.ErrDuplicateKey,
+		},
+		{
+			name:         "usuário com previsão estendida",
+			userName:     "Bruno",
+			userEmail:    "bruno@exemplo.com",
+			locationID:   validLocationID,
+			forecastDays: 11,
+			mockBehavior: func(mockRepo *MockUserRepository) {
+				mockRepo.On("FindByEmail", mock.Anything, "bruno@exemplo.com").Return(nil, handler.ErrNotFound).Once()
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Email == "bruno@exemplo.com" && user.ForecastDays == 11
+				})).Return(nil)
+			},
+		},
+		{
+			name:         "dias de previsão acima do horizonte do CPTEC",
+			userName:     validName,
+			userEmail:    validEmail,
+			locationID:   validLocationID,
+			forecastDays: 15,
+			mockBehavior: func(mockRepo *MockUserRepository) {},
+			expectedErr:  handler.ErrInvalidForecastDays,
+		},
+		{
+			name:         "fuso horário inválido",
+			userName:     validName,
+			userEmail:    validEmail,
+			locationID:   validLocationID,
+			timezone:     "America/Atlantida",
+			mockBehavior: func(mockRepo *MockUserRepository) {},
+			expectError:  true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehavior(mockRepo)
 
-			err := userService.Create(ctx, tt.userName, tt.userEmail, tt.locationID)
+			err := userService.Create(ctx, tt.userName, tt.userEmail, tt.locationID, tt.timezone, tt.forecastDays)
 
-			if tt.expectError {
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else if tt.expectError {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
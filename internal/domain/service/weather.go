@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"time"
 	"weather-notification/internal/domain/entity"
 	handler "weather-notification/internal/domain/error_handler"
@@ -13,28 +17,51 @@ import (
 type CPTECClient interface {
 	SearchCities(ctx context.Context, cityName string) ([]entity.Location, error)
 	GetWeatherForecast(ctx context.Context, cptecCode int) (*entity.WeatherForecastCollection, error)
+	GetSevenDayForecast(ctx context.Context, cptecCode int) (*entity.WeatherForecastCollection, error)
+	GetExtendedForecast(ctx context.Context, cptecCode int) (*entity.WeatherForecastCollection, error)
 	GetWaveForecast(ctx context.Context, cptecCode int, date time.Time) (*entity.WaveInfo, error)
 }
 
 type WeatherService struct {
 	cptecClient  CPTECClient
 	locationRepo repository.LocationRepository
+	cache        ForecastCache
+	cacheHits    atomic.Uint64
+	cacheMisses  atomic.Uint64
 }
 
-func NewWeatherService(client CPTECClient, locationRepo repository.LocationRepository) *WeatherService {
+func NewWeatherService(client CPTECClient, locationRepo repository.LocationRepository, cache ForecastCache) *WeatherService {
 	return &WeatherService{
 		cptecClient:  client,
 		locationRepo: locationRepo,
+		cache:        cache,
 	}
 }
 
-func (s *WeatherService) SearchLocation(ctx context.Context, cityName string) ([]entity.Location, error) {
-	location, err := s.locationRepo.FindByNameAndState(ctx, cityName, "")
-	if err == nil {
-		return []entity.Location{*location}, nil
+const locationSearchLimit = 10
+
+// SearchLocation busca as cidades pelo nome, opcionalmente restritas à UF,
+// ordenadas da correspondência mais exata para a mais parcial. A busca parte
+// do catálogo local, tolerante a acentos e erros de digitação, e só dispensa
+// o CPTEC quando alguma cidade armazenada corresponde ao nome de forma exata,
+// sem acentos ou por prefixo. As cidades apenas parecidas com o nome são
+// retornadas quando o CPTEC não encontra nenhuma.
+func (s *WeatherService) SearchLocation(ctx context.Context, cityName, state string) ([]entity.RankedLocation, error) {
+	state = strings.ToUpper(strings.TrimSpace(state))
+	if state != "" && len(state) != 2 {
+		return nil, handler.ErrInvalidState
 	}
 
-	locations, err := s.cptecClient.SearchCities(ctx, cityName)
+	stored, err := s.locationRepo.Search(ctx, strings.TrimSpace(cityName), state, locationSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+	approximate := entity.RankApproximateLocations(stored, cityName, state)
+	if len(approximate) > 0 && approximate[0].Match.IsTextual() {
+		return approximate, nil
+	}
+
+	locations, err := s.cptecClient.SearchCities(ctx, entity.RemoveAccents(cityName))
 	if err != nil {
 		return nil, err
 	}
@@ -51,28 +78,194 @@ func (s *WeatherService) SearchLocation(ctx context.Context, cityName string) ([
 		}
 	}
 
-	return newLocations, nil
+	ranked := entity.RankLocations(newLocations, cityName, state)
+	if len(ranked) == 0 {
+		return approximate, nil
+	}
+	return ranked, nil
+}
+
+// ResolveLocation encontra uma única localização a partir do ID, do código
+// CPTEC, das coordenadas ou do nome da cidade. Pelo nome, retorna AmbiguousLocationError
+// quando mais de uma cidade corresponde igualmente ao texto informado.
+func (s *WeatherService) ResolveLocation(ctx context.Context, query entity.LocationQuery) (*entity.Location, error) {
+	switch {
+	case query.ID != uuid.Nil:
+		return s.locationRepo.FindByID(ctx, query.ID)
+	case query.CPTECCode != 0:
+		return s.LocationByCPTECCode(ctx, query.CPTECCode)
+	case query.Coordinates != nil:
+		nearby, err := s.NearestLocations(ctx, query.Coordinates.Latitude, query.Coordinates.Longitude, 1)
+		if err != nil {
+			return nil, err
+		}
+		return &nearby[0].Location, nil
+	case strings.TrimSpace(query.City) != "":
+		ranked, err := s.SearchLocation(ctx, query.City, query.State)
+		if err != nil {
+			return nil, err
+		}
+		return entity.BestLocation(ranked, query.City)
+	default:
+		return nil, fmt.Errorf("%w: informe city, location_id, cptec_code ou latitude e longitude", handler.ErrInvalidInput)
+	}
+}
+
+// nearestSearchRadiiKm são os raios usados, em ordem, na busca da cidade mais
+// próxima. A busca só amplia o raio quando não encontra cidades suficientes,
+// já que no interior da região Norte as sedes municipais ficam a centenas de
+// quilômetros umas das outras.
+var nearestSearchRadiiKm = []float64{50, 200, 1000}
+
+// NearestLocations retorna as cidades do catálogo mais próximas das
+// coordenadas, pela distância de haversine. Só cidades importadas com
+// latitude e longitude participam da busca.
+func (s *WeatherService) NearestLocations(ctx context.Context, latitude, longitude float64, limit int) ([]entity.NearbyLocation, error) {
+	origin, err := entity.NewCoordinates(latitude, longitude)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 1 {
+		limit = 1
+	}
+
+	var nearby []entity.NearbyLocation
+	for _, radius := range nearestSearchRadiiKm {
+		candidates, err := s.locationRepo.FindWithinBounds(ctx, origin.Bounds(radius))
+		if err != nil {
+			return nil, err
+		}
+
+		nearby = entity.NearestLocations(*origin, candidates, limit)
+		if len(nearby) >= limit && nearby[len(nearby)-1].DistanceKm <= radius {
+			return nearby, nil
+		}
+	}
+
+	if len(nearby) == 0 {
+		return nil, fmt.Errorf("%w: nenhuma localização a menos de %.0f km de %v, %v",
+			handler.ErrNotFound, nearestSearchRadiiKm[len(nearestSearchRadiiKm)-1], latitude, longitude)
+	}
+
+	return nearby, nil
 }
 
+// LocationByCPTECCode busca a cidade pelo código CPTEC. Cidades ainda não
+// armazenadas são cadastradas com o nome e a UF da previsão do CPTEC.
+func (s *WeatherService) LocationByCPTECCode(ctx context.Context, cptecCode int) (*entity.Location, error) {
+	location, err := s.locationRepo.FindByCPTECCode(ctx, cptecCode)
+	if err == nil || err != handler.ErrNotFound {
+		return location, err
+	}
+
+	forecast, err := s.cptecClient.GetWeatherForecast(ctx, cptecCode)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := url.QueryUnescape(forecast.Nome)
+	if err != nil {
+		name = forecast.Nome
+	}
+
+	location, err = entity.NewLocation(cptecCode, name, forecast.UF)
+	if err != nil {
+		return nil, fmt.Errorf("%w: código CPTEC %d", handler.ErrNotFound, cptecCode)
+	}
+
+	if err := s.locationRepo.Create(ctx, location); err != nil {
+		return nil, err
+	}
+
+	return location, nil
+}
+
+// GetForecast retorna a previsão de 4 dias da localização.
 func (s *WeatherService) GetForecast(ctx context.Context, locationID uuid.UUID) (*entity.WeatherForecastCollection, error) {
+	return s.GetForecastForDays(ctx, locationID, entity.DefaultForecastDays)
+}
+
+// GetForecastForDays retorna days dias de previsão da localização, usando o
+// menor horizonte do CPTEC que os cobre.
+func (s *WeatherService) GetForecastForDays(ctx context.Context, locationID uuid.UUID, days int) (*entity.WeatherForecastCollection, error) {
+	if err := entity.ValidateForecastDays(days); err != nil {
+		return nil, err
+	}
+
 	location, err := s.locationRepo.FindByID(ctx, locationID)
 	if err != nil {
 		return nil, err
 	}
 
-	forecast, err := s.cptecClient.GetWeatherForecast(ctx, location.CPTECCode)
+	forecast, err := s.forecastForHorizon(ctx, location.CPTECCode, entity.ForecastHorizonForDays(days))
 	if err != nil {
 		return nil, err
 	}
 
+	return forecast.Limit(days), nil
+}
+
+func (s *WeatherService) forecastForHorizon(ctx context.Context, cptecCode int, horizon entity.ForecastHorizon) (*entity.WeatherForecastCollection, error) {
+	if s.cache != nil {
+		if cached, ok := s.cache.Get(ctx, cptecCode, horizon); ok {
+			s.cacheHits.Add(1)
+			return cached, nil
+		}
+		s.cacheMisses.Add(1)
+	}
+
+	forecast, err := s.fetchForecast(ctx, cptecCode, horizon)
+	if err != nil {
+		return nil, err
+	}
+
+	// A previsão de ondas é consultada dia a dia; nos horizontes longos ela
+	// fica restrita aos primeiros dias para não multiplicar as requisições.
 	for i, f := range forecast.Forecasts {
-		forecastDate := f.Date
+		if i >= entity.DefaultForecastDays {
+			break
+		}
 
-		wave, err := s.cptecClient.GetWaveForecast(ctx, location.CPTECCode, forecastDate)
+		wave, err := s.cptecClient.GetWaveForecast(ctx, cptecCode, f.Date)
 		if err == nil {
 			forecast.Forecasts[i].Wave = wave
 		}
 	}
 
+	if s.cache != nil {
+		_ = s.cache.Set(ctx, cptecCode, forecast)
+	}
+
 	return forecast, nil
 }
+
+// fetchForecast busca a previsão do horizonte no CPTEC. A estendida só traz
+// os dias seguintes aos da previsão de 4 dias, por isso as duas são unidas.
+func (s *WeatherService) fetchForecast(ctx context.Context, cptecCode int, horizon entity.ForecastHorizon) (*entity.WeatherForecastCollection, error) {
+	switch horizon {
+	case entity.ForecastHorizon7Days:
+		return s.cptecClient.GetSevenDayForecast(ctx, cptecCode)
+	case entity.ForecastHorizonExtended:
+		forecast, err := s.cptecClient.GetWeatherForecast(ctx, cptecCode)
+		if err != nil {
+			return nil, err
+		}
+
+		extended, err := s.cptecClient.GetExtendedForecast(ctx, cptecCode)
+		if err != nil {
+			return nil, err
+		}
+
+		forecast.Extend(entity.ForecastHorizonExtended, extended.Forecasts)
+		return forecast, nil
+	default:
+		return s.cptecClient.GetWeatherForecast(ctx, cptecCode)
+	}
+}
+
+func (s *WeatherService) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:   s.cacheHits.Load(),
+		Misses: s.cacheMisses.Load(),
+	}
+}
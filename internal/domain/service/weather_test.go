@@ -0,0 +1,278 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeatherService_ResolveLocation(t *testing.T) {
+	santaMariaRS := entity.Location{ID: uuid.New(), CPTECCode: 4598, Name: "Santa Maria", State: "RS"}
+	santaMariaRN := entity.Location{ID: uuid.New(), CPTECCode: 4597, Name: "Santa Maria", State: "RN"}
+	santaMariaJetiba := entity.Location{ID: uuid.New(), CPTECCode: 4600, Name: "Santa Maria de Jetibá", State: "ES"}
+	saoPaulo := entity.Location{ID: uuid.New(), CPTECCode: 244, Name: "São Paulo", State: "SP"}
+	saoPauloOlivenca := entity.Location{ID: uuid.New(), CPTECCode: 5013, Name: "São Paulo de Olivença", State: "AM"}
+
+	tests := []struct {
+		name        string
+		found       []entity.Location
+		query       entity.LocationQuery
+		expected    uuid.UUID
+		expectError error
+		candidates  int
+	}{
+		{
+			name:        "nome em mais de um estado é ambíguo",
+			found:       []entity.Location{santaMariaJetiba, santaMariaRS, santaMariaRN},
+			query:       entity.LocationQuery{City: "Santa Maria"},
+			expectError: handler.ErrAmbiguousLocation,
+			candidates:  2,
+		},
+		{
+			name:     "UF desambigua a cidade",
+			found:    []entity.Location{santaMariaJetiba, santaMariaRS, santaMariaRN},
+			query:    entity.LocationQuery{City: "Santa Maria", State: "rs"},
+			expected: santaMariaRS.ID,
+		},
+		{
+			name:     "nome sem acento prefere a correspondência completa ao prefixo",
+			found:    []entity.Location{saoPauloOlivenca, saoPaulo},
+			query:    entity.LocationQuery{City: "sao paulo"},
+			expected: saoPaulo.ID,
+		},
+		{
+			name:        "prefixo de várias cidades é ambíguo",
+			found:       []entity.Location{saoPauloOlivenca, saoPaulo},
+			query:       entity.LocationQuery{City: "São"},
+			expectError: handler.ErrAmbiguousLocation,
+			candidates:  2,
+		},
+		{
+			name:        "nenhuma cidade encontrada",
+			query:       entity.LocationQuery{City: "Atlântida"},
+			expectError: handler.ErrNotFound,
+		},
+		{
+			name:        "UF inválida",
+			query:       entity.LocationQuery{City: "Santa Maria", State: "RGS"},
+			expectError: handler.ErrInvalidState,
+		},
+		{
+			name:     "código CPTEC já armazenado",
+			query:    entity.LocationQuery{CPTECCode: santaMariaRS.CPTECCode},
+			expected: santaMariaRS.ID,
+		},
+		{
+			name:     "location_id informado",
+			query:    entity.LocationQuery{ID: santaMariaRS.ID, City: "Santa Maria"},
+			expected: santaMariaRS.ID,
+		},
+		{
+			name:        "sem identificação da localização",
+			query:       entity.LocationQuery{},
+			expectError: handler.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeCPTECClient{found: tt.found}
+			stored := santaMariaRS
+			locationRepo := &fakeLocationRepository{locations: map[uuid.UUID]*entity.Location{stored.ID: &stored}}
+			weatherService := service.NewWeatherService(client, locationRepo, nil)
+
+			location, err := weatherService.ResolveLocation(context.Background(), tt.query)
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+
+				if tt.candidates > 0 {
+					var ambiguous *entity.AmbiguousLocationError
+					if assert.ErrorAs(t, err, &ambiguous) {
+						assert.Len(t, ambiguous.Candidates, tt.candidates)
+					}
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, location.ID)
+			for _, search := range client.searches {
+				assert.Equal(t, entity.RemoveAccents(search), search)
+			}
+		})
+	}
+}
+
+func TestWeatherService_LocationByCPTECCode(t *testing.T) {
+	client := &fakeCPTECClient{cities: map[int]string{4598: "Santa+Maria"}}
+	locationRepo := &fakeLocationRepository{locations: map[uuid.UUID]*entity.Location{}}
+	weatherService := service.NewWeatherService(client, locationRepo, nil)
+
+	location, err := weatherService.LocationByCPTECCode(context.Background(), 4598)
+	assert.NoError(t, err)
+	assert.Equal(t, "Santa Maria", location.Name)
+	assert.Equal(t, "SP", location.State)
+	assert.Contains(t, locationRepo.locations, location.ID)
+
+	_, err = weatherService.LocationByCPTECCode(context.Background(), 9999)
+	assert.ErrorIs(t, err, handler.ErrNotFound)
+}
+
+func TestWeatherService_SearchLocationCatalog(t *testing.T) {
+	campinas := entity.Location{ID: uuid.New(), CPTECCode: 1110, Name: "Campinas", State: "SP"}
+	campinaGrande := entity.Location{ID: uuid.New(), CPTECCode: 1101, Name: "Campina Grande", State: "PB"}
+
+	t.Run("catálogo local atende correspondências por prefixo", func(t *testing.T) {
+		client := &fakeCPTECClient{}
+		locationRepo := &fakeLocationRepository{catalog: []entity.Location{campinas, campinaGrande}}
+		weatherService := service.NewWeatherService(client, locationRepo, nil)
+
+		ranked, err := weatherService.SearchLocation(context.Background(), "Campina", "")
+		assert.NoError(t, err)
+		assert.Len(t, ranked, 2)
+		assert.Equal(t, entity.MatchPrefix, ranked[0].Match)
+		assert.Empty(t, client.searches)
+	})
+
+	t.Run("nome com erro de digitação sem resultado no CPTEC retorna as sugestões", func(t *testing.T) {
+		client := &fakeCPTECClient{}
+		locationRepo := &fakeLocationRepository{catalog: []entity.Location{campinas, campinaGrande}}
+		weatherService := service.NewWeatherService(client, locationRepo, nil)
+
+		ranked, err := weatherService.SearchLocation(context.Background(), "Campinsa", "")
+		assert.NoError(t, err)
+		assert.Len(t, ranked, 2)
+		assert.Equal(t, campinas.ID, ranked[0].ID)
+		assert.Equal(t, entity.MatchApproximate, ranked[0].Match)
+		assert.Equal(t, []string{"Campinsa"}, client.searches)
+
+		_, err = weatherService.ResolveLocation(context.Background(), entity.LocationQuery{City: "Campinsa"})
+		assert.ErrorIs(t, err, handler.ErrAmbiguousLocation)
+	})
+
+	t.Run("correspondência apenas aproximada consulta o CPTEC", func(t *testing.T) {
+		found, err := entity.NewLocation(1109, "Campinápolis", "MT")
+		assert.NoError(t, err)
+		client := &fakeCPTECClient{found: []entity.Location{*found}}
+		locationRepo := &fakeLocationRepository{locations: map[uuid.UUID]*entity.Location{}, catalog: []entity.Location{campinas}}
+		weatherService := service.NewWeatherService(client, locationRepo, nil)
+
+		location, err := weatherService.ResolveLocation(context.Background(), entity.LocationQuery{City: "Campinapolis"})
+		assert.NoError(t, err)
+		assert.Equal(t, found.ID, location.ID)
+		assert.Equal(t, []string{"Campinapolis"}, client.searches)
+	})
+
+	t.Run("nome desconhecido consulta o CPTEC", func(t *testing.T) {
+		found, err := entity.NewLocation(5515, "Ubatuba", "SP")
+		assert.NoError(t, err)
+		client := &fakeCPTECClient{found: []entity.Location{*found}}
+		locationRepo := &fakeLocationRepository{locations: map[uuid.UUID]*entity.Location{}}
+		weatherService := service.NewWeatherService(client, locationRepo, nil)
+
+		ranked, err := weatherService.SearchLocation(context.Background(), "Ubatuba", "SP")
+		assert.NoError(t, err)
+		assert.Len(t, ranked, 1)
+		assert.Equal(t, []string{"Ubatuba"}, client.searches)
+		assert.Contains(t, locationRepo.locations, found.ID)
+	})
+}
+
+func TestLocationCatalogService_Import(t *testing.T) {
+	locationRepo := &fakeLocationRepository{}
+	catalogService := service.NewLocationCatalogService(locationRepo)
+
+	imported, err := catalogService.Import(context.Background(), []entity.Location{
+		{CPTECCode: 244, Name: "Sao Paulo", State: "SP"},
+		{CPTECCode: 5515, Name: "Ubatuba", State: "SP"},
+		{CPTECCode: 244, Name: "São Paulo", State: "SP"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, imported)
+	assert.Equal(t, "São Paulo", locationRepo.imported[0].Name)
+}
+
+func TestWeatherService_NearestLocations(t *testing.T) {
+	ubatuba := entity.Location{ID: uuid.New(), CPTECCode: 5515, Name: "Ubatuba", State: "SP", Coordinates: &entity.Coordinates{Latitude: -23.4336, Longitude: -45.0838}}
+	caraguatatuba := entity.Location{ID: uuid.New(), CPTECCode: 1203, Name: "Caraguatatuba", State: "SP", Coordinates: &entity.Coordinates{Latitude: -23.6203, Longitude: -45.4131}}
+	manaus := entity.Location{ID: uuid.New(), CPTECCode: 234, Name: "Manaus", State: "AM", Coordinates: &entity.Coordinates{Latitude: -3.1190, Longitude: -60.0217}}
+	semCoordenadas := entity.Location{ID: uuid.New(), CPTECCode: 244, Name: "São Paulo", State: "SP"}
+
+	locationRepo := &fakeLocationRepository{catalog: []entity.Location{manaus, caraguatatuba, semCoordenadas, ubatuba}}
+	weatherService := service.NewWeatherService(&fakeCPTECClient{}, locationRepo, nil)
+	ctx := context.Background()
+
+	t.Run("ordena pela distância", func(t *testing.T) {
+		nearby, err := weatherService.NearestLocations(ctx, -23.45, -45.07, 5)
+		assert.NoError(t, err)
+		assert.Len(t, nearby, 2)
+		assert.Equal(t, ubatuba.ID, nearby[0].ID)
+		assert.Equal(t, caraguatatuba.ID, nearby[1].ID)
+		assert.Less(t, nearby[0].DistanceKm, 3.0)
+	})
+
+	t.Run("amplia o raio em regiões com poucas cidades", func(t *testing.T) {
+		nearby, err := weatherService.NearestLocations(ctx, -4.5, -61.5, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, manaus.ID, nearby[0].ID)
+		assert.Greater(t, nearby[0].DistanceKm, 200.0)
+	})
+
+	t.Run("nenhuma cidade próxima", func(t *testing.T) {
+		_, err := weatherService.NearestLocations(ctx, 48.85, 2.35, 1)
+		assert.ErrorIs(t, err, handler.ErrNotFound)
+	})
+
+	t.Run("coordenadas inválidas", func(t *testing.T) {
+		_, err := weatherService.NearestLocations(ctx, -95, -45, 1)
+		assert.ErrorIs(t, err, handler.ErrInvalidCoordinates)
+	})
+
+	t.Run("usuário criado pelas coordenadas usa a cidade mais próxima", func(t *testing.T) {
+		location, err := weatherService.ResolveLocation(ctx, entity.LocationQuery{
+			Coordinates: &entity.Coordinates{Latitude: -23.61, Longitude: -45.40},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, caraguatatuba.ID, location.ID)
+	})
+}
+
+func TestWeatherService_GetForecastForDays(t *testing.T) {
+	ubatuba := &entity.Location{ID: uuid.New(), CPTECCode: 5515, Name: "Ubatuba", State: "SP"}
+	client := &fakeCPTECClient{cities: map[int]string{5515: "Ubatuba"}}
+	locationRepo := &fakeLocationRepository{locations: map[uuid.UUID]*entity.Location{ubatuba.ID: ubatuba}}
+	weatherService := service.NewWeatherService(client, locationRepo, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		days        int
+		horizon     entity.ForecastHorizon
+		expected    int
+		expectError error
+	}{
+		{"previsão de 4 dias", 1, entity.ForecastHorizon4Days, 1, nil},
+		{"previsão de 7 dias", 6, entity.ForecastHorizon7Days, 6, nil},
+		{"previsão estendida une a de 4 dias", 10, entity.ForecastHorizonExtended, 8, nil},
+		{"acima do horizonte do CPTEC", 12, "", 0, handler.ErrInvalidForecastDays},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast, err := weatherService.GetForecastForDays(ctx, ubatuba.ID, tt.days)
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.horizon, forecast.Horizon)
+			assert.Equal(t, tt.expected, forecast.Days())
+		})
+	}
+}
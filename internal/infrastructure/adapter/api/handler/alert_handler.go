@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AlertHandler struct {
+	alertService *service.AlertService
+}
+
+func NewAlertHandler(alertService *service.AlertService) *AlertHandler {
+	return &AlertHandler{
+		alertService: alertService,
+	}
+}
+
+// @Summary Cria uma regra de alerta
+// @Description Inscreve o usuário em um alerta meteorológico (temperatura, UV, altura das ondas ou condição do tempo)
+// @Tags Alertas
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Param request body CreateAlertRuleRequest true "Regra de alerta"
+// @Success 201 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id}/alerts [post]
+func (h *AlertHandler) Create(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	var req CreateAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	locationID := uuid.Nil
+	if req.LocationID != "" {
+		locationID, err = uuid.Parse(req.LocationID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Error: "location_id inválido",
+			})
+			return
+		}
+	}
+
+	rule, err := h.alertService.Create(
+		c.Request.Context(),
+		userID,
+		locationID,
+		req.Metric,
+		req.Operator,
+		req.Threshold,
+		req.Conditions,
+	)
+	if err != nil {
+		c.JSON(alertErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, Response{
+		Message: "Alerta criado com sucesso",
+		Data:    rule,
+	})
+}
+
+// @Summary Lista regras de alerta do usuário
+// @Description Retorna os alertas meteorológicos em que o usuário está inscrito
+// @Tags Alertas
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id}/alerts [get]
+func (h *AlertHandler) List(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	rules, err := h.alertService.ListByUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(alertErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Data: rules,
+	})
+}
+
+// @Summary Remove uma regra de alerta
+// @Description Cancela a inscrição do usuário em um alerta meteorológico
+// @Tags Alertas
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Param alert_id path string true "ID do alerta" Format(uuid)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id}/alerts/{alert_id} [delete]
+func (h *AlertHandler) Delete(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	ruleID, err := uuid.Parse(c.Param("alert_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "alert_id inválido",
+		})
+		return
+	}
+
+	if err := h.alertService.Delete(c.Request.Context(), userID, ruleID); err != nil {
+		c.JSON(alertErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Message: "Alerta removido com sucesso",
+	})
+}
+
+func (h *AlertHandler) SetupRoutes(r *gin.RouterGroup) {
+	alerts := r.Group("/users/:id/alerts")
+	{
+		alerts.POST("", h.Create)
+		alerts.GET("", h.List)
+		alerts.DELETE("/:alert_id", h.Delete)
+	}
+}
+
+func alertErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, handler.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, handler.ErrInvalidAlertMetric),
+		errors.Is(err, handler.ErrInvalidAlertOperator),
+		errors.Is(err, handler.ErrInvalidAlertThreshold),
+		errors.Is(err, handler.ErrInvalidUserID),
+		errors.Is(err, handler.ErrInvalidLocationID):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeadLetterHandler struct {
+	deadLetterService *service.DeadLetterService
+}
+
+func NewDeadLetterHandler(deadLetterService *service.DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		deadLetterService: deadLetterService,
+	}
+}
+
+// @Summary Lista mensagens da DLQ
+// @Description Lista as notificações que esgotaram as tentativas de envio, com o último erro e o histórico de tentativas. As mensagens permanecem na fila
+// @Tags Administração
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/admin/dlq [get]
+func (h *DeadLetterHandler) List(c *gin.Context) {
+	letters, err := h.deadLetterService.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Data: letters,
+	})
+}
+
+// @Summary Busca mensagem da DLQ
+// @Tags Administração
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID da mensagem na DLQ"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/admin/dlq/{id} [get]
+func (h *DeadLetterHandler) Get(c *gin.Context) {
+	letter, err := h.deadLetterService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(deadLetterErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Data: letter,
+	})
+}
+
+// @Summary Reprocessa mensagem da DLQ
+// @Description Devolve a notificação para a fila de envio, iniciando uma nova rodada de tentativas
+// @Tags Administração
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID da mensagem na DLQ"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/admin/dlq/{id}/replay [post]
+func (h *DeadLetterHandler) Replay(c *gin.Context) {
+	if err := h.deadLetterService.Replay(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(deadLetterErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Message: "Mensagem reenviada para processamento",
+	})
+}
+
+// @Summary Esvazia a DLQ
+// @Tags Administração
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/admin/dlq [delete]
+func (h *DeadLetterHandler) Purge(c *gin.Context) {
+	purged, err := h.deadLetterService.Purge(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Message: "DLQ esvaziada",
+		Data:    gin.H{"purged": purged},
+	})
+}
+
+func (h *DeadLetterHandler) SetupRoutes(r *gin.RouterGroup) {
+	dlq := r.Group("/admin/dlq")
+	{
+		dlq.GET("", h.List)
+		dlq.DELETE("", h.Purge)
+		dlq.GET("/:id", h.Get)
+		dlq.POST("/:id/replay", h.Replay)
+	}
+}
+
+func deadLetterErrorStatus(err error) int {
+	if errors.Is(err, handler.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
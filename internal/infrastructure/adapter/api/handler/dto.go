@@ -29,21 +29,84 @@ type WaveInfo struct {
 
 //USER
 
+// CreateUserRequest identifica a localização pelo location_id, pelo
+// cptec_code, pela latitude e longitude ou pelo nome da cidade, com a UF
+// opcional para desambiguar.
 type CreateUserRequest struct {
-	Name  string `json:"name" binding:"required" example:"Matheus"`
-	Email string `json:"email" binding:"required,email" example:"matheus@exemplo.com"`
-	City  string `json:"city" binding:"required" example:"São Paulo"`
+	Name         string   `json:"name" binding:"required" example:"Matheus"`
+	Email        string   `json:"email" binding:"required,email" example:"matheus@exemplo.com"`
+	City         string   `json:"city,omitempty" binding:"required_without_all=LocationID CPTECCode Latitude" example:"Santa Maria"`
+	State        string   `json:"state,omitempty" example:"RS"`
+	LocationID   string   `json:"location_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CPTECCode    int      `json:"cptec_code,omitempty" binding:"omitempty,gt=0" example:"4598"`
+	Latitude     *float64 `json:"latitude,omitempty" binding:"required_with=Longitude,omitempty,min=-90,max=90" example:"-29.6842"`
+	Longitude    *float64 `json:"longitude,omitempty" binding:"required_with=Latitude,omitempty,min=-180,max=180" example:"-53.8069"`
+	Timezone     string   `json:"timezone,omitempty" example:"America/Sao_Paulo"`
+	ForecastDays int      `json:"forecast_days,omitempty" binding:"omitempty,min=1,max=11" example:"7"`
 }
 
 type UpdateUserRequest struct {
-	Name string `json:"name,omitempty"`
-	City string `json:"city,omitempty"`
+	Name             string                  `json:"name,omitempty"`
+	City             string                  `json:"city,omitempty"`
+	State            string                  `json:"state,omitempty" example:"RS"`
+	LocationID       string                  `json:"location_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CPTECCode        int                     `json:"cptec_code,omitempty" binding:"omitempty,gt=0" example:"4598"`
+	Latitude         *float64                `json:"latitude,omitempty" binding:"required_with=Longitude,omitempty,min=-90,max=90" example:"-3.1190"`
+	Longitude        *float64                `json:"longitude,omitempty" binding:"required_with=Latitude,omitempty,min=-180,max=180" example:"-60.0217"`
+	Timezone         string                  `json:"timezone,omitempty" example:"America/Manaus"`
+	NotificationMode entity.NotificationMode `json:"notification_mode,omitempty" binding:"omitempty,oneof=SEPARADA COMBINADA" example:"COMBINADA"`
+	ForecastDays     int                     `json:"forecast_days,omitempty" binding:"omitempty,min=1,max=11" example:"7"`
 }
 
 type ToggleOptOutRequest struct {
 	OptOut bool `json:"opt_out" binding:"boolean"`
 }
 
+type UserChannelRequest struct {
+	Channel entity.Channel `json:"channel" binding:"required,oneof=EMAIL WEBHOOK SLACK SMS" example:"EMAIL"`
+	Address string         `json:"address,omitempty" example:"matheus@exemplo.com"`
+	Enabled *bool          `json:"enabled,omitempty" example:"true"`
+}
+
+type UpdateChannelsRequest struct {
+	Channels []UserChannelRequest `json:"channels" binding:"required,dive"`
+}
+
+type UserLocationRequest struct {
+	LocationID string `json:"location_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Label      string `json:"label,omitempty" example:"Casa de praia"`
+	Notify     *bool  `json:"notify,omitempty" example:"true"`
+	Primary    bool   `json:"primary,omitempty" example:"false"`
+}
+
+//ALERT
+
+type CreateAlertRuleRequest struct {
+	LocationID string               `json:"location_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Metric     entity.AlertMetric   `json:"metric" binding:"required,oneof=TEMPERATURA_MAXIMA TEMPERATURA_MINIMA UV ALTURA_ONDA CONDICAO" example:"TEMPERATURA_MAXIMA"`
+	Operator   entity.AlertOperator `json:"operator,omitempty" binding:"omitempty,oneof=MAIOR MAIOR_IGUAL MENOR MENOR_IGUAL" example:"MAIOR"`
+	Threshold  float64              `json:"threshold,omitempty" example:"35"`
+	Conditions []string             `json:"conditions,omitempty" example:"t,ch"`
+}
+
+//SUBSCRIPTION
+
+type SubscriptionRequest struct {
+	LocationID string   `json:"location_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Cron       string   `json:"cron,omitempty" example:"30 6 * * 1-5"`
+	Time       string   `json:"time,omitempty" example:"06:30"`
+	Days       []string `json:"days,omitempty" example:"SEG,TER,QUA,QUI,SEX"`
+	Timezone   string   `json:"timezone,omitempty" example:"America/Manaus"`
+	Active     *bool    `json:"active,omitempty" example:"true"`
+}
+
+func (r SubscriptionRequest) CronExpression() (string, error) {
+	if r.Cron != "" || r.Time == "" {
+		return r.Cron, nil
+	}
+	return entity.CronExpressionFromDays(r.Time, r.Days)
+}
+
 //NOTIFICATION
 
 type CreateGlobalNotificationRequest struct {
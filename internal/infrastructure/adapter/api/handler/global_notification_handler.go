@@ -2,10 +2,13 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 	"time"
+	"weather-notification/internal/domain/entity"
 	"weather-notification/internal/domain/service"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type GlobalNotificationHandler struct {
@@ -59,18 +62,49 @@ func (h *GlobalNotificationHandler) Create(c *gin.Context) {
 	})
 }
 
-// @Summary Lista notificações globais ativas
-// @Description Retorna todas as notificações globais ativas
+// @Summary Lista notificações globais
+// @Description Retorna as notificações globais em páginas, com o total de registros que atendem aos filtros. Sem o filtro active, apenas as ativas são listadas
 // @Tags Notificações Globais
 // @Security BearerAuth
 // @Produce json
+// @Param limit query int false "Itens por página (padrão 20, máximo 100)"
+// @Param cursor query string false "Cursor retornado em next_cursor pela página anterior"
+// @Param sort query string false "Campo de ordenação (created_at, time_of_day), com prefixo - para ordem decrescente"
+// @Param active query bool false "Filtra pelas ativas ou inativas (padrão true)"
+// @Param frequency query string false "Filtra pela frequência (DIARIA, SEMANAL)"
 // @Success 200 {object} Response
+// @Failure 400 {object} Response
 // @Failure 500 {object} Response
 // @Router /api/notifications/global [get]
 func (h *GlobalNotificationHandler) List(c *gin.Context) {
-	notifications, err := h.globalNotificationService.ListActive(c.Request.Context())
+	page, err := parsePageRequest(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, Response{
+		c.JSON(http.StatusBadRequest, Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	active, err := queryBool(c, "active")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: err.Error(),
+		})
+		return
+	}
+	if active == nil {
+		active = new(bool)
+		*active = true
+	}
+
+	filter := entity.GlobalNotificationFilter{
+		Active:    active,
+		Frequency: entity.Frequency(strings.ToUpper(c.Query("frequency"))),
+	}
+
+	notifications, err := h.globalNotificationService.List(c.Request.Context(), filter, page)
+	if err != nil {
+		c.JSON(listErrorStatus(err), Response{
 			Error: err.Error(),
 		})
 		return
@@ -81,10 +115,43 @@ func (h *GlobalNotificationHandler) List(c *gin.Context) {
 	})
 }
 
+// @Summary Histórico de execuções de uma notificação global
+// @Description Lista as execuções realizadas e as ignoradas (perdidas fora do período de tolerância) por fuso horário
+// @Tags Notificações Globais
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID da notificação global" Format(uuid)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/notifications/global/{id}/runs [get]
+func (h *GlobalNotificationHandler) ListRuns(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	runs, err := h.globalNotificationService.ListRuns(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Data: runs,
+	})
+}
+
 func (h *GlobalNotificationHandler) SetupRoutes(r *gin.RouterGroup) {
 	notifications := r.Group("/notifications/global")
 	{
 		notifications.POST("", h.Create)
 		notifications.GET("", h.List)
+		notifications.GET("/:id/runs", h.ListRuns)
 	}
 }
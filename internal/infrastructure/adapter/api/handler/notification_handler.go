@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
 	"weather-notification/internal/domain/service"
 
 	"github.com/gin-gonic/gin"
@@ -21,12 +26,16 @@ func NewNotificationHandler(notificationService *service.NotificationService) *N
 
 type CreateNotificationRequest struct {
 	UserID      string    `json:"user_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
-	LocationID  string    `json:"location_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
+	LocationID  string    `json:"location_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
 	ScheduleFor time.Time `json:"schedule_for" binding:"required" example:"2025-02-03T21:35:00-03:00"`
 }
 
+type RescheduleNotificationRequest struct {
+	ScheduleFor time.Time `json:"schedule_for" binding:"required" example:"2025-02-04T08:00:00-03:00"`
+}
+
 // @Summary Agenda uma nova notificação
-// @Description Cria um agendamento de notificação de previsão do tempo
+// @Description Cria um agendamento de notificação de previsão do tempo. Sem location_id, agenda as localizações salvas do usuário com notificação ativa, em mensagens separadas ou combinadas conforme a preferência do usuário
 // @Tags Notificações
 // @Security BearerAuth
 // @Accept json
@@ -34,6 +43,8 @@ type CreateNotificationRequest struct {
 // @Param request body CreateNotificationRequest true "Dados do agendamento"
 // @Success 201 {object} Response
 // @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 422 {object} Response
 // @Failure 500 {object} Response
 // @Router /api/notifications [post]
 func (h *NotificationHandler) Create(c *gin.Context) {
@@ -53,17 +64,14 @@ func (h *NotificationHandler) Create(c *gin.Context) {
 		return
 	}
 
-	locationID, err := uuid.Parse(req.LocationID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, Response{
-			Error: "location_id inválido",
-		})
+	locationID, ok := parseOptionalLocationID(c, req.LocationID)
+	if !ok {
 		return
 	}
 
 	err = h.notificationService.Schedule(c.Request.Context(), userID, locationID, req.ScheduleFor)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, Response{
+		c.JSON(notificationErrorStatus(err), Response{
 			Error: err.Error(),
 		})
 		return
@@ -75,15 +83,23 @@ func (h *NotificationHandler) Create(c *gin.Context) {
 }
 
 // @Summary Lista notificações do usuário
-// @Description Retorna todas as notificações de um usuário específico
+// @Description Retorna as notificações de um usuário em páginas, com o total de registros que atendem aos filtros
 // @Tags Notificações
 // @Security BearerAuth
 // @Produce json
 // @Param user_id query string true "ID do usuário" Format(uuid)
+// @Param limit query int false "Itens por página (padrão 20, máximo 100)"
+// @Param cursor query string false "Cursor retornado em next_cursor pela página anterior"
+// @Param sort query string false "Campo de ordenação (created_at, scheduled_for, updated_at, status), com prefixo - para ordem decrescente (padrão -created_at)"
+// @Param status query string false "Status separados por vírgula (PENDENTE, EM_PROCESSAMENTO, ENVIADA, FALHA, CANCELADA, EXPIRADA)"
+// @Param location_id query string false "Filtra pela localização" Format(uuid)
+// @Param from query string false "Agendadas a partir de (RFC 3339 ou AAAA-MM-DD)"
+// @Param to query string false "Agendadas antes de (RFC 3339 ou AAAA-MM-DD)"
 // @Success 200 {object} Response
 // @Failure 400 {object} Response
+// @Failure 404 {object} Response
 // @Failure 500 {object} Response
-// @Router /api/notifications/{user_id} [get]
+// @Router /api/notifications [get]
 func (h *NotificationHandler) List(c *gin.Context) {
 	userID, err := uuid.Parse(c.Query("user_id"))
 	if err != nil {
@@ -93,9 +109,26 @@ func (h *NotificationHandler) List(c *gin.Context) {
 		return
 	}
 
-	notifications, err := h.notificationService.GetUserNotifications(c.Request.Context(), userID)
+	page, err := parsePageRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	filter, err := parseNotificationFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: err.Error(),
+		})
+		return
+	}
+	filter.UserID = userID
+
+	notifications, err := h.notificationService.ListNotifications(c.Request.Context(), filter, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, Response{
+		c.JSON(listErrorStatus(err), Response{
 			Error: err.Error(),
 		})
 		return
@@ -106,10 +139,203 @@ func (h *NotificationHandler) List(c *gin.Context) {
 	})
 }
 
+// @Summary Lista entregas da notificação
+// @Description Retorna o resultado do envio da notificação em cada canal
+// @Tags Notificações
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID da notificação" Format(uuid)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/notifications/{id}/deliveries [get]
+func (h *NotificationHandler) ListDeliveries(c *gin.Context) {
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	deliveries, err := h.notificationService.GetDeliveries(c.Request.Context(), notificationID)
+	if err != nil {
+		c.JSON(notificationErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Data: deliveries,
+	})
+}
+
+// @Summary Cancela uma notificação
+// @Description Cancela uma notificação pendente ou com falha, impedindo novos envios
+// @Tags Notificações
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID da notificação" Format(uuid)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/notifications/{id}/cancel [post]
+func (h *NotificationHandler) Cancel(c *gin.Context) {
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	notification, err := h.notificationService.Cancel(c.Request.Context(), notificationID, entity.ActorAPI)
+	if err != nil {
+		c.JSON(notificationErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Message: "Notificação cancelada com sucesso",
+		Data:    notification,
+	})
+}
+
+// @Summary Reagenda uma notificação
+// @Description Altera o horário de envio de uma notificação pendente
+// @Tags Notificações
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID da notificação" Format(uuid)
+// @Param request body RescheduleNotificationRequest true "Novo horário"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/notifications/{id}/reschedule [post]
+func (h *NotificationHandler) Reschedule(c *gin.Context) {
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	var req RescheduleNotificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	notification, err := h.notificationService.Reschedule(c.Request.Context(), notificationID, req.ScheduleFor, entity.ActorAPI)
+	if err != nil {
+		c.JSON(notificationErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Message: "Notificação reagendada com sucesso",
+		Data:    notification,
+	})
+}
+
+// @Summary Lista o histórico da notificação
+// @Description Retorna as mudanças de status da notificação, com autor e erro de cada transição
+// @Tags Notificações
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID da notificação" Format(uuid)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/notifications/{id}/events [get]
+func (h *NotificationHandler) ListEvents(c *gin.Context) {
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	events, err := h.notificationService.GetEvents(c.Request.Context(), notificationID)
+	if err != nil {
+		c.JSON(notificationErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Data: events,
+	})
+}
+
 func (h *NotificationHandler) SetupRoutes(r *gin.RouterGroup) {
 	notifications := r.Group("/notifications")
 	{
 		notifications.POST("", h.Create)
 		notifications.GET("", h.List)
+		notifications.GET("/:id/deliveries", h.ListDeliveries)
+		notifications.GET("/:id/events", h.ListEvents)
+		notifications.POST("/:id/cancel", h.Cancel)
+		notifications.POST("/:id/reschedule", h.Reschedule)
 	}
 }
+
+func notificationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, handler.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, handler.ErrInvalidStatusTransition):
+		return http.StatusConflict
+	case errors.Is(err, handler.ErrInvalidScheduleTime):
+		return http.StatusBadRequest
+	case errors.Is(err, handler.ErrNoNotifiableLocations):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func parseNotificationFilter(c *gin.Context) (entity.NotificationFilter, error) {
+	var (
+		filter entity.NotificationFilter
+		err    error
+	)
+
+	if value := c.Query("status"); value != "" {
+		for _, status := range strings.Split(value, ",") {
+			status := entity.NotificationStatus(strings.ToUpper(strings.TrimSpace(status)))
+			if !status.IsValid() {
+				return filter, fmt.Errorf("%w: status %s", handler.ErrInvalidInput, status)
+			}
+			filter.Status = append(filter.Status, status)
+		}
+	}
+	if filter.LocationID, err = queryUUID(c, "location_id"); err != nil {
+		return filter, err
+	}
+	if filter.ScheduledFrom, err = queryTime(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.ScheduledBefore, err = queryTime(c, "to"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
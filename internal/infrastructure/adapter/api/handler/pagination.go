@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// parsePageRequest lê os parâmetros limit, cursor e sort comuns às
+// listagens paginadas.
+func parsePageRequest(c *gin.Context) (entity.PageRequest, error) {
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return entity.PageRequest{}, fmt.Errorf("%w: limit", handler.ErrInvalidInput)
+		}
+		limit = n
+	}
+
+	return entity.NewPageRequest(limit, c.Query("cursor"), c.Query("sort")), nil
+}
+
+// queryTime aceita datas no formato RFC 3339 ou apenas a data (AAAA-MM-DD).
+func queryTime(c *gin.Context, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", handler.ErrInvalidInput, name)
+}
+
+func queryUUID(c *gin.Context, name string) (*uuid.UUID, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", handler.ErrInvalidInput, name)
+	}
+	return &id, nil
+}
+
+func queryBool(c *gin.Context, name string) (*bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", handler.ErrInvalidInput, name)
+	}
+	return &b, nil
+}
+
+func listErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, handler.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, handler.ErrInvalidCursor),
+		errors.Is(err, handler.ErrInvalidSort),
+		errors.Is(err, handler.ErrInvalidInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
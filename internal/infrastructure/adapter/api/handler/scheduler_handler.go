@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"weather-notification/internal/domain/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SchedulerHandler struct {
+	leaderService *service.LeaderElectionService
+}
+
+func NewSchedulerHandler(leaderService *service.LeaderElectionService) *SchedulerHandler {
+	return &SchedulerHandler{
+		leaderService: leaderService,
+	}
+}
+
+// @Summary Status da liderança do agendador global
+// @Description Retorna a instância que detém a liderança do agendador de notificações globais e se a instância atual é a líder
+// @Tags Agendador
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/scheduler/leader [get]
+func (h *SchedulerHandler) GetLeader(c *gin.Context) {
+	status, err := h.leaderService.Status(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Data: status,
+	})
+}
+
+func (h *SchedulerHandler) SetupRoutes(r *gin.RouterGroup) {
+	r.GET("/scheduler/leader", h.GetLeader)
+}
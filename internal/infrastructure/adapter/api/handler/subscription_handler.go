@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SubscriptionHandler struct {
+	subscriptionService *service.SubscriptionService
+}
+
+func NewSubscriptionHandler(subscriptionService *service.SubscriptionService) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// @Summary Cria uma inscrição recorrente
+// @Description Agenda o envio recorrente da previsão para o usuário a partir de uma expressão cron ("30 6 * * 1-5") ou de um horário e dias da semana, avaliados no fuso horário do usuário
+// @Tags Inscrições
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Param request body SubscriptionRequest true "Agendamento recorrente"
+// @Success 201 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id}/subscriptions [post]
+func (h *SubscriptionHandler) Create(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	var req SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	locationID, ok := parseOptionalLocationID(c, req.LocationID)
+	if !ok {
+		return
+	}
+
+	cronExpression, err := req.CronExpression()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: err.Error(),
+		})
+		return
+	}
+	if cronExpression == "" {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "informe cron ou time",
+		})
+		return
+	}
+
+	subscription, err := h.subscriptionService.Create(c.Request.Context(), userID, locationID, cronExpression, req.Timezone)
+	if err != nil {
+		c.JSON(subscriptionErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, Response{
+		Message: "Inscrição criada com sucesso",
+		Data:    subscription,
+	})
+}
+
+// @Summary Lista inscrições recorrentes do usuário
+// @Description Retorna os agendamentos recorrentes do usuário com a próxima execução prevista
+// @Tags Inscrições
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id}/subscriptions [get]
+func (h *SubscriptionHandler) List(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	subscriptions, err := h.subscriptionService.ListByUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(subscriptionErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Data: subscriptions,
+	})
+}
+
+// @Summary Busca uma inscrição recorrente
+// @Description Retorna um agendamento recorrente do usuário
+// @Tags Inscrições
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Param subscription_id path string true "ID da inscrição" Format(uuid)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id}/subscriptions/{subscription_id} [get]
+func (h *SubscriptionHandler) Get(c *gin.Context) {
+	userID, subscriptionID, ok := parseSubscriptionIDs(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.subscriptionService.Get(c.Request.Context(), userID, subscriptionID)
+	if err != nil {
+		c.JSON(subscriptionErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Data: subscription,
+	})
+}
+
+// @Summary Atualiza uma inscrição recorrente
+// @Description Altera o agendamento, a localização ou pausa/reativa uma inscrição recorrente
+// @Tags Inscrições
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Param subscription_id path string true "ID da inscrição" Format(uuid)
+// @Param request body SubscriptionRequest true "Campos a alterar"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id}/subscriptions/{subscription_id} [put]
+func (h *SubscriptionHandler) Update(c *gin.Context) {
+	userID, subscriptionID, ok := parseSubscriptionIDs(c)
+	if !ok {
+		return
+	}
+
+	var req SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	locationID, ok := parseOptionalLocationID(c, req.LocationID)
+	if !ok {
+		return
+	}
+
+	cronExpression, err := req.CronExpression()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	subscription, err := h.subscriptionService.Update(
+		c.Request.Context(),
+		userID,
+		subscriptionID,
+		locationID,
+		cronExpression,
+		req.Timezone,
+		req.Active,
+	)
+	if err != nil {
+		c.JSON(subscriptionErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Message: "Inscrição atualizada com sucesso",
+		Data:    subscription,
+	})
+}
+
+// @Summary Remove uma inscrição recorrente
+// @Description Cancela um agendamento recorrente do usuário
+// @Tags Inscrições
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Param subscription_id path string true "ID da inscrição" Format(uuid)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id}/subscriptions/{subscription_id} [delete]
+func (h *SubscriptionHandler) Delete(c *gin.Context) {
+	userID, subscriptionID, ok := parseSubscriptionIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.subscriptionService.Delete(c.Request.Context(), userID, subscriptionID); err != nil {
+		c.JSON(subscriptionErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Message: "Inscrição removida com sucesso",
+	})
+}
+
+func (h *SubscriptionHandler) SetupRoutes(r *gin.RouterGroup) {
+	subscriptions := r.Group("/users/:id/subscriptions")
+	{
+		subscriptions.POST("", h.Create)
+		subscriptions.GET("", h.List)
+		subscriptions.GET("/:subscription_id", h.Get)
+		subscriptions.PUT("/:subscription_id", h.Update)
+		subscriptions.DELETE("/:subscription_id", h.Delete)
+	}
+}
+
+func parseSubscriptionIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "subscription_id inválido",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, subscriptionID, true
+}
+
+func parseOptionalLocationID(c *gin.Context, value string) (uuid.UUID, bool) {
+	if value == "" {
+		return uuid.Nil, true
+	}
+
+	locationID, err := uuid.Parse(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "location_id inválido",
+		})
+		return uuid.Nil, false
+	}
+
+	return locationID, true
+}
+
+func subscriptionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, handler.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, handler.ErrInvalidCronExpression),
+		errors.Is(err, handler.ErrInvalidLocationID),
+		errors.Is(err, handler.ErrInvalidTimezone):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
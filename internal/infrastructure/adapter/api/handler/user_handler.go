@@ -1,15 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strings"
-	"unicode"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
 	"weather-notification/internal/domain/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"golang.org/x/text/runes"
-	"golang.org/x/text/unicode/norm"
 )
 
 type UserHandler struct {
@@ -25,7 +25,7 @@ func NewUserHandler(userService *service.UserService, weatherService *service.We
 }
 
 // @Summary Cria um novo usuário
-// @Description Cria um usuário e vincula a uma localização
+// @Description Cria um usuário e vincula a uma localização, informada por location_id, cptec_code, latitude e longitude (a cidade mais próxima) ou nome da cidade (com a UF opcional). Se o nome corresponder a mais de uma cidade, retorna 409 com as candidatas em data
 // @Tags Usuários
 // @Security BearerAuth
 // @Accept json
@@ -33,6 +33,8 @@ func NewUserHandler(userService *service.UserService, weatherService *service.We
 // @Param request body CreateUserRequest true "Dados do usuário"
 // @Success 201 {object} Response
 // @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
 // @Failure 500 {object} Response
 // @Router /api/users [post]
 func (h *UserHandler) Create(c *gin.Context) {
@@ -44,31 +46,24 @@ func (h *UserHandler) Create(c *gin.Context) {
 		return
 	}
 
-	req.City = strings.Map(func(r rune) rune {
-		if runes.In(unicode.Mn).Contains(r) {
-			return -1
-		}
-		return r
-	}, norm.NFD.String(req.City))
-
-	locations, err := h.weatherService.SearchLocation(c.Request.Context(), req.City)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, Response{
-			Error: "erro ao buscar localização: " + err.Error(),
-		})
+	location, ok := h.resolveLocation(c, req.LocationID, entity.LocationQuery{
+		CPTECCode:   req.CPTECCode,
+		Coordinates: coordinates(req.Latitude, req.Longitude),
+		City:        req.City,
+		State:       req.State,
+	})
+	if !ok {
 		return
 	}
 
-	if len(locations) == 0 {
-		c.JSON(http.StatusInternalServerError, Response{
-			Error: "localização não encontrada",
-		})
-		return
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = location.Timezone
 	}
 
-	err = h.userService.Create(c.Request.Context(), req.Name, req.Email, locations[0].ID)
+	err := h.userService.Create(c.Request.Context(), req.Name, req.Email, location.ID, timezone, req.ForecastDays)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, Response{
+		c.JSON(userErrorStatus(err), Response{
 			Error: err.Error(),
 		})
 		return
@@ -80,7 +75,7 @@ func (h *UserHandler) Create(c *gin.Context) {
 }
 
 // @Summary Atualiza um usuário
-// @Description Atualiza os dados de um usuário
+// @Description Atualiza os dados de um usuário, incluindo se as localizações salvas recebem uma notificação cada (SEPARADA) ou uma única mensagem (COMBINADA) e quantos dias de previsão as notificações cobrem (1 a 11)
 // @Tags Usuários
 // @Security BearerAuth
 // @Accept json
@@ -109,27 +104,28 @@ func (h *UserHandler) Update(c *gin.Context) {
 		return
 	}
 
-	var locationID *uuid.UUID
-	if req.City != "" {
-		location, err := h.weatherService.SearchLocation(c.Request.Context(), req.City)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, Response{
-				Error: "erro ao buscar localização: " + err.Error(),
-			})
+	locationID := uuid.Nil
+	timezone := req.Timezone
+	query := entity.LocationQuery{
+		CPTECCode:   req.CPTECCode,
+		Coordinates: coordinates(req.Latitude, req.Longitude),
+		City:        req.City,
+		State:       req.State,
+	}
+	if req.LocationID != "" || !query.IsEmpty() {
+		location, ok := h.resolveLocation(c, req.LocationID, query)
+		if !ok {
 			return
 		}
-		if len(location) == 0 {
-			c.JSON(http.StatusNotFound, Response{
-				Error: "localização não encontrada",
-			})
-			return
+		locationID = location.ID
+		if timezone == "" {
+			timezone = location.Timezone
 		}
-		locationID = &location[0].ID
 	}
 
-	err = h.userService.Update(c.Request.Context(), userID, req.Name, *locationID)
+	err = h.userService.Update(c.Request.Context(), userID, req.Name, locationID, timezone, req.NotificationMode, req.ForecastDays)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, Response{
+		c.JSON(userErrorStatus(err), Response{
 			Error: err.Error(),
 		})
 		return
@@ -141,17 +137,41 @@ func (h *UserHandler) Update(c *gin.Context) {
 }
 
 // @Summary Lista usuários
-// @Description Retorna uma lista de todos os usuários cadastrados
+// @Description Retorna os usuários cadastrados em páginas, com o total de registros que atendem aos filtros
 // @Tags Usuários
 // @Security BearerAuth
 // @Produce json
+// @Param limit query int false "Itens por página (padrão 20, máximo 100)"
+// @Param cursor query string false "Cursor retornado em next_cursor pela página anterior"
+// @Param sort query string false "Campo de ordenação (created_at, name, email), com prefixo - para ordem decrescente"
+// @Param opt_out query bool false "Filtra pelo opt-out"
+// @Param location_id query string false "Filtra pela localização" Format(uuid)
+// @Param created_from query string false "Cadastrados a partir de (RFC 3339 ou AAAA-MM-DD)"
+// @Param created_to query string false "Cadastrados antes de (RFC 3339 ou AAAA-MM-DD)"
 // @Success 200 {object} Response
+// @Failure 400 {object} Response
 // @Failure 500 {object} Response
 // @Router /api/users [get]
 func (h *UserHandler) List(c *gin.Context) {
-	users, err := h.userService.GetAllUsers(c.Request.Context())
+	page, err := parsePageRequest(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, Response{
+		c.JSON(http.StatusBadRequest, Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	filter, err := parseUserFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	users, err := h.userService.ListUsers(c.Request.Context(), filter, page)
+	if err != nil {
+		c.JSON(listErrorStatus(err), Response{
 			Error: err.Error(),
 		})
 		return
@@ -172,6 +192,7 @@ func (h *UserHandler) List(c *gin.Context) {
 // @Param request body ToggleOptOutRequest true "Novo status do opt-out"
 // @Success 200 {object} Response
 // @Failure 400 {object} Response
+// @Failure 404 {object} Response
 // @Failure 500 {object} Response
 // @Router /api/users/{user_id}/optout [patch]
 func (h *UserHandler) ToggleOptOut(c *gin.Context) {
@@ -193,7 +214,7 @@ func (h *UserHandler) ToggleOptOut(c *gin.Context) {
 
 	err = h.userService.ToggleOptOut(c.Request.Context(), userID, req.OptOut)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, Response{
+		c.JSON(userErrorStatus(err), Response{
 			Error: err.Error(),
 		})
 		return
@@ -204,12 +225,266 @@ func (h *UserHandler) ToggleOptOut(c *gin.Context) {
 	})
 }
 
+// @Summary Atualiza os canais de notificação do usuário
+// @Description Define por quais canais (EMAIL, WEBHOOK, SLACK, SMS) o usuário recebe notificações e seus endereços
+// @Tags Usuários
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Param request body UpdateChannelsRequest true "Canais de notificação"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id}/channels [put]
+func (h *UserHandler) UpdateChannels(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	var req UpdateChannelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	channels := make([]entity.UserChannel, 0, len(req.Channels))
+	for _, channel := range req.Channels {
+		enabled := true
+		if channel.Enabled != nil {
+			enabled = *channel.Enabled
+		}
+		channels = append(channels, entity.UserChannel{
+			Channel: channel.Channel,
+			Address: channel.Address,
+			Enabled: enabled,
+		})
+	}
+
+	err = h.userService.UpdateChannels(c.Request.Context(), userID, channels)
+	if err != nil {
+		c.JSON(userErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Message: "Canais de notificação atualizados com sucesso",
+	})
+}
+
+// @Summary Busca um usuário
+// @Description Retorna os dados de um usuário pelo ID
+// @Tags Usuários
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id} [get]
+func (h *UserHandler) Get(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	user, err := h.userService.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(userErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Data: user,
+	})
+}
+
+// @Summary Busca um usuário pelo e-mail
+// @Description Retorna os dados do usuário cadastrado com o e-mail informado
+// @Tags Usuários
+// @Security BearerAuth
+// @Produce json
+// @Param email query string true "E-mail do usuário"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/by-email [get]
+func (h *UserHandler) GetByEmail(c *gin.Context) {
+	email := strings.TrimSpace(c.Query("email"))
+	if email == "" {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "email não fornecido",
+		})
+		return
+	}
+
+	user, err := h.userService.GetUserByEmail(c.Request.Context(), email)
+	if err != nil {
+		c.JSON(userErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Data: user,
+	})
+}
+
+// @Summary Remove um usuário
+// @Description Remove o usuário com suas notificações, inscrições e alertas. Notificações pendentes deixam de ser enviadas
+// @Tags Usuários
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id} [delete]
+func (h *UserHandler) Delete(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	if err := h.userService.Delete(c.Request.Context(), userID); err != nil {
+		c.JSON(userErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Message: "Usuário removido com sucesso",
+	})
+}
+
 func (h *UserHandler) SetupRoutes(r *gin.RouterGroup) {
 	users := r.Group("/users")
 	{
 		users.POST("", h.Create)
+		users.GET("/by-email", h.GetByEmail)
+		users.GET("/:id", h.Get)
 		users.PUT("/:id", h.Update)
+		users.DELETE("/:id", h.Delete)
+		users.PUT("/:id/channels", h.UpdateChannels)
 		users.GET("", h.List)
 		users.PATCH("/:user_id/optout", h.ToggleOptOut)
 	}
 }
+
+// resolveLocation encontra a localização pelo location_id, pelo código
+// CPTEC, pelas coordenadas ou pelo nome da cidade. Quando o nome corresponde
+// a mais de uma cidade, responde 409 com as candidatas.
+func (h *UserHandler) resolveLocation(c *gin.Context, locationID string, query entity.LocationQuery) (*entity.Location, bool) {
+	if locationID != "" {
+		id, err := uuid.Parse(locationID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Error: "location_id inválido",
+			})
+			return nil, false
+		}
+		query.ID = id
+	}
+
+	location, err := h.weatherService.ResolveLocation(c.Request.Context(), query)
+	if err != nil {
+		var ambiguous *entity.AmbiguousLocationError
+		if errors.As(err, &ambiguous) {
+			c.JSON(http.StatusConflict, Response{
+				Error: err.Error(),
+				Data:  ambiguous.Candidates,
+			})
+			return nil, false
+		}
+
+		status, message := userErrorStatus(err), err.Error()
+		switch status {
+		case http.StatusNotFound:
+			message = "localização não encontrada"
+		case http.StatusInternalServerError:
+			message = "erro ao buscar localização: " + err.Error()
+		}
+		c.JSON(status, Response{
+			Error: message,
+		})
+		return nil, false
+	}
+
+	return location, true
+}
+
+func coordinates(latitude, longitude *float64) *entity.Coordinates {
+	if latitude == nil || longitude == nil {
+		return nil
+	}
+	return &entity.Coordinates{Latitude: *latitude, Longitude: *longitude}
+}
+
+func userErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, handler.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, handler.ErrDuplicateKey):
+		return http.StatusConflict
+	case errors.Is(err, handler.ErrEmptyName),
+		errors.Is(err, handler.ErrEmptyEmail),
+		errors.Is(err, handler.ErrInvalidEmail),
+		errors.Is(err, handler.ErrInvalidLocationID),
+		errors.Is(err, handler.ErrInvalidState),
+		errors.Is(err, handler.ErrInvalidCoordinates),
+		errors.Is(err, handler.ErrInvalidInput),
+		errors.Is(err, handler.ErrInvalidTimezone),
+		errors.Is(err, handler.ErrInvalidForecastDays),
+		errors.Is(err, handler.ErrInvalidNotificationMode),
+		errors.Is(err, handler.ErrInvalidChannel),
+		errors.Is(err, handler.ErrEmptyChannelAddress),
+		errors.Is(err, handler.ErrInvalidChannelAddress):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func parseUserFilter(c *gin.Context) (entity.UserFilter, error) {
+	var (
+		filter entity.UserFilter
+		err    error
+	)
+
+	if filter.OptOut, err = queryBool(c, "opt_out"); err != nil {
+		return filter, err
+	}
+	if filter.LocationID, err = queryUUID(c, "location_id"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedFrom, err = queryTime(c, "created_from"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedBefore, err = queryTime(c, "created_to"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type UserLocationHandler struct {
+	userLocationService *service.UserLocationService
+}
+
+func NewUserLocationHandler(userLocationService *service.UserLocationService) *UserLocationHandler {
+	return &UserLocationHandler{
+		userLocationService: userLocationService,
+	}
+}
+
+// @Summary Salva uma localização do usuário
+// @Description Adiciona uma localização com rótulo ("casa", "casa de praia") às localizações do usuário. Com primary a localização passa a ser a principal do cadastro
+// @Tags Localizações do usuário
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Param request body UserLocationRequest true "Localização a salvar"
+// @Success 201 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id}/locations [post]
+func (h *UserLocationHandler) Create(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	var req UserLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	locationID, err := uuid.Parse(req.LocationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "location_id inválido",
+		})
+		return
+	}
+
+	location, err := h.userLocationService.Add(c.Request.Context(), userID, locationID, req.Label, req.Notify, req.Primary)
+	if err != nil {
+		c.JSON(userLocationErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, Response{
+		Message: "Localização salva com sucesso",
+		Data:    location,
+	})
+}
+
+// @Summary Lista as localizações do usuário
+// @Description Retorna as localizações salvas do usuário, com a principal primeiro
+// @Tags Localizações do usuário
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id}/locations [get]
+func (h *UserLocationHandler) List(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return
+	}
+
+	locations, err := h.userLocationService.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(userLocationErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Data: locations,
+	})
+}
+
+// @Summary Atualiza uma localização do usuário
+// @Description Altera o rótulo, ativa ou desativa as notificações da localização ou a torna a principal
+// @Tags Localizações do usuário
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Param user_location_id path string true "ID da localização salva" Format(uuid)
+// @Param request body UserLocationRequest true "Campos a alterar"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id}/locations/{user_location_id} [put]
+func (h *UserLocationHandler) Update(c *gin.Context) {
+	userID, userLocationID, ok := parseUserLocationIDs(c)
+	if !ok {
+		return
+	}
+
+	var req UserLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	location, err := h.userLocationService.Update(c.Request.Context(), userID, userLocationID, req.Label, req.Notify, req.Primary)
+	if err != nil {
+		c.JSON(userLocationErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Message: "Localização atualizada com sucesso",
+		Data:    location,
+	})
+}
+
+// @Summary Remove uma localização do usuário
+// @Description Remove uma localização salva. A localização principal só pode ser removida depois que outra for escolhida como principal
+// @Tags Localizações do usuário
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ID do usuário" Format(uuid)
+// @Param user_location_id path string true "ID da localização salva" Format(uuid)
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/users/{id}/locations/{user_location_id} [delete]
+func (h *UserLocationHandler) Delete(c *gin.Context) {
+	userID, userLocationID, ok := parseUserLocationIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userLocationService.Remove(c.Request.Context(), userID, userLocationID); err != nil {
+		c.JSON(userLocationErrorStatus(err), Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Message: "Localização removida com sucesso",
+	})
+}
+
+func (h *UserLocationHandler) SetupRoutes(r *gin.RouterGroup) {
+	locations := r.Group("/users/:id/locations")
+	{
+		locations.POST("", h.Create)
+		locations.GET("", h.List)
+		locations.PUT("/:user_location_id", h.Update)
+		locations.DELETE("/:user_location_id", h.Delete)
+	}
+}
+
+func parseUserLocationIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "ID inválido",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userLocationID, err := uuid.Parse(c.Param("user_location_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "user_location_id inválido",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, userLocationID, true
+}
+
+func userLocationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, handler.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, handler.ErrDuplicateKey),
+		errors.Is(err, handler.ErrPrimaryLocationRequired):
+		return http.StatusConflict
+	case errors.Is(err, handler.ErrInvalidLocationID),
+		errors.Is(err, handler.ErrEmptyLocationLabel),
+		errors.Is(err, handler.ErrInvalidLocationLabel):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
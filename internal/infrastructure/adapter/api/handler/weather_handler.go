@@ -1,15 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
-	"strings"
-	"unicode"
+	"strconv"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
 	"weather-notification/internal/domain/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"golang.org/x/text/runes"
-	"golang.org/x/text/unicode/norm"
 )
 
 type WeatherHandler struct {
@@ -23,11 +23,12 @@ func NewWeatherHandler(weatherService *service.WeatherService) *WeatherHandler {
 }
 
 // @Summary Busca cidade por nome
-// @Description Busca uma cidade no CPTEC por nome
+// @Description Busca uma cidade por nome, opcionalmente restrita a uma UF, no catálogo local (tolerante a acentos e erros de digitação) e, sem correspondência exata, sem acentos ou por prefixo, no CPTEC. Os resultados vêm ordenados pela correspondência com o nome (EXATA, SEM_ACENTO, PREFIXO, PARCIAL, APROXIMADA)
 // @Tags Localizações
 // @Security BearerAuth
 // @Produce json
 // @Param city query string true "Nome da cidade"
+// @Param state query string false "UF da cidade" example(RS)
 // @Success 200 {object} Response
 // @Failure 400 {object} Response
 // @Failure 500 {object} Response
@@ -41,16 +42,73 @@ func (h *WeatherHandler) SearchLocation(c *gin.Context) {
 		return
 	}
 
-	cityName = strings.Map(func(r rune) rune {
-		if runes.In(unicode.Mn).Contains(r) {
-			return -1
+	locations, err := h.weatherService.SearchLocation(c.Request.Context(), cityName, c.Query("state"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, handler.ErrInvalidState) {
+			status = http.StatusBadRequest
 		}
-		return r
-	}, norm.NFD.String(cityName))
+		c.JSON(status, Response{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Data: locations,
+	})
+}
+
+const (
+	defaultNearestLimit = 5
+	maxNearestLimit     = 20
+)
 
-	locations, err := h.weatherService.SearchLocation(c.Request.Context(), cityName)
+// @Summary Busca as cidades mais próximas
+// @Description Retorna as cidades do catálogo mais próximas das coordenadas, ordenadas pela distância em quilômetros (fórmula de haversine). Só participam cidades importadas com latitude e longitude
+// @Tags Localizações
+// @Security BearerAuth
+// @Produce json
+// @Param lat query number true "Latitude" example(-23.4336)
+// @Param lon query number true "Longitude" example(-45.0838)
+// @Param limit query int false "Quantidade de cidades (padrão 5, máximo 20)"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Failure 500 {object} Response
+// @Router /api/weather/nearest [get]
+func (h *WeatherHandler) NearestLocations(c *gin.Context) {
+	latitude, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	longitude, errLon := strconv.ParseFloat(c.Query("lon"), 64)
+	if errLat != nil || errLon != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Error: "lat e lon são obrigatórios e devem ser números",
+		})
+		return
+	}
+
+	limit := defaultNearestLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxNearestLimit {
+			c.JSON(http.StatusBadRequest, Response{
+				Error: "limit deve ser um número entre 1 e 20",
+			})
+			return
+		}
+		limit = n
+	}
+
+	locations, err := h.weatherService.NearestLocations(c.Request.Context(), latitude, longitude, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, Response{
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, handler.ErrInvalidCoordinates):
+			status = http.StatusBadRequest
+		case errors.Is(err, handler.ErrNotFound):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, Response{
 			Error: err.Error(),
 		})
 		return
@@ -62,11 +120,12 @@ func (h *WeatherHandler) SearchLocation(c *gin.Context) {
 }
 
 // @Summary Busca previsão do tempo
-// @Description Retorna a previsão do tempo para uma localidade
+// @Description Retorna a previsão do tempo para uma localidade. Com days acima de 4 usa a previsão de 7 dias ou a estendida do CPTEC, informada em horizon
 // @Tags Clima
 // @Security BearerAuth
 // @Produce json
 // @Param location_id query string true "ID da localidade" Format(uuid)
+// @Param days query int false "Dias de previsão, de 1 a 11 (padrão 4)"
 // @Success 200 {object} Response
 // @Failure 400 {object} Response
 // @Failure 500 {object} Response
@@ -80,9 +139,24 @@ func (h *WeatherHandler) GetForecast(c *gin.Context) {
 		return
 	}
 
-	forecast, err := h.weatherService.GetForecast(c.Request.Context(), locationID)
+	days := entity.DefaultForecastDays
+	if value := c.Query("days"); value != "" {
+		days, err = strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Error: "days deve ser um número",
+			})
+			return
+		}
+	}
+
+	forecast, err := h.weatherService.GetForecastForDays(c.Request.Context(), locationID, days)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, Response{
+		status := http.StatusInternalServerError
+		if errors.Is(err, handler.ErrInvalidForecastDays) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, Response{
 			Error: err.Error(),
 		})
 		return
@@ -93,10 +167,39 @@ func (h *WeatherHandler) GetForecast(c *gin.Context) {
 	})
 }
 
+// @Summary Estatísticas do cache de previsões
+// @Description Retorna a quantidade de acertos e falhas do cache de previsões do CPTEC
+// @Tags Clima
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} Response
+// @Router /api/weather/cache/stats [get]
+func (h *WeatherHandler) GetCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, Response{
+		Data: h.weatherService.CacheStats(),
+	})
+}
+
+// @Summary Lista as condições do tempo do CPTEC
+// @Description Retorna o catálogo de códigos de condição do CPTEC com descrição, severidade e ícone
+// @Tags Clima
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} Response
+// @Router /api/weather/conditions [get]
+func (h *WeatherHandler) ListConditions(c *gin.Context) {
+	c.JSON(http.StatusOK, Response{
+		Data: entity.ConditionCatalog(),
+	})
+}
+
 func (h *WeatherHandler) SetupRoutes(r *gin.RouterGroup) {
 	weather := r.Group("/weather")
 	{
 		weather.GET("/search", h.SearchLocation)
+		weather.GET("/nearest", h.NearestLocations)
 		weather.GET("/forecast", h.GetForecast)
+		weather.GET("/cache/stats", h.GetCacheStats)
+		weather.GET("/conditions", h.ListConditions)
 	}
 }
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/domain/service"
+)
+
+type forecastKey struct {
+	cptecCode int
+	horizon   entity.ForecastHorizon
+}
+
+type forecastEntry struct {
+	forecast  entity.WeatherForecastCollection
+	expiresAt time.Time
+}
+
+type MemoryForecastCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[forecastKey]forecastEntry
+}
+
+func NewMemoryForecastCache(ttl time.Duration) service.ForecastCache {
+	return &MemoryForecastCache{
+		ttl:     ttl,
+		entries: make(map[forecastKey]forecastEntry),
+	}
+}
+
+func (c *MemoryForecastCache) Get(ctx context.Context, cptecCode int, horizon entity.ForecastHorizon) (*entity.WeatherForecastCollection, bool) {
+	key := forecastKey{cptecCode: cptecCode, horizon: horizon}
+
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		c.mu.Lock()
+		if current, ok := c.entries[key]; ok && current.expiresAt.Equal(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+		return nil, false
+	}
+
+	return copyForecast(&entry.forecast), true
+}
+
+func (c *MemoryForecastCache) Set(ctx context.Context, cptecCode int, forecast *entity.WeatherForecastCollection) error {
+	now := time.Now()
+	key := forecastKey{cptecCode: cptecCode, horizon: forecast.Horizon}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if current, ok := c.entries[key]; ok && now.Before(current.expiresAt) &&
+		forecast.IssuedAt.Before(current.forecast.IssuedAt) {
+		return nil
+	}
+
+	c.entries[key] = forecastEntry{
+		forecast:  *copyForecast(forecast),
+		expiresAt: forecast.ExpiresAt(now, c.ttl),
+	}
+
+	return nil
+}
+
+// copyForecast copia a coleção inteira, inclusive as ondas de cada dia, para
+// que quem lê ou grava no cache não altere a entrada de outro chamador.
+func copyForecast(forecast *entity.WeatherForecastCollection) *entity.WeatherForecastCollection {
+	clone := *forecast
+	clone.Forecasts = make([]entity.WeatherForecast, len(forecast.Forecasts))
+	copy(clone.Forecasts, forecast.Forecasts)
+	for i, day := range clone.Forecasts {
+		if day.Wave != nil {
+			wave := *day.Wave
+			clone.Forecasts[i].Wave = &wave
+		}
+	}
+	return &clone
+}
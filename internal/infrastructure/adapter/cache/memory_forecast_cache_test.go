@@ -0,0 +1,125 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/infrastructure/adapter/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryForecastCache_GetSet(t *testing.T) {
+	ctx := context.Background()
+	issuedAt, _ := time.Parse("2006-01-02", time.Now().AddDate(0, 0, -1).Format("2006-01-02"))
+
+	forecast := &entity.WeatherForecastCollection{
+		Nome:      "Campinas",
+		UF:        "SP",
+		Horizon:   entity.ForecastHorizon4Days,
+		Forecasts: []entity.WeatherForecast{{MinTemp: 18, MaxTemp: 30, Forecast: "ps"}},
+		IssuedAt:  issuedAt,
+	}
+
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		wait     time.Duration
+		cptec    int
+		horizon  entity.ForecastHorizon
+		expectOK bool
+	}{
+		{
+			name:     "acerto dentro do TTL",
+			ttl:      time.Minute,
+			cptec:    244,
+			horizon:  entity.ForecastHorizon4Days,
+			expectOK: true,
+		},
+		{
+			name:     "falha para código desconhecido",
+			ttl:      time.Minute,
+			cptec:    999,
+			horizon:  entity.ForecastHorizon4Days,
+			expectOK: false,
+		},
+		{
+			name:     "falha para outro horizonte",
+			ttl:      time.Minute,
+			cptec:    244,
+			horizon:  entity.ForecastHorizon7Days,
+			expectOK: false,
+		},
+		{
+			name:     "falha após TTL expirar",
+			ttl:      10 * time.Millisecond,
+			wait:     20 * time.Millisecond,
+			cptec:    244,
+			horizon:  entity.ForecastHorizon4Days,
+			expectOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := cache.NewMemoryForecastCache(tt.ttl)
+			assert.NoError(t, c.Set(ctx, 244, forecast))
+
+			time.Sleep(tt.wait)
+
+			cached, ok := c.Get(ctx, tt.cptec, tt.horizon)
+			assert.Equal(t, tt.expectOK, ok)
+			if tt.expectOK {
+				assert.Equal(t, forecast.Nome, cached.Nome)
+				assert.Equal(t, forecast.Forecasts, cached.Forecasts)
+			}
+		})
+	}
+}
+
+func TestMemoryForecastCache_KeepsNewerIssue(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryForecastCache(time.Hour)
+
+	newer := &entity.WeatherForecastCollection{Nome: "nova", Horizon: entity.ForecastHorizon4Days, IssuedAt: time.Now().AddDate(0, 0, -1)}
+	older := &entity.WeatherForecastCollection{Nome: "antiga", Horizon: entity.ForecastHorizon4Days, IssuedAt: time.Now().AddDate(0, 0, -2)}
+
+	assert.NoError(t, c.Set(ctx, 244, newer))
+	assert.NoError(t, c.Set(ctx, 244, older))
+
+	cached, ok := c.Get(ctx, 244, entity.ForecastHorizon4Days)
+	assert.True(t, ok)
+	assert.Equal(t, "nova", cached.Nome)
+}
+
+func TestMemoryForecastCache_IsolatesWaveInfo(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryForecastCache(time.Hour)
+
+	forecast := &entity.WeatherForecastCollection{
+		Nome:    "Santos",
+		Horizon: entity.ForecastHorizon4Days,
+		Forecasts: []entity.WeatherForecast{{
+			MinTemp: 20,
+			MaxTemp: 28,
+			Wave:    &entity.WaveInfo{Morning: entity.WavePeriod{Height: 1.2}},
+		}},
+		IssuedAt: time.Now().AddDate(0, 0, -1),
+	}
+	assert.NoError(t, c.Set(ctx, 244, forecast))
+
+	// Alterar o valor gravado não afeta o cache
+	forecast.Forecasts[0].Wave.Morning.Height = 3.5
+
+	cached, ok := c.Get(ctx, 244, entity.ForecastHorizon4Days)
+	assert.True(t, ok)
+	assert.Equal(t, 1.2, cached.Forecasts[0].Wave.Morning.Height)
+
+	// Alterar o valor lido também não afeta as próximas leituras
+	cached.Forecasts[0].Wave.Morning.Height = 4.0
+
+	cached, ok = c.Get(ctx, 244, entity.ForecastHorizon4Days)
+	assert.True(t, ok)
+	assert.Equal(t, 1.2, cached.Forecasts[0].Wave.Morning.Height)
+}
@@ -0,0 +1,117 @@
+package cptec
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"weather-notification/internal/domain/entity"
+)
+
+const (
+	CatalogXML = "xml"
+	CatalogCSV = "csv"
+)
+
+// CatalogFormat deduz o formato do catálogo pela extensão do arquivo.
+func CatalogFormat(path string) string {
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+}
+
+// ReadCityCatalog lê a lista de cidades do CPTEC no XML devolvido por
+// listaCidades ou em CSV com as colunas id, nome e uf (separadas por
+// vírgula ou ponto e vírgula, com cabeçalho opcional). O CSV pode trazer
+// ainda as colunas latitude e longitude, que o XML do CPTEC não informa.
+// Linhas com código, nome ou UF inválidos são ignoradas, assim como
+// coordenadas inválidas.
+func ReadCityCatalog(r io.Reader, format string) ([]entity.Location, error) {
+	switch strings.ToLower(format) {
+	case CatalogXML:
+		return readCatalogXML(r)
+	case CatalogCSV:
+		return readCatalogCSV(r)
+	default:
+		return nil, fmt.Errorf("formato de catálogo não suportado: %q", format)
+	}
+}
+
+func readCatalogXML(r io.Reader) ([]entity.Location, error) {
+	var result cityResponse
+	if err := decodeISO88591XML(r, &result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar XML: %w", err)
+	}
+
+	locations := make([]entity.Location, 0, len(result.Cities))
+	for _, city := range result.Cities {
+		location, err := entity.NewLocation(city.ID, strings.TrimSpace(city.Name), strings.TrimSpace(city.State))
+		if err != nil {
+			continue
+		}
+		locations = append(locations, *location)
+	}
+
+	return locations, nil
+}
+
+func readCatalogCSV(r io.Reader) ([]entity.Location, error) {
+	buffered := bufio.NewReader(r)
+	firstLine, err := buffered.Peek(buffered.Size())
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if line, _, _ := strings.Cut(string(firstLine), "\n"); strings.Contains(line, ";") {
+		reader.Comma = ';'
+	}
+
+	var locations []entity.Location
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler CSV: %w", err)
+		}
+		if len(record) < 3 {
+			continue
+		}
+
+		id, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		if err != nil {
+			continue
+		}
+
+		location, err := entity.NewLocation(id, strings.TrimSpace(record[1]), strings.ToUpper(strings.TrimSpace(record[2])))
+		if err != nil {
+			continue
+		}
+		if len(record) >= 5 {
+			location.Coordinates = parseCoordinates(record[3], record[4])
+		}
+		locations = append(locations, *location)
+	}
+
+	return locations, nil
+}
+
+func parseCoordinates(latitude, longitude string) *entity.Coordinates {
+	lat, errLat := strconv.ParseFloat(strings.TrimSpace(strings.Replace(latitude, ",", ".", 1)), 64)
+	lon, errLon := strconv.ParseFloat(strings.TrimSpace(strings.Replace(longitude, ",", ".", 1)), 64)
+	if errLat != nil || errLon != nil {
+		return nil
+	}
+
+	coordinates, err := entity.NewCoordinates(lat, lon)
+	if err != nil {
+		return nil
+	}
+	return coordinates
+}
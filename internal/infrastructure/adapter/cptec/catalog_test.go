@@ -0,0 +1,65 @@
+package cptec_test
+
+import (
+	"strings"
+	"testing"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/infrastructure/adapter/cptec"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCityCatalog(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		content  string
+		expected []string
+	}{
+		{
+			name:   "XML do CPTEC em ISO-8859-1",
+			format: cptec.CatalogXML,
+			content: "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><cidades>" +
+				"<cidade><nome>S\xe3o Paulo</nome><uf>SP</uf><id>244</id></cidade>" +
+				"<cidade><nome>Ubatuba</nome><uf>SP</uf><id>5515</id></cidade>" +
+				"</cidades>",
+			expected: []string{"São Paulo", "Ubatuba"},
+		},
+		{
+			name:     "CSV com cabeçalho e linha inválida",
+			format:   cptec.CatalogCSV,
+			content:  "id,nome,uf\n244,São Paulo,sp\nabc,Sem código,SP\n5515,Ubatuba,SP\n",
+			expected: []string{"São Paulo", "Ubatuba"},
+		},
+		{
+			name:     "CSV separado por ponto e vírgula",
+			format:   cptec.CatalogCSV,
+			content:  "244;São Paulo;SP\n5515;Ubatuba;SP\n",
+			expected: []string{"São Paulo", "Ubatuba"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locations, err := cptec.ReadCityCatalog(strings.NewReader(tt.content), tt.format)
+			assert.NoError(t, err)
+
+			names := make([]string, 0, len(locations))
+			for _, location := range locations {
+				assert.Equal(t, "SP", location.State)
+				names = append(names, location.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+
+	locations, err := cptec.ReadCityCatalog(strings.NewReader("5515;Ubatuba;SP;-23,4336;-45,0838\n244;São Paulo;SP;;\n"), cptec.CatalogCSV)
+	assert.NoError(t, err)
+	if assert.Len(t, locations, 2) {
+		assert.Equal(t, &entity.Coordinates{Latitude: -23.4336, Longitude: -45.0838}, locations[0].Coordinates)
+		assert.Nil(t, locations[1].Coordinates)
+	}
+
+	_, err = cptec.ReadCityCatalog(strings.NewReader(""), "json")
+	assert.Error(t, err)
+}
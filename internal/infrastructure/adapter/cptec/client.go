@@ -31,10 +31,11 @@ type cityResponse struct {
 }
 
 type forecastResponse struct {
-	XMLName   xml.Name `xml:"cidade"`
-	Name      string   `xml:"nome"`
-	State     string   `xml:"uf"`
-	Forecasts []struct {
+	XMLName    xml.Name `xml:"cidade"`
+	Name       string   `xml:"nome"`
+	State      string   `xml:"uf"`
+	UpdateTime string   `xml:"atualizacao"`
+	Forecasts  []struct {
 		Date     string `xml:"dia"`
 		MinTemp  string `xml:"minima"`
 		MaxTemp  string `xml:"maxima"`
@@ -110,7 +111,22 @@ func (c *Client) SearchCities(ctx context.Context, cityName string) ([]entity.Lo
 
 func (c *Client) GetWeatherForecast(ctx context.Context, cptecCode int) (*entity.WeatherForecastCollection, error) {
 	endpoint := fmt.Sprintf("%s/cidade/%d/previsao.xml", c.baseURL, cptecCode)
+	return c.getForecast(ctx, endpoint, entity.ForecastHorizon4Days)
+}
+
+func (c *Client) GetSevenDayForecast(ctx context.Context, cptecCode int) (*entity.WeatherForecastCollection, error) {
+	endpoint := fmt.Sprintf("%s/cidade/7dias/%d/previsao.xml", c.baseURL, cptecCode)
+	return c.getForecast(ctx, endpoint, entity.ForecastHorizon7Days)
+}
+
+// GetExtendedForecast retorna a previsão estendida, com os dias seguintes
+// aos da previsão de 4 dias. O CPTEC não informa o índice UV nela.
+func (c *Client) GetExtendedForecast(ctx context.Context, cptecCode int) (*entity.WeatherForecastCollection, error) {
+	endpoint := fmt.Sprintf("%s/cidade/%d/estendida.xml", c.baseURL, cptecCode)
+	return c.getForecast(ctx, endpoint, entity.ForecastHorizonExtended)
+}
 
+func (c *Client) getForecast(ctx context.Context, endpoint string, horizon entity.ForecastHorizon) (*entity.WeatherForecastCollection, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar request: %w", err)
@@ -141,25 +157,27 @@ func (c *Client) GetWeatherForecast(ctx context.Context, cptecCode int) (*entity
 			continue
 		}
 
-		iuv, err := strconv.ParseFloat(f.IUV, 64)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao converter: %w", err)
+		var iuv float64
+		if f.IUV != "" {
+			iuv, err = strconv.ParseFloat(f.IUV, 64)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao converter: %w", err)
+			}
 		}
 
 		minTemp := parseTemperature(f.MinTemp)
 		maxTemp := parseTemperature(f.MaxTemp)
 
-		forecast := entity.WeatherForecast{
-			Date:     date,
-			MinTemp:  minTemp,
-			MaxTemp:  maxTemp,
-			Forecast: f.Forecast,
-			UV:       iuv,
-		}
-		forecasts = append(forecasts, forecast)
+		forecasts = append(forecasts, entity.NewWeatherForecast(date, minTemp, maxTemp, f.Forecast, iuv))
+	}
+
+	collection := entity.NewWeatherForecastCollection(uuid.New(), result.Name, result.State, forecasts)
+	collection.Horizon = horizon
+	if issuedAt, err := time.Parse("2006-01-02", result.UpdateTime); err == nil {
+		collection.IssuedAt = issuedAt
 	}
 
-	return entity.NewWeatherForecastCollection(uuid.New(), result.Name, result.State, forecasts), nil
+	return collection, nil
 }
 
 func (c *Client) GetWaveForecast(ctx context.Context, cptecCode int, date time.Time) (*entity.WaveInfo, error) {
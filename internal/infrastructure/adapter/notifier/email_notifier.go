@@ -0,0 +1,185 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+)
+
+type EmailConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	// Insecure desliga o STARTTLS obrigatório. Serve apenas para servidores
+	// locais de desenvolvimento, como o MailHog, e não permite autenticação.
+	Insecure bool
+	Timeout  time.Duration
+}
+
+type EmailNotifier struct {
+	config EmailConfig
+}
+
+func NewEmailNotifier(config EmailConfig) *EmailNotifier {
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	return &EmailNotifier{
+		config: config,
+	}
+}
+
+func (n *EmailNotifier) Channel() entity.Channel {
+	return entity.ChannelEmail
+}
+
+func (n *EmailNotifier) Send(ctx context.Context, notification *entity.Notification, address string) error {
+	if address == "" {
+		return handler.ErrEmptyChannelAddress
+	}
+
+	message, err := n.buildMessage(notification, address)
+	if err != nil {
+		return fmt.Errorf("erro ao montar email: %w", err)
+	}
+
+	if err := n.deliver(ctx, address, message); err != nil {
+		return fmt.Errorf("erro ao enviar email: %w", err)
+	}
+
+	return nil
+}
+
+func (n *EmailNotifier) buildMessage(notification *entity.Notification, address string) ([]byte, error) {
+	data := newEmailTemplateData(notification)
+
+	var text, html bytes.Buffer
+	if err := textEmailTemplate.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := htmlEmailTemplate.Execute(&html, data); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     []byte
+	}{
+		{"text/plain; charset=UTF-8", text.Bytes()},
+		{"text/html; charset=UTF-8", html.Bytes()},
+	}
+
+	for _, p := range parts {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qp := quotedprintable.NewWriter(part)
+		if _, err := qp.Write(p.content); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	subject := "Previsão do tempo - " + data.Subject()
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&message, "To: %s\r\n", address)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "Message-ID: <%s@weather-notification>\r\n", notification.ID)
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%s\r\n", writer.Boundary())
+	fmt.Fprintf(&message, "\r\n")
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+func (n *EmailNotifier) deliver(ctx context.Context, address string, message []byte) error {
+	dialer := &net.Dialer{Timeout: n.config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(n.config.Host, n.config.Port))
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(n.config.Timeout))
+	}
+
+	client, err := smtp.NewClient(conn, n.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if n.config.Insecure {
+		if n.config.Username != "" {
+			return fmt.Errorf("autenticação SMTP exige STARTTLS")
+		}
+	} else {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("servidor SMTP não suporta STARTTLS")
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: n.config.Host}); err != nil {
+			return err
+		}
+	}
+
+	if n.config.Username != "" {
+		auth := smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.config.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(address); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,194 @@
+package notifier_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/infrastructure/adapter/notifier"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSMTPServer struct {
+	listener   net.Listener
+	recipients []string
+	messages   chan string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeSMTPServer{
+		listener: listener,
+		messages: make(chan string, 1),
+	}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return server
+}
+
+func (s *fakeSMTPServer) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM"):
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO"):
+			s.recipients = append(s.recipients, strings.TrimSpace(line[len("RCPT TO:"):]))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.messages <- data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func TestEmailNotifier_Send(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	host, port, err := net.SplitHostPort(server.listener.Addr().String())
+	require.NoError(t, err)
+
+	emailNotifier := notifier.NewEmailNotifier(notifier.EmailConfig{
+		Host:     host,
+		Port:     port,
+		From:     "previsao@exemplo.com",
+		Insecure: true,
+	})
+
+	notification := &entity.Notification{
+		ID:     uuid.New(),
+		UserID: uuid.New(),
+		Content: entity.WeatherForecastCollection{
+			Nome: "Ubatuba",
+			UF:   "SP",
+			Forecasts: []entity.WeatherForecast{
+				{
+					Date:     time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+					MinTemp:  21,
+					MaxTemp:  31.5,
+					Forecast: "ps",
+					UV:       11,
+					Wave: &entity.WaveInfo{
+						Morning:   entity.WavePeriod{Height: 1.2, Direction: "SE", Agitation: "Fraco"},
+						Afternoon: entity.WavePeriod{Height: 1.5, Direction: "S", Agitation: "Moderado"},
+						Night:     entity.WavePeriod{Height: 0.9, Direction: "SE", Agitation: "Fraco"},
+					},
+				},
+			},
+		},
+	}
+
+	err = emailNotifier.Send(context.Background(), notification, "matheus@exemplo.com")
+	require.NoError(t, err)
+
+	var raw string
+	select {
+	case raw = <-server.messages:
+	case <-time.After(time.Second):
+		t.Fatal("servidor SMTP não recebeu a mensagem")
+	}
+
+	assert.Equal(t, []string{"<matheus@exemplo.com>"}, server.recipients)
+
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Previsão do tempo - Ubatuba/SP", subject)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(part)
+		require.NoError(t, err)
+
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(content)
+	}
+
+	assert.Contains(t, parts["text/plain"], "Máxima: 31.5°C")
+	assert.Contains(t, parts["text/plain"], "Ondas tarde: 1.5m S (Moderado)")
+	assert.Contains(t, parts["text/html"], "<td>31.5°C</td>")
+	assert.Contains(t, parts["text/html"], "<h3>Ondas em 02/02</h3>")
+}
+
+func TestEmailNotifier_RequiresStartTLS(t *testing.T) {
+	tests := []struct {
+		name   string
+		config notifier.EmailConfig
+	}{
+		{"servidor sem STARTTLS", notifier.EmailConfig{From: "previsao@exemplo.com"}},
+		{"credenciais sem STARTTLS", notifier.EmailConfig{From: "previsao@exemplo.com", Username: "previsao", Password: "segredo", Insecure: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeSMTPServer(t)
+			host, port, err := net.SplitHostPort(server.listener.Addr().String())
+			require.NoError(t, err)
+
+			tt.config.Host, tt.config.Port = host, port
+			err = notifier.NewEmailNotifier(tt.config).Send(context.Background(), &entity.Notification{ID: uuid.New()}, "matheus@exemplo.com")
+
+			assert.Error(t, err)
+			assert.Empty(t, server.recipients)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package notifier
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"weather-notification/internal/domain/entity"
+)
+
+type emailTemplateData struct {
+	Message   string
+	Locations []emailLocation
+}
+
+type emailLocation struct {
+	Title     string
+	Forecasts []entity.WeatherForecast
+}
+
+var templateFuncs = map[string]interface{}{
+	"date": func(f entity.WeatherForecast) string {
+		return f.Date.Format("02/01")
+	},
+}
+
+var textEmailTemplate = texttemplate.Must(texttemplate.New("text").Funcs(templateFuncs).Parse(
+	`{{if .Message}}{{.Message}}
+
+{{end}}{{range $i, $location := .Locations}}{{if $i}}
+{{end}}Previsão do tempo para {{$location.Title}}
+{{range $location.Forecasts}}
+{{date .}} - {{.Icon}} {{.Description}}
+  Mínima: {{printf "%.1f" .MinTemp}}°C | Máxima: {{printf "%.1f" .MaxTemp}}°C | UV: {{printf "%.1f" .UV}}
+{{- if .HasWaveForecast}}
+  Ondas manhã: {{printf "%.1f" .Wave.Morning.Height}}m {{.Wave.Morning.Direction}} ({{.Wave.Morning.Agitation}}), vento {{printf "%.1f" .Wave.Morning.WindSpeed}} km/h {{.Wave.Morning.WindDir}}
+  Ondas tarde: {{printf "%.1f" .Wave.Afternoon.Height}}m {{.Wave.Afternoon.Direction}} ({{.Wave.Afternoon.Agitation}}), vento {{printf "%.1f" .Wave.Afternoon.WindSpeed}} km/h {{.Wave.Afternoon.WindDir}}
+  Ondas noite: {{printf "%.1f" .Wave.Night.Height}}m {{.Wave.Night.Direction}} ({{.Wave.Night.Agitation}}), vento {{printf "%.1f" .Wave.Night.WindSpeed}} km/h {{.Wave.Night.WindDir}}
+{{- end}}
+{{end}}{{end}}`))
+
+var htmlEmailTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(templateFuncs).Parse(
+	`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif;">
+{{- if .Message}}
+<p><strong>{{.Message}}</strong></p>
+{{- end}}
+{{- range .Locations}}
+<h2>Previsão do tempo para {{.Title}}</h2>
+<table border="1" cellpadding="6" cellspacing="0" style="border-collapse: collapse;">
+<tr><th>Dia</th><th>Tempo</th><th>Mínima</th><th>Máxima</th><th>UV</th></tr>
+{{- range .Forecasts}}
+<tr><td>{{date .}}</td><td>{{.Icon}} {{.Description}}</td><td>{{printf "%.1f" .MinTemp}}°C</td><td>{{printf "%.1f" .MaxTemp}}°C</td><td>{{printf "%.1f" .UV}}</td></tr>
+{{- end}}
+</table>
+{{- range .Forecasts}}
+{{- if .HasWaveForecast}}
+<h3>Ondas em {{date .}}</h3>
+<table border="1" cellpadding="6" cellspacing="0" style="border-collapse: collapse;">
+<tr><th>Período</th><th>Altura</th><th>Direção</th><th>Agitação</th><th>Vento</th></tr>
+<tr><td>Manhã</td><td>{{printf "%.1f" .Wave.Morning.Height}}m</td><td>{{.Wave.Morning.Direction}}</td><td>{{.Wave.Morning.Agitation}}</td><td>{{printf "%.1f" .Wave.Morning.WindSpeed}} km/h {{.Wave.Morning.WindDir}}</td></tr>
+<tr><td>Tarde</td><td>{{printf "%.1f" .Wave.Afternoon.Height}}m</td><td>{{.Wave.Afternoon.Direction}}</td><td>{{.Wave.Afternoon.Agitation}}</td><td>{{printf "%.1f" .Wave.Afternoon.WindSpeed}} km/h {{.Wave.Afternoon.WindDir}}</td></tr>
+<tr><td>Noite</td><td>{{printf "%.1f" .Wave.Night.Height}}m</td><td>{{.Wave.Night.Direction}}</td><td>{{.Wave.Night.Agitation}}</td><td>{{printf "%.1f" .Wave.Night.WindSpeed}} km/h {{.Wave.Night.WindDir}}</td></tr>
+</table>
+{{- end}}
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+func newEmailTemplateData(notification *entity.Notification) emailTemplateData {
+	data := emailTemplateData{
+		Message: notification.Message,
+	}
+
+	for _, location := range notification.Locations() {
+		data.Locations = append(data.Locations, emailLocation{
+			Title:     location.Title(),
+			Forecasts: location.Content.Upcoming(),
+		})
+	}
+
+	return data
+}
+
+func (d emailTemplateData) Subject() string {
+	titles := make([]string, 0, len(d.Locations))
+	for _, location := range d.Locations {
+		titles = append(titles, location.Title)
+	}
+	return strings.Join(titles, ", ")
+}
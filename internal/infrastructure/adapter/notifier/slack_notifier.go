@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+)
+
+type SlackNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{
+		webhookURL: webhookURL,
+		client:     &http.Client{},
+	}
+}
+
+func (n *SlackNotifier) Channel() entity.Channel {
+	return entity.ChannelSlack
+}
+
+func (n *SlackNotifier) Send(ctx context.Context, notification *entity.Notification, address string) error {
+	webhookURL := n.webhookURL
+	if address != "" {
+		webhookURL = address
+	}
+	if webhookURL == "" {
+		return handler.ErrEmptyChannelAddress
+	}
+
+	payload := map[string]interface{}{
+		"text": notification.FormatNotificationContent(),
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar notificação: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("erro ao criar request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao enviar notificação slack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &handler.StatusCodeError{Message: "erro ao enviar notificação slack", StatusCode: resp.StatusCode}
+	}
+
+	return nil
+}
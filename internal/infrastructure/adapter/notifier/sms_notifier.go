@@ -0,0 +1,68 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+)
+
+type SMSNotifier struct {
+	gatewayURL string
+	authToken  string
+	client     *http.Client
+}
+
+func NewSMSNotifier(gatewayURL, authToken string) *SMSNotifier {
+	return &SMSNotifier{
+		gatewayURL: gatewayURL,
+		authToken:  authToken,
+		client:     &http.Client{},
+	}
+}
+
+func (n *SMSNotifier) Channel() entity.Channel {
+	return entity.ChannelSMS
+}
+
+func (n *SMSNotifier) Send(ctx context.Context, notification *entity.Notification, address string) error {
+	if address == "" {
+		return handler.ErrEmptyChannelAddress
+	}
+
+	payload := map[string]interface{}{
+		"to":      address,
+		"message": notification.FormatNotificationContent(),
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar notificação: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.gatewayURL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("erro ao criar request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if n.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+n.authToken)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao enviar SMS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &handler.StatusCodeError{Message: "erro ao enviar SMS", StatusCode: resp.StatusCode}
+	}
+
+	return nil
+}
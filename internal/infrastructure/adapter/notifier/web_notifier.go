@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
 )
 
 type WebNotifier struct {
@@ -26,27 +27,47 @@ func NewWebNotifier(webhookURL string) *WebNotifier {
 	}
 }
 
-func (n *WebNotifier) Send(ctx context.Context, notification *entity.Notification) error {
+func (n *WebNotifier) Channel() entity.Channel {
+	return entity.ChannelWebhook
+}
+
+// Send publica a notificação no webhook do usuário ou, sem endereço, no
+// WEBHOOK_URL configurado. O API_TOKEN só acompanha as requisições para o
+// webhook configurado, já que é a credencial de acesso à própria API.
+func (n *WebNotifier) Send(ctx context.Context, notification *entity.Notification, address string) error {
+	webhookURL := n.webhookURL
+	if address != "" {
+		webhookURL = address
+	}
+
 	payload := map[string]interface{}{
 		"id":        notification.ID,
 		"user_id":   notification.UserID,
 		"content":   notification.Content,
+		"message":   notification.Message,
 		"timestamp": notification.CreatedAt,
 	}
+	if notification.LocationLabel != "" {
+		payload["location_label"] = notification.LocationLabel
+	}
+	if len(notification.AdditionalLocations) > 0 {
+		payload["additional_locations"] = notification.AdditionalLocations
+	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("erro ao serializar notificação: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", n.webhookURL, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("erro ao criar request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", entity.DeliveryKey(notification.ID, entity.ChannelWebhook))
 
-	if n.authToken != "" {
+	if n.authToken != "" && webhookURL == n.webhookURL {
 		req.Header.Set("Authorization", "Bearer "+n.authToken)
 	}
 
@@ -57,7 +78,7 @@ func (n *WebNotifier) Send(ctx context.Context, notification *entity.Notificatio
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("erro ao enviar notificação: status %d", resp.StatusCode)
+		return &handler.StatusCodeError{Message: "erro ao enviar notificação", StatusCode: resp.StatusCode}
 	}
 
 	return nil
@@ -0,0 +1,70 @@
+package notifier_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/infrastructure/adapter/notifier"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebNotifier_IdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notification := &entity.Notification{ID: uuid.New()}
+	webNotifier := notifier.NewWebNotifier(server.URL)
+
+	assert.NoError(t, webNotifier.Send(context.Background(), notification, ""))
+	assert.NoError(t, webNotifier.Send(context.Background(), notification, ""))
+
+	expected := entity.DeliveryKey(notification.ID, entity.ChannelWebhook)
+	assert.Equal(t, []string{expected, expected}, keys)
+}
+
+func TestWebNotifier_StatusCodeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	webNotifier := notifier.NewWebNotifier(server.URL)
+
+	err := webNotifier.Send(context.Background(), &entity.Notification{ID: uuid.New()}, "")
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, handler.StatusCode(err))
+}
+
+func TestWebNotifier_AuthorizationOnlyForConfiguredWebhook(t *testing.T) {
+	t.Setenv("API_TOKEN", "segredo")
+
+	var authorization []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	userServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer userServer.Close()
+
+	webNotifier := notifier.NewWebNotifier(server.URL)
+	notification := &entity.Notification{ID: uuid.New()}
+
+	assert.NoError(t, webNotifier.Send(context.Background(), notification, ""))
+	assert.NoError(t, webNotifier.Send(context.Background(), notification, userServer.URL))
+
+	assert.Equal(t, []string{"Bearer segredo", ""}, authorization)
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type alertRuleRepository struct {
+	db *sql.DB
+}
+
+func NewAlertRuleRepository(db *sql.DB) repository.AlertRuleRepository {
+	return &alertRuleRepository{
+		db: db,
+	}
+}
+
+func (r *alertRuleRepository) Create(ctx context.Context, rule *entity.AlertRule) error {
+	query := `
+        INSERT INTO alert_rules (
+            id, user_id, location_id, metric, operator, threshold,
+            conditions, active, created_at, updated_at
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    `
+
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
+		rule.ID,
+		rule.UserID,
+		rule.LocationID,
+		rule.Metric,
+		rule.Operator,
+		rule.Threshold,
+		conditions,
+		rule.Active,
+		rule.CreatedAt,
+		rule.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *alertRuleRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]*entity.AlertRule, error) {
+	query := `
+        SELECT id, user_id, location_id, metric, operator, threshold,
+               conditions, active, created_at, updated_at
+        FROM alert_rules
+        WHERE user_id = $1
+        ORDER BY created_at
+    `
+
+	return r.queryRules(ctx, query, userID)
+}
+
+func (r *alertRuleRepository) FindActive(ctx context.Context) ([]*entity.AlertRule, error) {
+	query := `
+        SELECT id, user_id, location_id, metric, operator, threshold,
+               conditions, active, created_at, updated_at
+        FROM alert_rules
+        WHERE active = true
+        ORDER BY location_id
+    `
+
+	return r.queryRules(ctx, query)
+}
+
+func (r *alertRuleRepository) queryRules(ctx context.Context, query string, args ...interface{}) ([]*entity.AlertRule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*entity.AlertRule
+	for rows.Next() {
+		rule := &entity.AlertRule{}
+		var conditions []byte
+
+		err := rows.Scan(
+			&rule.ID,
+			&rule.UserID,
+			&rule.LocationID,
+			&rule.Metric,
+			&rule.Operator,
+			&rule.Threshold,
+			&conditions,
+			&rule.Active,
+			&rule.CreatedAt,
+			&rule.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(conditions, &rule.Conditions); err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (r *alertRuleRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	query := `
+        DELETE FROM alert_rules
+        WHERE id = $1 AND user_id = $2
+    `
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return handler.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *alertRuleRepository) RegisterTrigger(ctx context.Context, ruleID uuid.UUID, forecastDate time.Time) (bool, error) {
+	query := `
+        INSERT INTO alert_triggers (rule_id, forecast_date, created_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT (rule_id, forecast_date) DO NOTHING
+    `
+
+	result, err := r.db.ExecContext(ctx, query, ruleID, forecastDate.Format("2006-01-02"))
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *alertRuleRepository) DeleteTrigger(ctx context.Context, ruleID uuid.UUID, forecastDate time.Time) error {
+	query := `
+        DELETE FROM alert_triggers
+        WHERE rule_id = $1 AND forecast_date = $2
+    `
+
+	_, err := r.db.ExecContext(ctx, query, ruleID, forecastDate.Format("2006-01-02"))
+	return err
+}
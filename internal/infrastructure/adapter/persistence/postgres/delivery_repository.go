@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type deliveryRepository struct {
+	db *sql.DB
+}
+
+func NewDeliveryRepository(db *sql.DB) repository.DeliveryRepository {
+	return &deliveryRepository{
+		db: db,
+	}
+}
+
+func (r *deliveryRepository) Create(ctx context.Context, delivery *entity.DeliveryResult) error {
+	query := `
+        INSERT INTO notification_deliveries (id, notification_id, channel, address, status, error, http_status, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, 0), $8)
+    `
+
+	_, err := r.db.ExecContext(ctx, query,
+		delivery.ID,
+		delivery.NotificationID,
+		delivery.Channel,
+		delivery.Address,
+		delivery.Status,
+		delivery.Error,
+		delivery.HTTPStatus,
+		delivery.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *deliveryRepository) FindByNotification(ctx context.Context, notificationID uuid.UUID) ([]entity.DeliveryResult, error) {
+	query := `
+        SELECT id, notification_id, channel, COALESCE(address, ''), status, COALESCE(error, ''),
+               COALESCE(http_status, 0), created_at
+        FROM notification_deliveries
+        WHERE notification_id = $1
+        ORDER BY created_at
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, notificationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []entity.DeliveryResult
+	for rows.Next() {
+		delivery := entity.DeliveryResult{}
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.NotificationID,
+			&delivery.Channel,
+			&delivery.Address,
+			&delivery.Status,
+			&delivery.Error,
+			&delivery.HTTPStatus,
+			&delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
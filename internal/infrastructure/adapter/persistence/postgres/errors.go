@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/lib/pq"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// translateError converte violações de unicidade do PostgreSQL em
+// ErrDuplicateKey e referências a registros inexistentes em ErrNotFound,
+// mantendo o nome da restrição violada.
+func translateError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code {
+	case uniqueViolation:
+		return fmt.Errorf("%w: %s", handler.ErrDuplicateKey, pqErr.Constraint)
+	case foreignKeyViolation:
+		return fmt.Errorf("%w: %s", handler.ErrNotFound, pqErr.Constraint)
+	default:
+		return err
+	}
+}
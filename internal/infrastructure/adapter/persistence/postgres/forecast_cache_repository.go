@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/domain/service"
+)
+
+type forecastCacheRepository struct {
+	db  *sql.DB
+	ttl time.Duration
+}
+
+func NewForecastCacheRepository(db *sql.DB, ttl time.Duration) service.ForecastCache {
+	return &forecastCacheRepository{
+		db:  db,
+		ttl: ttl,
+	}
+}
+
+func (r *forecastCacheRepository) Get(ctx context.Context, cptecCode int, horizon entity.ForecastHorizon) (*entity.WeatherForecastCollection, bool) {
+	query := `
+        SELECT content
+        FROM forecast_cache
+        WHERE cptec_id = $1 AND horizon = $2 AND expires_at > NOW()
+    `
+
+	var content []byte
+	if err := r.db.QueryRowContext(ctx, query, cptecCode, horizon).Scan(&content); err != nil {
+		return nil, false
+	}
+
+	forecast := &entity.WeatherForecastCollection{}
+	if err := json.Unmarshal(content, forecast); err != nil {
+		return nil, false
+	}
+
+	return forecast, true
+}
+
+func (r *forecastCacheRepository) Set(ctx context.Context, cptecCode int, forecast *entity.WeatherForecastCollection) error {
+	query := `
+        INSERT INTO forecast_cache (cptec_id, horizon, content, issued_at, expires_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, NOW())
+        ON CONFLICT (cptec_id, horizon) DO UPDATE
+        SET content = EXCLUDED.content,
+            issued_at = EXCLUDED.issued_at,
+            expires_at = EXCLUDED.expires_at,
+            updated_at = NOW()
+        WHERE forecast_cache.expires_at <= NOW()
+           OR forecast_cache.issued_at IS NULL
+           OR forecast_cache.issued_at <= EXCLUDED.issued_at
+    `
+
+	content, err := json.Marshal(forecast)
+	if err != nil {
+		return err
+	}
+
+	var issuedAt *time.Time
+	if !forecast.IssuedAt.IsZero() {
+		issuedAt = &forecast.IssuedAt
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
+		cptecCode,
+		forecast.Horizon,
+		content,
+		issuedAt,
+		forecast.ExpiresAt(time.Now(), r.ttl),
+	)
+
+	return err
+}
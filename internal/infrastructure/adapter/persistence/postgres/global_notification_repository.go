@@ -9,8 +9,11 @@ import (
 	"weather-notification/internal/domain/repository"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const globalNotificationColumns = `id, time_of_day, frequency, active, last_execution, created_at`
+
 type globalNotificationRepository struct {
 	db *sql.DB
 }
@@ -40,7 +43,7 @@ func (r *globalNotificationRepository) Create(ctx context.Context, notification
 
 func (r *globalNotificationRepository) FindActive(ctx context.Context) ([]*entity.GlobalNotification, error) {
 	query := `
-        SELECT id, time_of_day, frequency, active, last_execution, created_at
+        SELECT ` + globalNotificationColumns + `
         FROM global_notifications
         WHERE active = true
     `
@@ -53,32 +56,144 @@ func (r *globalNotificationRepository) FindActive(ctx context.Context) ([]*entit
 
 	var notifications []*entity.GlobalNotification
 	for rows.Next() {
-		n := &entity.GlobalNotification{}
-		err := rows.Scan(
-			&n.ID,
-			&n.TimeOfDay,
-			&n.Frequency,
-			&n.Active,
-			&n.LastExecution,
-			&n.CreatedAt,
-		)
+		n, err := scanGlobalNotification(rows)
 		if err != nil {
 			return nil, err
 		}
 		notifications = append(notifications, n)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadExecutions(ctx, notifications); err != nil {
+		return nil, err
+	}
 
 	return notifications, nil
 }
 
-func (r *globalNotificationRepository) UpdateLastExecution(ctx context.Context, id uuid.UUID, executionTime time.Time) error {
+var globalNotificationPageQuery = pageQuery[*entity.GlobalNotification]{
+	table:       "global_notifications",
+	columns:     globalNotificationColumns,
+	defaultSort: "created_at",
+	sorts: map[string]sortColumn[*entity.GlobalNotification]{
+		"created_at":  {"created_at", func(n *entity.GlobalNotification) string { return n.CreatedAt.Format(time.RFC3339Nano) }},
+		"time_of_day": {"time_of_day", func(n *entity.GlobalNotification) string { return n.TimeOfDay.Format("15:04:05") }},
+	},
+	id:   func(n *entity.GlobalNotification) uuid.UUID { return n.ID },
+	scan: scanGlobalNotification,
+}
+
+func (r *globalNotificationRepository) List(ctx context.Context, filter entity.GlobalNotificationFilter, page entity.PageRequest) (*entity.Page[*entity.GlobalNotification], error) {
+	var q listQuery
+	if filter.Active != nil {
+		q.where("active = ?", *filter.Active)
+	}
+	if filter.Frequency != "" {
+		q.where("frequency = ?", filter.Frequency)
+	}
+
+	result, err := paginate(ctx, r.db, globalNotificationPageQuery, q, page)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadExecutions(ctx, result.Items); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func scanGlobalNotification(row rowScanner) (*entity.GlobalNotification, error) {
+	n := &entity.GlobalNotification{}
+	err := row.Scan(
+		&n.ID,
+		&n.TimeOfDay,
+		&n.Frequency,
+		&n.Active,
+		&n.LastExecution,
+		&n.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (r *globalNotificationRepository) loadExecutions(ctx context.Context, notifications []*entity.GlobalNotification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*entity.GlobalNotification, len(notifications))
+	ids := make([]string, 0, len(notifications))
+	for _, n := range notifications {
+		n.Executions = make(map[string]time.Time)
+		byID[n.ID] = n
+		ids = append(ids, n.ID.String())
+	}
+
+	query := `
+        SELECT global_notification_id, timezone, last_execution
+        FROM global_notification_executions
+        WHERE global_notification_id = ANY($1)
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id            uuid.UUID
+			timezone      string
+			lastExecution time.Time
+		)
+		if err := rows.Scan(&id, &timezone, &lastExecution); err != nil {
+			return err
+		}
+		if n, ok := byID[id]; ok {
+			n.Executions[timezone] = lastExecution.UTC()
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *globalNotificationRepository) RecordExecution(ctx context.Context, lease *entity.Lease, id uuid.UUID, timezone string, executionTime time.Time, runs []*entity.GlobalNotificationRun) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// O bloqueio compartilhado impede que outra instância assuma a
+	// concessão antes do commit desta execução
+	var held int
+	err = tx.QueryRowContext(ctx, `
+        SELECT 1
+        FROM scheduler_leases
+        WHERE name = $1 AND holder_id = $2 AND expires_at > NOW()
+        FOR SHARE
+    `, lease.Name, lease.HolderID).Scan(&held)
+	if err == sql.ErrNoRows {
+		return handler.ErrLeaseLost
+	}
+	if err != nil {
+		return err
+	}
+
 	query := `
         UPDATE global_notifications
         SET last_execution = $1
         WHERE id = $2
     `
 
-	result, err := r.db.ExecContext(ctx, query, executionTime, id)
+	result, err := tx.ExecContext(ctx, query, executionTime.UTC(), id)
 	if err != nil {
 		return err
 	}
@@ -92,5 +207,78 @@ func (r *globalNotificationRepository) UpdateLastExecution(ctx context.Context,
 		return handler.ErrNotFound
 	}
 
-	return nil
+	query = `
+        INSERT INTO global_notification_executions (global_notification_id, timezone, last_execution)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (global_notification_id, timezone)
+        DO UPDATE SET last_execution = EXCLUDED.last_execution
+    `
+
+	if _, err := tx.ExecContext(ctx, query, id, timezone, executionTime.UTC()); err != nil {
+		return err
+	}
+
+	for _, run := range runs {
+		if err := recordRun(ctx, tx, run); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func recordRun(ctx context.Context, tx *sql.Tx, run *entity.GlobalNotificationRun) error {
+	query := `
+        INSERT INTO global_notification_runs (
+            id, global_notification_id, timezone, scheduled_for, status, reason, created_at
+        )
+        VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+    `
+
+	_, err := tx.ExecContext(ctx, query,
+		run.ID,
+		run.GlobalNotificationID,
+		run.Timezone,
+		run.ScheduledFor,
+		run.Status,
+		run.Reason,
+		run.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *globalNotificationRepository) FindRuns(ctx context.Context, id uuid.UUID) ([]*entity.GlobalNotificationRun, error) {
+	query := `
+        SELECT id, global_notification_id, timezone, scheduled_for, status, COALESCE(reason, ''), created_at
+        FROM global_notification_runs
+        WHERE global_notification_id = $1
+        ORDER BY scheduled_for DESC
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*entity.GlobalNotificationRun
+	for rows.Next() {
+		run := &entity.GlobalNotificationRun{}
+		err := rows.Scan(
+			&run.ID,
+			&run.GlobalNotificationID,
+			&run.Timezone,
+			&run.ScheduledFor,
+			&run.Status,
+			&run.Reason,
+			&run.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
 }
@@ -0,0 +1,71 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/infrastructure/adapter/persistence/postgres"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var schedulerLease = &entity.Lease{Name: "global-scheduler", HolderID: "instancia-a"}
+
+func TestGlobalNotificationRepository_RecordExecution(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("erro criando mock do db: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewGlobalNotificationRepository(db)
+	id := uuid.New()
+	executedAt := time.Date(2024, 2, 2, 10, 1, 0, 0, time.UTC)
+	skipped := entity.NewGlobalNotificationRun(id, "America/Sao_Paulo", executedAt.AddDate(0, 0, -1), entity.RunSkipped, "execução perdida")
+	executed := entity.NewGlobalNotificationRun(id, "America/Sao_Paulo", executedAt.Add(-time.Minute), entity.RunExecuted, "")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM scheduler_leases\s+WHERE name = \$1 AND holder_id = \$2 AND expires_at > NOW\(\)\s+FOR SHARE`).
+		WithArgs("global-scheduler", "instancia-a").
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+	mock.ExpectExec(`UPDATE global_notifications`).
+		WithArgs(executedAt, id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO global_notification_executions`).
+		WithArgs(id, "America/Sao_Paulo", executedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	for _, run := range []*entity.GlobalNotificationRun{skipped, executed} {
+		mock.ExpectExec(`INSERT INTO global_notification_runs`).
+			WithArgs(run.ID, id, "America/Sao_Paulo", run.ScheduledFor, run.Status, run.Reason, run.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectCommit()
+
+	err = repo.RecordExecution(context.Background(), schedulerLease, id, "America/Sao_Paulo", executedAt, []*entity.GlobalNotificationRun{skipped, executed})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGlobalNotificationRepository_RecordExecutionWithoutLease(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("erro criando mock do db: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewGlobalNotificationRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM scheduler_leases`).
+		WithArgs("global-scheduler", "instancia-a").
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}))
+	mock.ExpectRollback()
+
+	err = repo.RecordExecution(context.Background(), schedulerLease, uuid.New(), "America/Sao_Paulo", time.Now(), nil)
+	assert.ErrorIs(t, err, handler.ErrLeaseLost)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
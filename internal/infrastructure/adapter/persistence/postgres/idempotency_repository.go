@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/repository"
+)
+
+// DefaultClaimLease é o prazo após o qual a posse de um envio interrompido,
+// por exemplo pela queda do processo, pode ser reivindicada novamente.
+const DefaultClaimLease = 5 * time.Minute
+
+type idempotencyRepository struct {
+	db    *sql.DB
+	lease time.Duration
+}
+
+func NewIdempotencyRepository(db *sql.DB, lease time.Duration) repository.IdempotencyRepository {
+	if lease <= 0 {
+		lease = DefaultClaimLease
+	}
+
+	return &idempotencyRepository{
+		db:    db,
+		lease: lease,
+	}
+}
+
+// Claim insere o registro ou, se ele já existe, só o reivindica quando ainda
+// não foi enviado e nenhuma outra tentativa detém a posse dentro do prazo.
+// O WHERE do ON CONFLICT é avaliado com a linha bloqueada, então entre
+// tentativas concorrentes apenas uma recebe o registro de volta.
+func (r *idempotencyRepository) Claim(ctx context.Context, record *entity.DeliveryIdempotency) (*entity.DeliveryIdempotency, error) {
+	query := `
+        INSERT INTO delivery_idempotency (key, notification_id, channel, status, attempts, created_at, claimed_at)
+        VALUES ($1, $2, $3, $4, 1, $5, $5)
+        ON CONFLICT (key) DO UPDATE
+        SET attempts = delivery_idempotency.attempts + 1, claimed_at = EXCLUDED.claimed_at
+        WHERE delivery_idempotency.status = $4
+          AND (delivery_idempotency.claimed_at IS NULL OR delivery_idempotency.claimed_at < $6)
+        RETURNING key, notification_id, channel, status, attempts, created_at, claimed_at, sent_at
+    `
+
+	claimedAt := record.CreatedAt
+	stored, err := scanIdempotency(r.db.QueryRowContext(ctx, query,
+		record.Key,
+		record.NotificationID,
+		record.Channel,
+		entity.StatusPending,
+		claimedAt,
+		claimedAt.Add(-r.lease),
+	))
+	if !errors.Is(err, sql.ErrNoRows) {
+		return stored, err
+	}
+
+	// Nenhuma linha reivindicada: o envio já foi concluído ou está em
+	// andamento por outro processamento
+	stored, err = r.find(ctx, record.Key)
+	if err != nil {
+		return nil, err
+	}
+	if !stored.AlreadySent() {
+		return nil, handler.ErrDeliveryInProgress
+	}
+
+	return stored, nil
+}
+
+func (r *idempotencyRepository) find(ctx context.Context, key string) (*entity.DeliveryIdempotency, error) {
+	query := `
+        SELECT key, notification_id, channel, status, attempts, created_at, claimed_at, sent_at
+        FROM delivery_idempotency
+        WHERE key = $1
+    `
+
+	stored, err := scanIdempotency(r.db.QueryRowContext(ctx, query, key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, handler.ErrNotFound
+	}
+	return stored, err
+}
+
+func scanIdempotency(row rowScanner) (*entity.DeliveryIdempotency, error) {
+	stored := &entity.DeliveryIdempotency{}
+	err := row.Scan(
+		&stored.Key,
+		&stored.NotificationID,
+		&stored.Channel,
+		&stored.Status,
+		&stored.Attempts,
+		&stored.CreatedAt,
+		&stored.ClaimedAt,
+		&stored.SentAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+func (r *idempotencyRepository) MarkSent(ctx context.Context, key string) error {
+	query := `
+        UPDATE delivery_idempotency
+        SET status = $1, sent_at = NOW(), claimed_at = NULL
+        WHERE key = $2
+    `
+
+	return r.exec(ctx, query, entity.StatusSent, key)
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, key string) error {
+	query := `
+        UPDATE delivery_idempotency
+        SET claimed_at = NULL
+        WHERE key = $1 AND status = $2
+    `
+
+	return r.exec(ctx, query, key, entity.StatusPending)
+}
+
+func (r *idempotencyRepository) exec(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return handler.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/infrastructure/adapter/persistence/postgres"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var idempotencyColumns = []string{"key", "notification_id", "channel", "status", "attempts", "created_at", "claimed_at", "sent_at"}
+
+func TestIdempotencyRepository_Claim(t *testing.T) {
+	record := entity.NewDeliveryIdempotency(uuid.New(), entity.ChannelWebhook)
+	sentAt := time.Now()
+	lease := time.Minute
+
+	tests := []struct {
+		name        string
+		claimed     bool
+		status      entity.NotificationStatus
+		attempts    int
+		sentAt      *time.Time
+		sent        bool
+		expectError error
+	}{
+		{name: "primeira tentativa", claimed: true, status: entity.StatusPending, attempts: 1},
+		{name: "tentativa anterior interrompida", claimed: true, status: entity.StatusPending, attempts: 2},
+		{name: "envio já concluído", status: entity.StatusSent, attempts: 2, sentAt: &sentAt, sent: true},
+		{name: "envio em andamento por outra tentativa", status: entity.StatusPending, attempts: 1, expectError: handler.ErrDeliveryInProgress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, sqlMock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			row := sqlmock.NewRows(idempotencyColumns).
+				AddRow(record.Key, record.NotificationID, record.Channel, tt.status, tt.attempts, record.CreatedAt, nil, tt.sentAt)
+			claim := sqlMock.ExpectQuery("INSERT INTO delivery_idempotency").
+				WithArgs(record.Key, record.NotificationID, record.Channel, entity.StatusPending, record.CreatedAt, record.CreatedAt.Add(-lease))
+			if tt.claimed {
+				claim.WillReturnRows(row)
+			} else {
+				// O WHERE do ON CONFLICT não reivindicou a linha
+				claim.WillReturnRows(sqlmock.NewRows(idempotencyColumns))
+				sqlMock.ExpectQuery("SELECT (.+) FROM delivery_idempotency").
+					WithArgs(record.Key).
+					WillReturnRows(row)
+			}
+
+			repo := postgres.NewIdempotencyRepository(db, lease)
+			stored, err := repo.Claim(context.Background(), record)
+
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, stored)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.sent, stored.AlreadySent())
+				assert.Equal(t, tt.attempts, stored.Attempts)
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIdempotencyRepository_Release(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	key := entity.DeliveryKey(uuid.New(), entity.ChannelEmail)
+	sqlMock.ExpectExec("UPDATE delivery_idempotency SET claimed_at = NULL").
+		WithArgs(key, entity.StatusPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := postgres.NewIdempotencyRepository(db, time.Minute)
+	assert.NoError(t, repo.Release(context.Background(), key))
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/repository"
+)
+
+type leaseRepository struct {
+	db *sql.DB
+}
+
+func NewLeaseRepository(db *sql.DB) repository.LeaseRepository {
+	return &leaseRepository{
+		db: db,
+	}
+}
+
+func (r *leaseRepository) TryAcquire(ctx context.Context, name, holderID string, ttl time.Duration) (*entity.Lease, error) {
+	query := `
+        INSERT INTO scheduler_leases (name, holder_id, acquired_at, renewed_at, expires_at)
+        VALUES ($1, $2, NOW(), NOW(), NOW() + make_interval(secs => $3))
+        ON CONFLICT (name) DO UPDATE SET
+            holder_id = EXCLUDED.holder_id,
+            acquired_at = CASE
+                WHEN scheduler_leases.holder_id = EXCLUDED.holder_id THEN scheduler_leases.acquired_at
+                ELSE NOW()
+            END,
+            renewed_at = NOW(),
+            expires_at = EXCLUDED.expires_at
+        WHERE scheduler_leases.holder_id = EXCLUDED.holder_id
+           OR scheduler_leases.expires_at < NOW()
+        RETURNING name, holder_id, acquired_at, renewed_at, expires_at
+    `
+
+	lease := &entity.Lease{}
+	err := r.db.QueryRowContext(ctx, query, name, holderID, ttl.Seconds()).Scan(
+		&lease.Name,
+		&lease.HolderID,
+		&lease.AcquiredAt,
+		&lease.RenewedAt,
+		&lease.ExpiresAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+func (r *leaseRepository) Find(ctx context.Context, name string) (*entity.Lease, error) {
+	query := `
+        SELECT name, holder_id, acquired_at, renewed_at, expires_at
+        FROM scheduler_leases
+        WHERE name = $1
+    `
+
+	lease := &entity.Lease{}
+	err := r.db.QueryRowContext(ctx, query, name).Scan(
+		&lease.Name,
+		&lease.HolderID,
+		&lease.AcquiredAt,
+		&lease.RenewedAt,
+		&lease.ExpiresAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, handler.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+func (r *leaseRepository) Release(ctx context.Context, name, holderID string) error {
+	query := `
+        UPDATE scheduler_leases
+        SET expires_at = NOW()
+        WHERE name = $1 AND holder_id = $2
+    `
+
+	_, err := r.db.ExecContext(ctx, query, name, holderID)
+	return err
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"weather-notification/internal/domain/entity"
 	handler "weather-notification/internal/domain/error_handler"
 	"weather-notification/internal/domain/repository"
@@ -10,6 +11,8 @@ import (
 	"github.com/google/uuid"
 )
 
+const locationColumns = "id, cptec_id, name, state, timezone, latitude, longitude"
+
 type locationRepository struct {
 	db *sql.DB
 }
@@ -22,15 +25,19 @@ func NewLocationRepository(db *sql.DB) repository.LocationRepository {
 
 func (r *locationRepository) Create(ctx context.Context, location *entity.Location) error {
 	query := `
-        INSERT INTO locations (id, cptec_id, name, state)
-        VALUES ($1, $2, $3, $4)
+        INSERT INTO locations (id, cptec_id, name, state, timezone, latitude, longitude)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
     `
 
+	latitude, longitude := coordinateArgs(location.Coordinates)
 	_, err := r.db.ExecContext(ctx, query,
 		location.ID,
 		location.CPTECCode,
 		location.Name,
 		location.State,
+		location.Timezone,
+		latitude,
+		longitude,
 	)
 
 	if err != nil {
@@ -42,19 +49,12 @@ func (r *locationRepository) Create(ctx context.Context, location *entity.Locati
 
 func (r *locationRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Location, error) {
 	query := `
-        SELECT id, cptec_id, name, state
+        SELECT ` + locationColumns + `
         FROM locations
         WHERE id = $1
     `
 
-	location := &entity.Location{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&location.ID,
-		&location.CPTECCode,
-		&location.Name,
-		&location.State,
-	)
-
+	location, err := scanLocation(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, handler.ErrNotFound
 	}
@@ -62,24 +62,17 @@ func (r *locationRepository) FindByID(ctx context.Context, id uuid.UUID) (*entit
 		return nil, err
 	}
 
-	return location, nil
+	return &location, nil
 }
 
 func (r *locationRepository) FindByCPTECCode(ctx context.Context, cptecCode int) (*entity.Location, error) {
 	query := `
-        SELECT id, cptec_id, name, state
+        SELECT ` + locationColumns + `
         FROM locations
         WHERE cptec_id = $1
     `
 
-	location := &entity.Location{}
-	err := r.db.QueryRowContext(ctx, query, cptecCode).Scan(
-		&location.ID,
-		&location.CPTECCode,
-		&location.Name,
-		&location.State,
-	)
-
+	location, err := scanLocation(r.db.QueryRowContext(ctx, query, cptecCode))
 	if err == sql.ErrNoRows {
 		return nil, handler.ErrNotFound
 	}
@@ -87,30 +80,165 @@ func (r *locationRepository) FindByCPTECCode(ctx context.Context, cptecCode int)
 		return nil, err
 	}
 
-	return location, nil
+	return &location, nil
 }
 
 func (r *locationRepository) FindByNameAndState(ctx context.Context, name, state string) (*entity.Location, error) {
 	query := `
-        SELECT id, cptec_id, name, state
+        SELECT ` + locationColumns + `
+        FROM locations
+        WHERE lower(name) = lower($1) AND state = $2
+    `
+
+	location, err := scanLocation(r.db.QueryRowContext(ctx, query, name, state))
+	if err == sql.ErrNoRows {
+		return nil, handler.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+// Search busca no catálogo local as cidades cujo nome contém o texto ou se
+// parece com ele, ignorando acentos e maiúsculas, da mais para a menos
+// semelhante.
+func (r *locationRepository) Search(ctx context.Context, name, state string, limit int) ([]entity.Location, error) {
+	query := `
+        SELECT ` + locationColumns + `
+        FROM locations
+        WHERE (f_unaccent(lower(name)) % f_unaccent(lower($1))
+            OR f_unaccent(lower(name)) LIKE '%' || f_unaccent(lower($4)) || '%' ESCAPE '\')
+          AND ($2 = '' OR state = $2)
+        ORDER BY similarity(f_unaccent(lower(name)), f_unaccent(lower($1))) DESC, name, state
+        LIMIT $3
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, name, state, limit, escapeLike(name))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanLocations(rows)
+}
+
+// Import grava o catálogo de cidades em uma única transação. Cidades já
+// cadastradas pelo código CPTEC têm nome, UF, fuso horário e coordenadas
+// atualizados e mantêm o ID, preservando as referências de usuários e
+// notificações.
+func (r *locationRepository) Import(ctx context.Context, locations []entity.Location) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+        INSERT INTO locations (id, cptec_id, name, state, timezone, latitude, longitude)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (cptec_id) DO UPDATE
+        SET name = EXCLUDED.name, state = EXCLUDED.state, timezone = EXCLUDED.timezone,
+            latitude = COALESCE(EXCLUDED.latitude, locations.latitude),
+            longitude = COALESCE(EXCLUDED.longitude, locations.longitude)
+    `)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	for _, location := range locations {
+		latitude, longitude := coordinateArgs(location.Coordinates)
+		if _, err := stmt.ExecContext(ctx,
+			location.ID,
+			location.CPTECCode,
+			location.Name,
+			location.State,
+			location.Timezone,
+			latitude,
+			longitude,
+		); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(locations), nil
+}
+
+// FindWithinBounds retorna as cidades com coordenadas dentro do retângulo.
+func (r *locationRepository) FindWithinBounds(ctx context.Context, bounds entity.CoordinateBounds) ([]entity.Location, error) {
+	query := `
+        SELECT ` + locationColumns + `
         FROM locations
-        WHERE name = $1 AND state = $2
+        WHERE latitude BETWEEN $1 AND $2 AND longitude BETWEEN $3 AND $4
     `
 
-	location := &entity.Location{}
-	err := r.db.QueryRowContext(ctx, query, name, state).Scan(
+	rows, err := r.db.QueryContext(ctx, query,
+		bounds.MinLatitude,
+		bounds.MaxLatitude,
+		bounds.MinLongitude,
+		bounds.MaxLongitude,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanLocations(rows)
+}
+
+func scanLocations(rows *sql.Rows) ([]entity.Location, error) {
+	locations := []entity.Location{}
+	for rows.Next() {
+		location, err := scanLocation(rows)
+		if err != nil {
+			return nil, err
+		}
+		locations = append(locations, location)
+	}
+
+	return locations, rows.Err()
+}
+
+func scanLocation(row rowScanner) (entity.Location, error) {
+	location := entity.Location{}
+	var latitude, longitude sql.NullFloat64
+	err := row.Scan(
 		&location.ID,
 		&location.CPTECCode,
 		&location.Name,
 		&location.State,
+		&location.Timezone,
+		&latitude,
+		&longitude,
 	)
-
-	if err == sql.ErrNoRows {
-		return nil, handler.ErrNotFound
-	}
 	if err != nil {
-		return nil, err
+		return location, err
+	}
+
+	if latitude.Valid && longitude.Valid {
+		location.Coordinates = &entity.Coordinates{Latitude: latitude.Float64, Longitude: longitude.Float64}
 	}
 
 	return location, nil
 }
+
+func coordinateArgs(coordinates *entity.Coordinates) (interface{}, interface{}) {
+	if coordinates == nil {
+		return nil, nil
+	}
+	return coordinates.Latitude, coordinates.Longitude
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike faz com que %, _ e \ no texto pesquisado sejam comparados
+// literalmente no LIKE.
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
@@ -0,0 +1,102 @@
+package postgres_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/infrastructure/adapter/persistence/postgres"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var locationColumns = []string{"id", "cptec_id", "name", "state", "timezone", "latitude", "longitude"}
+
+func TestLocationRepository_Search(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		pattern string
+	}{
+		{"nome com erro de digitação", "Campinsa", "Campinsa"},
+		{"curingas do LIKE são comparados literalmente", `50%_d\e`, `50\%\_d\\e`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("erro criando mock do db: %v", err)
+			}
+			defer db.Close()
+
+			repo := postgres.NewLocationRepository(db)
+			id := uuid.New()
+
+			mock.ExpectQuery(`f_unaccent\(lower\(name\)\) % f_unaccent\(lower\(\$1\)\)`).
+				WithArgs(tt.query, "SP", 10, tt.pattern).
+				WillReturnRows(sqlmock.NewRows(locationColumns).
+					AddRow(id, 1110, "Campinas", "SP", entity.DefaultTimezone, nil, nil))
+
+			locations, err := repo.Search(context.Background(), tt.query, "SP", 10)
+			assert.NoError(t, err)
+			assert.Len(t, locations, 1)
+			assert.Equal(t, id, locations[0].ID)
+			assert.Nil(t, locations[0].Coordinates)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLocationRepository_Import(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("erro criando mock do db: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewLocationRepository(db)
+	saoPaulo, _ := entity.NewLocation(244, "São Paulo", "SP")
+	ubatuba, _ := entity.NewLocation(5515, "Ubatuba", "SP")
+	ubatuba.Coordinates = &entity.Coordinates{Latitude: -23.4336, Longitude: -45.0838}
+
+	mock.ExpectBegin()
+	prepare := mock.ExpectPrepare(`ON CONFLICT \(cptec_id\) DO UPDATE`)
+	prepare.ExpectExec().
+		WithArgs(saoPaulo.ID, 244, "São Paulo", "SP", saoPaulo.Timezone, nil, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	prepare.ExpectExec().
+		WithArgs(ubatuba.ID, 5515, "Ubatuba", "SP", ubatuba.Timezone, -23.4336, -45.0838).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	imported, err := repo.Import(context.Background(), []entity.Location{*saoPaulo, *ubatuba})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, imported)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLocationRepository_FindWithinBounds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("erro criando mock do db: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewLocationRepository(db)
+	bounds := entity.Coordinates{Latitude: -23.45, Longitude: -45.07}.Bounds(50)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE latitude BETWEEN $1 AND $2 AND longitude BETWEEN $3 AND $4`)).
+		WithArgs(bounds.MinLatitude, bounds.MaxLatitude, bounds.MinLongitude, bounds.MaxLongitude).
+		WillReturnRows(sqlmock.NewRows(locationColumns).
+			AddRow(uuid.New(), 5515, "Ubatuba", "SP", entity.DefaultTimezone, -23.4336, -45.0838))
+
+	locations, err := repo.FindWithinBounds(context.Background(), bounds)
+	assert.NoError(t, err)
+	if assert.Len(t, locations, 1) {
+		assert.Equal(t, &entity.Coordinates{Latitude: -23.4336, Longitude: -45.0838}, locations[0].Coordinates)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,13 +4,20 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"time"
 	"weather-notification/internal/domain/entity"
 	handler "weather-notification/internal/domain/error_handler"
 	"weather-notification/internal/domain/repository"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const notificationColumns = `id, user_id, location_id, content, COALESCE(location_label, ''),
+               additional_locations, COALESCE(message, ''), status,
+               scheduled_for, sent_at, attempts, COALESCE(last_error, ''),
+               COALESCE(last_http_status, 0), last_attempt_at, created_at, updated_at`
+
 type notificationRepository struct {
 	db *sql.DB
 }
@@ -21,13 +28,77 @@ func NewNotificationRepository(db *sql.DB) repository.NotificationRepository {
 	}
 }
 
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanNotification(row rowScanner) (*entity.Notification, error) {
+	notification := &entity.Notification{}
+	var content, additionalLocations []byte
+
+	err := row.Scan(
+		&notification.ID,
+		&notification.UserID,
+		&notification.LocationID,
+		&content,
+		&notification.LocationLabel,
+		&additionalLocations,
+		&notification.Message,
+		&notification.Status,
+		&notification.ScheduledFor,
+		&notification.SentAt,
+		&notification.Attempts,
+		&notification.LastError,
+		&notification.LastHTTPStatus,
+		&notification.LastAttemptAt,
+		&notification.CreatedAt,
+		&notification.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, &notification.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(additionalLocations, &notification.AdditionalLocations)
+	if err != nil {
+		return nil, err
+	}
+
+	return notification, nil
+}
+
+func (r *notificationRepository) queryNotifications(ctx context.Context, query string, args ...interface{}) ([]*entity.Notification, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*entity.Notification
+
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
 func (r *notificationRepository) Create(ctx context.Context, notification *entity.Notification) error {
 	query := `
         INSERT INTO notifications (
-            id, user_id, location_id, content, status, 
-            scheduled_for, sent_at, created_at, updated_at
+            id, user_id, location_id, content, location_label, additional_locations,
+            message, status, scheduled_for, sent_at, created_at, updated_at
         )
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, NULLIF($7, ''), $8, $9, $10, $11, $12)
     `
 
 	content, err := json.Marshal(notification.Content)
@@ -35,44 +106,59 @@ func (r *notificationRepository) Create(ctx context.Context, notification *entit
 		return err
 	}
 
-	_, err = r.db.ExecContext(ctx, query,
+	additionalLocations := notification.AdditionalLocations
+	if additionalLocations == nil {
+		additionalLocations = []entity.LocationForecast{}
+	}
+	additional, err := json.Marshal(additionalLocations)
+	if err != nil {
+		return err
+	}
+
+	message, err := entity.NewNotificationOutboxMessage(notification)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, query,
 		notification.ID,
 		notification.UserID,
 		notification.LocationID,
 		content,
+		notification.LocationLabel,
+		additional,
+		notification.Message,
 		notification.Status,
 		notification.ScheduledFor,
 		notification.SentAt,
 		notification.CreatedAt,
 		notification.UpdatedAt,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if err := insertOutboxMessage(ctx, tx, message); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *notificationRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error) {
 	query := `
-        SELECT id, user_id, location_id, content, status, 
-               scheduled_for, sent_at, created_at, updated_at
+        SELECT ` + notificationColumns + `
         FROM notifications
         WHERE id = $1
     `
 
-	notification := &entity.Notification{}
-	var content []byte
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&notification.ID,
-		&notification.UserID,
-		&notification.LocationID,
-		&content,
-		&notification.Status,
-		&notification.ScheduledFor,
-		&notification.SentAt,
-		&notification.CreatedAt,
-		&notification.UpdatedAt,
-	)
-
+	notification, err := scanNotification(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, handler.ErrNotFound
 	}
@@ -80,180 +166,228 @@ func (r *notificationRepository) FindByID(ctx context.Context, id uuid.UUID) (*e
 		return nil, err
 	}
 
-	err = json.Unmarshal(content, &notification.Content)
-	if err != nil {
-		return nil, err
-	}
-
 	return notification, nil
 }
 
-func (r *notificationRepository) FindPendingNotifications(ctx context.Context) ([]*entity.Notification, error) {
+// FindRecoverableNotifications retorna, na ordem do agendamento, as
+// notificações PENDENTE, vencidas ou futuras, as em FALHA que ainda não
+// esgotaram maxAttempts tentativas e as EM_PROCESSAMENTO.
+func (r *notificationRepository) FindRecoverableNotifications(ctx context.Context, maxAttempts int) ([]*entity.Notification, error) {
 	query := `
-        SELECT id, user_id, location_id, content, status, 
-               scheduled_for, sent_at, created_at, updated_at
+        SELECT ` + notificationColumns + `
         FROM notifications
-        WHERE status = $1 AND scheduled_for <= NOW() AT TIME ZONE 'America/Sao_Paulo' 
+        WHERE status = $1
+           OR (status = $2 AND attempts < $3)
+           OR status = $4
         ORDER BY scheduled_for
     `
 
-	rows, err := r.db.QueryContext(ctx, query, entity.StatusPending)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	return r.queryNotifications(ctx, query, entity.StatusPending, entity.StatusFailed, maxAttempts, entity.StatusProcessing)
+}
 
-	var notifications []*entity.Notification
+func (r *notificationRepository) Transition(ctx context.Context, notification *entity.Notification, event *entity.NotificationEvent) error {
+	query := `
+        UPDATE notifications
+        SET status = $1, sent_at = $2, attempts = $3, last_error = NULLIF($4, ''),
+            last_http_status = NULLIF($5, 0), last_attempt_at = $6, updated_at = $7
+        WHERE id = $8 AND status = $9
+    `
 
-	for rows.Next() {
-		notification := &entity.Notification{}
-		var content []byte
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-		err := rows.Scan(
-			&notification.ID,
-			&notification.UserID,
-			&notification.LocationID,
-			&content,
-			&notification.Status,
-			&notification.ScheduledFor,
-			&notification.SentAt,
-			&notification.CreatedAt,
-			&notification.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
+	result, err := tx.ExecContext(ctx, query,
+		notification.Status,
+		notification.SentAt,
+		notification.Attempts,
+		notification.LastError,
+		notification.LastHTTPStatus,
+		notification.LastAttemptAt,
+		notification.UpdatedAt,
+		notification.ID,
+		event.FromStatus,
+	)
+	if err != nil {
+		return err
+	}
 
-		err = json.Unmarshal(content, &notification.Content)
-		if err != nil {
-			return nil, err
-		}
+	if err := requireTransition(result); err != nil {
+		return err
+	}
 
-		notifications = append(notifications, notification)
+	if err := insertNotificationEvent(ctx, tx, event); err != nil {
+		return err
 	}
 
-	return notifications, nil
+	return tx.Commit()
 }
 
-func (r *notificationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status entity.NotificationStatus) error {
+func (r *notificationRepository) Reschedule(ctx context.Context, notification *entity.Notification, event *entity.NotificationEvent) error {
 	query := `
-		UPDATE notifications
-		SET status = $1::text, 
-			sent_at = CASE 
-				WHEN $1::text = 'ENVIADA' THEN NOW() AT TIME ZONE 'America/Sao_Paulo' 
-				ELSE sent_at 
-			END,
-			updated_at = NOW() AT TIME ZONE 'America/Sao_Paulo'
-		WHERE id = $2
-	`
-
-	result, err := r.db.ExecContext(ctx, query, status, id)
+        UPDATE notifications
+        SET scheduled_for = $1, updated_at = $2
+        WHERE id = $3 AND status = $4
+    `
+
+	message, err := entity.NewNotificationOutboxMessage(notification)
 	if err != nil {
 		return err
 	}
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query,
+		notification.ScheduledFor,
+		notification.UpdatedAt,
+		notification.ID,
+		event.FromStatus,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := requireTransition(result); err != nil {
+		return err
+	}
+
+	if err := insertNotificationEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	if err := insertOutboxMessage(ctx, tx, message); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// requireTransition trata como conflito a atualização que não encontrou a
+// notificação no status esperado, pois outro processo a alterou antes.
+func requireTransition(result sql.Result) error {
 	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
 
 	if rows == 0 {
-		return handler.ErrNotFound
+		return handler.ErrInvalidStatusTransition
 	}
 
 	return nil
 }
 
-func (r *notificationRepository) FindByUserAndLocation(ctx context.Context, userID, locationID uuid.UUID) ([]*entity.Notification, error) {
+func insertNotificationEvent(ctx context.Context, tx *sql.Tx, event *entity.NotificationEvent) error {
 	query := `
-        SELECT id, user_id, location_id, content, status, 
-               scheduled_for, sent_at, created_at, updated_at
-        FROM notifications
-        WHERE user_id = $1 AND location_id = $2
-        ORDER BY scheduled_for DESC
+        INSERT INTO notification_events (
+            id, notification_id, from_status, to_status, actor, error, details, created_at
+        )
+        VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8)
+    `
+
+	_, err := tx.ExecContext(ctx, query,
+		event.ID,
+		event.NotificationID,
+		event.FromStatus,
+		event.ToStatus,
+		event.Actor,
+		event.Error,
+		event.Details,
+		event.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *notificationRepository) FindEvents(ctx context.Context, id uuid.UUID) ([]*entity.NotificationEvent, error) {
+	query := `
+        SELECT id, notification_id, from_status, to_status, actor,
+               COALESCE(error, ''), COALESCE(details, ''), created_at
+        FROM notification_events
+        WHERE notification_id = $1
+        ORDER BY created_at
     `
 
-	rows, err := r.db.QueryContext(ctx, query, userID, locationID)
+	rows, err := r.db.QueryContext(ctx, query, id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var notifications []*entity.Notification
-
+	var events []*entity.NotificationEvent
 	for rows.Next() {
-		notification := &entity.Notification{}
-		var content []byte
-
+		event := &entity.NotificationEvent{}
 		err := rows.Scan(
-			&notification.ID,
-			&notification.UserID,
-			&notification.LocationID,
-			&content,
-			&notification.Status,
-			&notification.ScheduledFor,
-			&notification.SentAt,
-			&notification.CreatedAt,
-			&notification.UpdatedAt,
+			&event.ID,
+			&event.NotificationID,
+			&event.FromStatus,
+			&event.ToStatus,
+			&event.Actor,
+			&event.Error,
+			&event.Details,
+			&event.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
-
-		err = json.Unmarshal(content, &notification.Content)
-		if err != nil {
-			return nil, err
-		}
-
-		notifications = append(notifications, notification)
+		events = append(events, event)
 	}
 
-	return notifications, nil
+	return events, nil
 }
 
-func (r *notificationRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Notification, error) {
+func (r *notificationRepository) FindByUserAndLocation(ctx context.Context, userID, locationID uuid.UUID) ([]*entity.Notification, error) {
 	query := `
-        SELECT id, user_id, location_id, content, status, 
-               scheduled_for, sent_at, created_at, updated_at
+        SELECT ` + notificationColumns + `
         FROM notifications
-        WHERE user_id = $1
-        ORDER BY created_at DESC
+        WHERE user_id = $1 AND location_id = $2
+        ORDER BY scheduled_for DESC
     `
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var notifications []*entity.Notification
-
-	for rows.Next() {
-		notification := &entity.Notification{}
-		var content []byte
+	return r.queryNotifications(ctx, query, userID, locationID)
+}
 
-		err := rows.Scan(
-			&notification.ID,
-			&notification.UserID,
-			&notification.LocationID,
-			&content,
-			&notification.Status,
-			&notification.ScheduledFor,
-			&notification.SentAt,
-			&notification.CreatedAt,
-			&notification.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
+var notificationPageQuery = pageQuery[*entity.Notification]{
+	table:       "notifications",
+	columns:     notificationColumns,
+	defaultSort: "-created_at",
+	sorts: map[string]sortColumn[*entity.Notification]{
+		"created_at":    {"created_at", func(n *entity.Notification) string { return n.CreatedAt.Format(time.RFC3339Nano) }},
+		"scheduled_for": {"scheduled_for", func(n *entity.Notification) string { return n.ScheduledFor.Format(time.RFC3339Nano) }},
+		"updated_at":    {"updated_at", func(n *entity.Notification) string { return n.UpdatedAt.Format(time.RFC3339Nano) }},
+		"status":        {"status", func(n *entity.Notification) string { return string(n.Status) }},
+	},
+	id:   func(n *entity.Notification) uuid.UUID { return n.ID },
+	scan: scanNotification,
+}
 
-		err = json.Unmarshal(content, &notification.Content)
-		if err != nil {
-			return nil, err
+func (r *notificationRepository) List(ctx context.Context, filter entity.NotificationFilter, page entity.PageRequest) (*entity.Page[*entity.Notification], error) {
+	var q listQuery
+	if filter.UserID != uuid.Nil {
+		q.where("user_id = ?", filter.UserID)
+	}
+	if len(filter.Status) > 0 {
+		statuses := make([]string, 0, len(filter.Status))
+		for _, status := range filter.Status {
+			statuses = append(statuses, string(status))
 		}
-
-		notifications = append(notifications, notification)
+		q.where("status = ANY(?)", pq.Array(statuses))
+	}
+	if filter.LocationID != nil {
+		q.where("location_id = ?", *filter.LocationID)
+	}
+	if filter.ScheduledFrom != nil {
+		q.where("scheduled_for >= ?", *filter.ScheduledFrom)
+	}
+	if filter.ScheduledBefore != nil {
+		q.where("scheduled_for < ?", *filter.ScheduledBefore)
 	}
 
-	return notifications, nil
+	return paginate(ctx, r.db, notificationPageQuery, q, page)
 }
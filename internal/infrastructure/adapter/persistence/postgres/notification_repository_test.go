@@ -0,0 +1,179 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/infrastructure/adapter/persistence/postgres"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationRepository_CreateWritesOutbox(t *testing.T) {
+	notification, err := entity.NewNotification(uuid.New(), uuid.New(), entity.WeatherForecastCollection{}, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		outboxErr   error
+		expectError bool
+	}{
+		{"grava notificação e outbox na mesma transação", nil, false},
+		{"falha no outbox desfaz a notificação", errors.New("falha no outbox"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("erro criando mock do db: %v", err)
+			}
+			defer db.Close()
+
+			repo := postgres.NewNotificationRepository(db)
+
+			mock.ExpectBegin()
+			mock.ExpectExec(`INSERT INTO notifications`).WillReturnResult(sqlmock.NewResult(1, 1))
+			outbox := mock.ExpectExec(`INSERT INTO outbox`).
+				WithArgs(sqlmock.AnyArg(), notification.ID, entity.EventNotificationScheduled, sqlmock.AnyArg(), 0, sqlmock.AnyArg())
+			if tt.outboxErr != nil {
+				outbox.WillReturnError(tt.outboxErr)
+				mock.ExpectRollback()
+			} else {
+				outbox.WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			}
+
+			err = repo.Create(context.Background(), notification)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNotificationRepository_Transition(t *testing.T) {
+	attemptAt := time.Now()
+	cause := &handler.StatusCodeError{Message: "erro ao enviar notificação", StatusCode: 503}
+
+	cancel := func(n *entity.Notification) (*entity.NotificationEvent, error) {
+		return n.Cancel(entity.ActorAPI)
+	}
+	fail := func(n *entity.Notification) (*entity.NotificationEvent, error) {
+		return n.MarkAsFailed(entity.ActorWorker, cause)
+	}
+
+	tests := []struct {
+		name         string
+		notification entity.Notification
+		transition   func(*entity.Notification) (*entity.NotificationEvent, error)
+		updateArgs   func(n *entity.Notification) []driver.Value
+		eventArgs    []driver.Value
+		rows         int64
+		expectedErr  error
+	}{
+		{
+			name:         "cancelamento grava status e evento",
+			notification: entity.Notification{ID: uuid.New(), Status: entity.StatusPending},
+			transition:   cancel,
+			updateArgs: func(n *entity.Notification) []driver.Value {
+				return []driver.Value{entity.StatusCanceled, nil, 0, "", 0, nil, sqlmock.AnyArg(), n.ID, entity.StatusPending}
+			},
+			eventArgs: []driver.Value{entity.StatusPending, entity.StatusCanceled, entity.ActorAPI, ""},
+			rows:      1,
+		},
+		{
+			name:         "falha grava tentativas, erro e status HTTP",
+			notification: entity.Notification{ID: uuid.New(), Status: entity.StatusProcessing, Attempts: 2, LastAttemptAt: &attemptAt},
+			transition:   fail,
+			updateArgs: func(n *entity.Notification) []driver.Value {
+				return []driver.Value{entity.StatusFailed, nil, 2, cause.Error(), 503, &attemptAt, sqlmock.AnyArg(), n.ID, entity.StatusProcessing}
+			},
+			eventArgs: []driver.Value{entity.StatusProcessing, entity.StatusFailed, entity.ActorWorker, cause.Error()},
+			rows:      1,
+		},
+		{
+			name:         "status alterado por outro processo",
+			notification: entity.Notification{ID: uuid.New(), Status: entity.StatusPending},
+			transition:   cancel,
+			updateArgs: func(n *entity.Notification) []driver.Value {
+				return []driver.Value{entity.StatusCanceled, nil, 0, "", 0, nil, sqlmock.AnyArg(), n.ID, entity.StatusPending}
+			},
+			rows:        0,
+			expectedErr: handler.ErrInvalidStatusTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("erro criando mock do db: %v", err)
+			}
+			defer db.Close()
+
+			repo := postgres.NewNotificationRepository(db)
+			notification := tt.notification
+			event, err := tt.transition(&notification)
+			assert.NoError(t, err)
+
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE notifications`).
+				WithArgs(tt.updateArgs(&notification)...).
+				WillReturnResult(sqlmock.NewResult(0, tt.rows))
+			if tt.expectedErr == nil {
+				args := append([]driver.Value{event.ID, notification.ID}, tt.eventArgs...)
+				args = append(args, "", sqlmock.AnyArg())
+				mock.ExpectExec(`INSERT INTO notification_events`).
+					WithArgs(args...).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			err = repo.Transition(context.Background(), &notification, event)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNotificationRepository_RescheduleWritesOutbox(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("erro criando mock do db: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewNotificationRepository(db)
+	notification := &entity.Notification{ID: uuid.New(), Status: entity.StatusPending, ScheduledFor: time.Now()}
+	event, err := notification.Reschedule(time.Now().Add(time.Hour), entity.ActorAPI)
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE notifications`).
+		WithArgs(notification.ScheduledFor, sqlmock.AnyArg(), notification.ID, entity.StatusPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO notification_events`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO outbox`).
+		WithArgs(sqlmock.AnyArg(), notification.ID, entity.EventNotificationScheduled, sqlmock.AnyArg(), 0, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.Reschedule(context.Background(), notification, event))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/domain/repository"
+)
+
+type outboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) repository.OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+func insertOutboxMessage(ctx context.Context, tx *sql.Tx, message *entity.OutboxMessage) error {
+	query := `
+        INSERT INTO outbox (id, aggregate_id, event_type, payload, attempts, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `
+
+	_, err := tx.ExecContext(ctx, query,
+		message.ID,
+		message.AggregateID,
+		message.EventType,
+		[]byte(message.Payload),
+		message.Attempts,
+		message.CreatedAt,
+	)
+
+	return err
+}
+
+// outboxClaimLease é por quanto tempo as mensagens reivindicadas ficam fora
+// das próximas buscas enquanto são publicadas. Se o processo cair antes de
+// marcá-las, elas voltam a ser despachadas depois desse prazo.
+const outboxClaimLease = 5 * time.Minute
+
+// DispatchPending reivindica um lote de mensagens adiando o available_at e
+// confirma a reivindicação antes de publicar, para não manter conexão e
+// bloqueios abertos enquanto espera o broker. Cada mensagem publicada é
+// marcada como despachada em um comando próprio.
+func (r *outboxRepository) DispatchPending(ctx context.Context, limit int, publish func(*entity.OutboxMessage) error) (int, error) {
+	messages, err := r.claim(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	dispatched := 0
+	for _, message := range messages {
+		if err := publish(message); err != nil {
+			_, err = r.db.ExecContext(ctx, `
+                UPDATE outbox
+                SET attempts = attempts + 1,
+                    last_error = $1,
+                    available_at = NOW() + make_interval(secs => LEAST(300, POWER(2, attempts + 1)))
+                WHERE id = $2
+            `, err.Error(), message.ID)
+			if err != nil {
+				return dispatched, err
+			}
+			continue
+		}
+
+		_, err = r.db.ExecContext(ctx, `
+            UPDATE outbox
+            SET dispatched_at = NOW(), attempts = attempts + 1, last_error = NULL
+            WHERE id = $1
+        `, message.ID)
+		if err != nil {
+			return dispatched, err
+		}
+		dispatched++
+	}
+
+	return dispatched, nil
+}
+
+func (r *outboxRepository) claim(ctx context.Context, limit int) ([]*entity.OutboxMessage, error) {
+	query := `
+        UPDATE outbox
+        SET available_at = NOW() + make_interval(secs => $2)
+        WHERE id IN (
+            SELECT id
+            FROM outbox
+            WHERE dispatched_at IS NULL AND available_at <= NOW()
+            ORDER BY created_at
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, aggregate_id, event_type, payload, attempts, COALESCE(last_error, ''), created_at
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, limit, outboxClaimLease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*entity.OutboxMessage
+	for rows.Next() {
+		message := &entity.OutboxMessage{}
+		var payload []byte
+		err := rows.Scan(
+			&message.ID,
+			&message.AggregateID,
+			&message.EventType,
+			&payload,
+			&message.Attempts,
+			&message.LastError,
+			&message.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		message.Payload = payload
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING não preserva a ordem da subconsulta
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
+
+	return messages, nil
+}
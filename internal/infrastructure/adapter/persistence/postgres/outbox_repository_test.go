@@ -0,0 +1,55 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/infrastructure/adapter/persistence/postgres"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxRepository_DispatchPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("erro criando mock do db: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewOutboxRepository(db)
+	createdAt := time.Date(2024, 2, 2, 10, 0, 0, 0, time.UTC)
+	published, failed := uuid.New(), uuid.New()
+	columns := []string{"id", "aggregate_id", "event_type", "payload", "attempts", "last_error", "created_at"}
+
+	// Nenhuma transação fica aberta durante a publicação: a reivindicação e
+	// cada marcação são comandos independentes
+	mock.ExpectQuery(`UPDATE outbox\s+SET available_at = NOW\(\) \+ make_interval\(secs => \$2\)[\s\S]+FOR UPDATE SKIP LOCKED[\s\S]+RETURNING`).
+		WithArgs(100, (5 * time.Minute).Seconds()).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(failed, uuid.New(), entity.EventNotificationScheduled, []byte(`{}`), 0, "", createdAt.Add(time.Second)).
+			AddRow(published, uuid.New(), entity.EventNotificationScheduled, []byte(`{}`), 0, "", createdAt))
+	mock.ExpectExec(`SET dispatched_at = NOW\(\)`).
+		WithArgs(published).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SET attempts = attempts \+ 1,\s+last_error = \$1`).
+		WithArgs("broker indisponível", failed).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	var order []uuid.UUID
+	dispatched, err := repo.DispatchPending(context.Background(), 100, func(message *entity.OutboxMessage) error {
+		order = append(order, message.ID)
+		if message.ID == failed {
+			return errors.New("broker indisponível")
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dispatched)
+	assert.Equal(t, []uuid.UUID{published, failed}, order)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
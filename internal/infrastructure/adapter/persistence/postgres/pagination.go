@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/google/uuid"
+)
+
+// listQuery acumula as condições de uma listagem, numerando os parâmetros
+// na ordem em que são adicionados. O "?" da condição é trocado pelo
+// parâmetro correspondente.
+type listQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (q *listQuery) where(condition string, value interface{}) {
+	q.args = append(q.args, value)
+	q.conditions = append(q.conditions, strings.Replace(condition, "?", fmt.Sprintf("$%d", len(q.args)), 1))
+}
+
+// after restringe a listagem aos registros posteriores ao cursor na
+// ordenação por coluna e ID.
+func (q *listQuery) after(column, comparison string, cursor *entity.Cursor) {
+	q.args = append(q.args, cursor.Value, cursor.ID)
+	q.conditions = append(q.conditions, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, comparison, len(q.args)-1, len(q.args)))
+}
+
+func (q *listQuery) clause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// sortColumn associa um campo de ordenação aceito pela API à coluna da
+// tabela e ao valor do campo usado para montar o cursor.
+type sortColumn[T any] struct {
+	column string
+	value  func(T) string
+}
+
+type pageQuery[T any] struct {
+	table       string
+	columns     string
+	sorts       map[string]sortColumn[T]
+	defaultSort string
+	id          func(T) uuid.UUID
+	scan        func(rowScanner) (T, error)
+}
+
+// paginate conta o total de registros que atendem aos filtros e busca a
+// página seguinte ao cursor, ordenando pelo campo escolhido e pelo ID.
+func paginate[T any](ctx context.Context, db *sql.DB, spec pageQuery[T], q listQuery, page entity.PageRequest) (*entity.Page[T], error) {
+	field, desc := page.SortField(spec.defaultSort)
+	sort, ok := spec.sorts[field]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", handler.ErrInvalidSort, field)
+	}
+
+	var cursor *entity.Cursor
+	if page.Cursor != "" {
+		decoded, err := entity.DecodeCursor(page.Cursor, page.Sort)
+		if err != nil {
+			return nil, err
+		}
+		cursor = decoded
+	}
+
+	result := &entity.Page[T]{Items: []T{}}
+
+	countQuery := `SELECT COUNT(*) FROM ` + spec.table + ` ` + q.clause()
+	if err := db.QueryRowContext(ctx, countQuery, q.args...).Scan(&result.Total); err != nil {
+		return nil, err
+	}
+
+	direction, comparison := "ASC", ">"
+	if desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if cursor != nil {
+		q.after(sort.column, comparison, cursor)
+	}
+
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM %s
+        %s
+        ORDER BY %s %s, id %s
+        LIMIT %d
+    `, spec.columns, spec.table, q.clause(), sort.column, direction, direction, page.Limit+1)
+
+	rows, err := db.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := spec.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(result.Items) > page.Limit {
+		result.Items = result.Items[:page.Limit]
+		last := result.Items[len(result.Items)-1]
+		result.NextCursor = entity.Cursor{
+			Sort:  page.Sort,
+			Value: sort.value(last),
+			ID:    spec.id(last),
+		}.Encode()
+	}
+
+	return result, nil
+}
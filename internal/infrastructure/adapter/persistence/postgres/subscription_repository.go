@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type subscriptionRepository struct {
+	db *sql.DB
+}
+
+func NewSubscriptionRepository(db *sql.DB) repository.SubscriptionRepository {
+	return &subscriptionRepository{
+		db: db,
+	}
+}
+
+func (r *subscriptionRepository) Create(ctx context.Context, subscription *entity.Subscription) error {
+	query := `
+        INSERT INTO subscriptions (
+            id, user_id, location_id, cron_expression, timezone, active,
+            next_run_at, last_run_at, created_at, updated_at
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    `
+
+	_, err := r.db.ExecContext(ctx, query,
+		subscription.ID,
+		subscription.UserID,
+		subscription.LocationID,
+		subscription.CronExpression,
+		subscription.Timezone,
+		subscription.Active,
+		subscription.NextRunAt,
+		subscription.LastRunAt,
+		subscription.CreatedAt,
+		subscription.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *subscriptionRepository) Update(ctx context.Context, subscription *entity.Subscription) error {
+	query := `
+        UPDATE subscriptions
+        SET location_id = $1, cron_expression = $2, timezone = $3, active = $4,
+            next_run_at = $5, last_run_at = $6, updated_at = $7
+        WHERE id = $8
+    `
+
+	result, err := r.db.ExecContext(ctx, query,
+		subscription.LocationID,
+		subscription.CronExpression,
+		subscription.Timezone,
+		subscription.Active,
+		subscription.NextRunAt,
+		subscription.LastRunAt,
+		subscription.UpdatedAt,
+		subscription.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return handler.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *subscriptionRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*entity.Subscription, error) {
+	query := `
+        SELECT id, user_id, location_id, cron_expression, timezone, active,
+               next_run_at, last_run_at, created_at, updated_at
+        FROM subscriptions
+        WHERE id = $1 AND user_id = $2
+    `
+
+	subscription := &entity.Subscription{}
+	err := r.db.QueryRowContext(ctx, query, id, userID).Scan(
+		&subscription.ID,
+		&subscription.UserID,
+		&subscription.LocationID,
+		&subscription.CronExpression,
+		&subscription.Timezone,
+		&subscription.Active,
+		&subscription.NextRunAt,
+		&subscription.LastRunAt,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, handler.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (r *subscriptionRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]*entity.Subscription, error) {
+	query := `
+        SELECT id, user_id, location_id, cron_expression, timezone, active,
+               next_run_at, last_run_at, created_at, updated_at
+        FROM subscriptions
+        WHERE user_id = $1
+        ORDER BY created_at
+    `
+
+	return r.querySubscriptions(ctx, query, userID)
+}
+
+func (r *subscriptionRepository) FindDue(ctx context.Context, now time.Time) ([]*entity.Subscription, error) {
+	query := `
+        SELECT id, user_id, location_id, cron_expression, timezone, active,
+               next_run_at, last_run_at, created_at, updated_at
+        FROM subscriptions
+        WHERE active = true AND next_run_at <= $1
+        ORDER BY next_run_at
+    `
+
+	return r.querySubscriptions(ctx, query, now)
+}
+
+func (r *subscriptionRepository) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]*entity.Subscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*entity.Subscription
+	for rows.Next() {
+		subscription := &entity.Subscription{}
+		err := rows.Scan(
+			&subscription.ID,
+			&subscription.UserID,
+			&subscription.LocationID,
+			&subscription.CronExpression,
+			&subscription.Timezone,
+			&subscription.Active,
+			&subscription.NextRunAt,
+			&subscription.LastRunAt,
+			&subscription.CreatedAt,
+			&subscription.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *subscriptionRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	query := `
+        DELETE FROM subscriptions
+        WHERE id = $1 AND user_id = $2
+    `
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return handler.ErrNotFound
+	}
+
+	return nil
+}
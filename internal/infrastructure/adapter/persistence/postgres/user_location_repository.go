@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+	"weather-notification/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+const userLocationColumns = "id, user_id, location_id, label, is_primary, notify, created_at, updated_at"
+
+type userLocationRepository struct {
+	db *sql.DB
+}
+
+func NewUserLocationRepository(db *sql.DB) repository.UserLocationRepository {
+	return &userLocationRepository{
+		db: db,
+	}
+}
+
+// Create salva a localização. Quando ela é a principal, a principal
+// anterior é rebaixada e a localização do cadastro é trocada na mesma
+// transação.
+func (r *userLocationRepository) Create(ctx context.Context, location *entity.UserLocation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if location.Primary {
+		if err := demotePrimary(ctx, tx, location.UserID, location.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO user_locations (id, user_id, location_id, label, is_primary, notify, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    `,
+		location.ID,
+		location.UserID,
+		location.LocationID,
+		location.Label,
+		location.Primary,
+		location.Notify,
+		location.CreatedAt,
+		location.UpdatedAt,
+	)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if location.Primary {
+		if err := setUserLocation(ctx, tx, location.UserID, location.LocationID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *userLocationRepository) Update(ctx context.Context, location *entity.UserLocation) error {
+	query := `
+        UPDATE user_locations
+        SET label = $1, notify = $2, updated_at = $3
+        WHERE id = $4 AND user_id = $5
+    `
+
+	result, err := r.db.ExecContext(ctx, query,
+		location.Label,
+		location.Notify,
+		location.UpdatedAt,
+		location.ID,
+		location.UserID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return handler.ErrNotFound
+	}
+
+	return nil
+}
+
+// SetPrimary troca a localização principal do usuário e atualiza a
+// localização do cadastro na mesma transação.
+func (r *userLocationRepository) SetPrimary(ctx context.Context, userID, id uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := demotePrimary(ctx, tx, userID, id); err != nil {
+		return err
+	}
+
+	var locationID uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+        UPDATE user_locations
+        SET is_primary = true, updated_at = NOW()
+        WHERE user_id = $1 AND id = $2
+        RETURNING location_id
+    `, userID, id).Scan(&locationID)
+	if err == sql.ErrNoRows {
+		return handler.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := setUserLocation(ctx, tx, userID, locationID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// demotePrimary tira a marca de principal das outras localizações do
+// usuário, mantendo-as salvas.
+func demotePrimary(ctx context.Context, tx *sql.Tx, userID, id uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `
+        UPDATE user_locations
+        SET is_primary = false, updated_at = NOW()
+        WHERE user_id = $1 AND is_primary AND id <> $2
+    `, userID, id)
+	return err
+}
+
+func setUserLocation(ctx context.Context, tx *sql.Tx, userID, locationID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `
+        UPDATE users
+        SET location_id = $1, updated_at = NOW()
+        WHERE id = $2
+    `, locationID, userID)
+	return err
+}
+
+func (r *userLocationRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*entity.UserLocation, error) {
+	query := `
+        SELECT ` + userLocationColumns + `
+        FROM user_locations
+        WHERE id = $1 AND user_id = $2
+    `
+
+	location, err := scanUserLocation(r.db.QueryRowContext(ctx, query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, handler.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+func (r *userLocationRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]entity.UserLocation, error) {
+	query := `
+        SELECT ` + userLocationColumns + `
+        FROM user_locations
+        WHERE user_id = $1
+        ORDER BY is_primary DESC, created_at, id
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := []entity.UserLocation{}
+	for rows.Next() {
+		location, err := scanUserLocation(rows)
+		if err != nil {
+			return nil, err
+		}
+		locations = append(locations, location)
+	}
+
+	return locations, rows.Err()
+}
+
+// Delete remove uma localização salva. A localização principal não é
+// removida por aqui, já que ela espelha a localização do cadastro.
+func (r *userLocationRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+        DELETE FROM user_locations
+        WHERE id = $1 AND user_id = $2 AND NOT is_primary
+    `, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return handler.ErrNotFound
+	}
+
+	return nil
+}
+
+func scanUserLocation(row rowScanner) (entity.UserLocation, error) {
+	location := entity.UserLocation{}
+	err := row.Scan(
+		&location.ID,
+		&location.UserID,
+		&location.LocationID,
+		&location.Label,
+		&location.Primary,
+		&location.Notify,
+		&location.CreatedAt,
+		&location.UpdatedAt,
+	)
+	return location, err
+}
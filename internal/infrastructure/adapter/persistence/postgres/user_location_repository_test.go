@@ -0,0 +1,56 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"weather-notification/internal/domain/entity"
+	"weather-notification/internal/infrastructure/adapter/persistence/postgres"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserLocationRepository_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		primary bool
+	}{
+		{"localização comum", false},
+		{"localização principal rebaixa a anterior na mesma transação", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("erro criando mock do db: %v", err)
+			}
+			defer db.Close()
+
+			repo := postgres.NewUserLocationRepository(db)
+			location, err := entity.NewUserLocation(uuid.New(), uuid.New(), "casa de praia")
+			assert.NoError(t, err)
+			location.Primary = tt.primary
+
+			mock.ExpectBegin()
+			if tt.primary {
+				mock.ExpectExec(`UPDATE user_locations\s+SET is_primary = false`).
+					WithArgs(location.UserID, location.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			mock.ExpectExec(`INSERT INTO user_locations`).
+				WithArgs(location.ID, location.UserID, location.LocationID, location.Label, tt.primary, location.Notify, location.CreatedAt, location.UpdatedAt).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			if tt.primary {
+				mock.ExpectExec(`UPDATE users`).
+					WithArgs(location.LocationID, location.UserID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			mock.ExpectCommit()
+
+			assert.NoError(t, repo.Create(context.Background(), location))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -3,6 +3,8 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"time"
 	"weather-notification/internal/domain/entity"
 	handler "weather-notification/internal/domain/error_handler"
 	"weather-notification/internal/domain/repository"
@@ -10,6 +12,8 @@ import (
 	"github.com/google/uuid"
 )
 
+const userColumns = "id, location_id, name, email, opt_out, channels, timezone, notification_mode, forecast_days, created_at, updated_at"
+
 type userRepository struct {
 	db *sql.DB
 }
@@ -20,96 +24,150 @@ func NewUserRepository(db *sql.DB) repository.UserRepository {
 	}
 }
 
+// Create grava o usuário e registra a localização do cadastro como a
+// localização principal salva.
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
 	query := `
-        INSERT INTO users (id, location_id, name, email, opt_out, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        INSERT INTO users (id, location_id, name, email, opt_out, channels, timezone, notification_mode, forecast_days, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
     `
 
-	_, err := r.db.ExecContext(ctx, query,
+	channels, err := marshalChannels(user.Channels)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, query,
 		user.ID,
 		user.LocationID,
 		user.Name,
 		user.Email,
 		user.OptOut,
+		channels,
+		user.Timezone,
+		notificationMode(user.NotificationMode),
+		forecastDays(user.ForecastDays),
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
-
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
-	return nil
+	if err := syncPrimaryLocation(ctx, tx, user.ID, user.LocationID); err != nil {
+		return translateError(err)
+	}
+
+	return tx.Commit()
 }
 
+// Update altera o usuário e, quando a localização do cadastro muda, troca
+// a localização principal salva.
 func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
 	query := `
 		UPDATE users
-		SET name = $1, email = $2, location_id = $3, opt_out = $4, updated_at = NOW()
-		WHERE id = $5
+		SET name = $1, email = $2, location_id = $3, opt_out = $4, channels = $5, timezone = $6,
+		    notification_mode = $7, forecast_days = $8, updated_at = NOW()
+		WHERE id = $9
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	channels, err := marshalChannels(user.Channels)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, query,
 		user.Name,
 		user.Email,
 		user.LocationID,
 		user.OptOut,
+		channels,
+		user.Timezone,
+		notificationMode(user.NotificationMode),
+		forecastDays(user.ForecastDays),
 		user.ID,
 	)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if err := syncPrimaryLocation(ctx, tx, user.ID, user.LocationID); err != nil {
+		return translateError(err)
+	}
+
+	return tx.Commit()
+}
 
+// syncPrimaryLocation garante que a localização do cadastro seja a
+// localização principal salva. A principal anterior continua salva como
+// localização comum; se a nova localização já estava salva, ela passa a ser
+// a principal.
+func syncPrimaryLocation(ctx context.Context, tx *sql.Tx, userID, locationID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `
+        UPDATE user_locations
+        SET is_primary = false, updated_at = NOW()
+        WHERE user_id = $1 AND is_primary AND location_id <> $2
+    `, userID, locationID)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO user_locations (id, user_id, location_id, label, is_primary, notify, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, true, true, NOW(), NOW())
+        ON CONFLICT (user_id, location_id) DO UPDATE SET is_primary = true, updated_at = NOW()
+    `, uuid.New(), userID, locationID, entity.PrimaryLocationLabel)
+	return err
+}
+
+func notificationMode(mode entity.NotificationMode) entity.NotificationMode {
+	if mode == "" {
+		return entity.NotificationModeSeparate
+	}
+	return mode
+}
+
+func forecastDays(days int) int {
+	if days == 0 {
+		return entity.DefaultForecastDays
+	}
+	return days
 }
 
 func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
 	query := `
-        SELECT id, location_id, name, email, opt_out, created_at, updated_at
+        SELECT ` + userColumns + `
         FROM users
         WHERE id = $1
     `
-	user := &entity.User{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID,
-		&user.LocationID,
-		&user.Name,
-		&user.Email,
-		&user.OptOut,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, handler.ErrNotFound
-	}
-	if err != nil {
-		return nil, err
-	}
 
-	return user, nil
+	return r.findOne(ctx, query, id)
 }
 
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	query := `
-        SELECT id, location_id, name, email, opt_out, created_at, updated_at
+        SELECT ` + userColumns + `
         FROM users
         WHERE email = $1
     `
 
-	user := &entity.User{}
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.LocationID,
-		&user.Name,
-		&user.Email,
-		&user.OptOut,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	return r.findOne(ctx, query, email)
+}
 
+func (r *userRepository) findOne(ctx context.Context, query string, args ...interface{}) (*entity.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, args...))
 	if err == sql.ErrNoRows {
 		return nil, handler.ErrNotFound
 	}
@@ -117,7 +175,7 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity
 		return nil, err
 	}
 
-	return user, nil
+	return &user, nil
 }
 
 func (r *userRepository) UpdateOptOut(ctx context.Context, id uuid.UUID, optOut bool) error {
@@ -144,42 +202,100 @@ func (r *userRepository) UpdateOptOut(ctx context.Context, id uuid.UUID, optOut
 	return nil
 }
 
-func (r *userRepository) FindAll(ctx context.Context) ([]entity.User, error) {
-	query := `
-		SELECT id, location_id, name, email, opt_out, created_at, updated_at
-		FROM users
-	`
+// Delete remove o usuário; notificações, entregas, inscrições e alertas são
+// removidos em cascata pelo banco. As mensagens das notificações que ainda
+// não foram publicadas saem do outbox na mesma transação.
+func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	rows, err := r.db.QueryContext(ctx, query)
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM outbox
+        WHERE dispatched_at IS NULL
+          AND aggregate_id IN (SELECT id FROM notifications WHERE user_id = $1)
+    `, id)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
 
-	var users []entity.User
-	for rows.Next() {
-		user := entity.User{}
-		err := rows.Scan(
-			&user.ID,
-			&user.LocationID,
-			&user.Name,
-			&user.Email,
-			&user.OptOut,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		users = append(users, user)
+	result, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
 	}
 
-	return users, nil
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return handler.ErrNotFound
+	}
+
+	return tx.Commit()
+}
+
+var userPageQuery = pageQuery[entity.User]{
+	table:       "users",
+	columns:     userColumns,
+	defaultSort: "created_at",
+	sorts: map[string]sortColumn[entity.User]{
+		"created_at": {"created_at", func(u entity.User) string { return u.CreatedAt.Format(time.RFC3339Nano) }},
+		"name":       {"name", func(u entity.User) string { return u.Name }},
+		"email":      {"email", func(u entity.User) string { return u.Email }},
+	},
+	id:   func(u entity.User) uuid.UUID { return u.ID },
+	scan: scanUser,
+}
+
+func (r *userRepository) List(ctx context.Context, filter entity.UserFilter, page entity.PageRequest) (*entity.Page[entity.User], error) {
+	var q listQuery
+	if filter.OptOut != nil {
+		q.where("opt_out = ?", *filter.OptOut)
+	}
+	if filter.LocationID != nil {
+		q.where("location_id = ?", *filter.LocationID)
+	}
+	if filter.CreatedFrom != nil {
+		q.where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedBefore != nil {
+		q.where("created_at < ?", *filter.CreatedBefore)
+	}
+
+	return paginate(ctx, r.db, userPageQuery, q, page)
+}
+
+func scanUser(row rowScanner) (entity.User, error) {
+	user := entity.User{}
+	var channels []byte
+	err := row.Scan(
+		&user.ID,
+		&user.LocationID,
+		&user.Name,
+		&user.Email,
+		&user.OptOut,
+		&channels,
+		&user.Timezone,
+		&user.NotificationMode,
+		&user.ForecastDays,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return user, err
+	}
+
+	err = json.Unmarshal(channels, &user.Channels)
+	return user, err
 }
 
 func (r *userRepository) FindAllActive(ctx context.Context) ([]entity.User, error) {
 	query := `
-		SELECT id, location_id, name, email, opt_out, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE opt_out = false
 	`
@@ -192,16 +308,7 @@ func (r *userRepository) FindAllActive(ctx context.Context) ([]entity.User, erro
 
 	var users []entity.User
 	for rows.Next() {
-		user := entity.User{}
-		err := rows.Scan(
-			&user.ID,
-			&user.LocationID,
-			&user.Name,
-			&user.Email,
-			&user.OptOut,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -210,3 +317,10 @@ func (r *userRepository) FindAllActive(ctx context.Context) ([]entity.User, erro
 
 	return users, nil
 }
+
+func marshalChannels(channels []entity.UserChannel) ([]byte, error) {
+	if channels == nil {
+		channels = []entity.UserChannel{}
+	}
+	return json.Marshal(channels)
+}
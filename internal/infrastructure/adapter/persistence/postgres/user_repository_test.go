@@ -2,13 +2,17 @@ package postgres_test
 
 import (
 	"context"
+	"database/sql/driver"
+	"regexp"
 	"testing"
 	"time"
 	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
 	"weather-notification/internal/infrastructure/adapter/persistence/postgres"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -28,15 +32,195 @@ func TestUserRepository_Create(t *testing.T) {
 		Name:       "Matheus",
 		Email:      "matheus@exemplo.com",
 		OptOut:     false,
+		Timezone:   entity.DefaultTimezone,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectExec(`INSERT INTO users`).
-		WithArgs(user.ID, user.LocationID, user.Name, user.Email, user.OptOut, user.CreatedAt, user.UpdatedAt).
+		WithArgs(user.ID, user.LocationID, user.Name, user.Email, user.OptOut, []byte("[]"), user.Timezone, entity.NotificationModeSeparate, entity.DefaultForecastDays, user.CreatedAt, user.UpdatedAt).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE user_locations\s+SET is_primary = false`).
+		WithArgs(user.ID, user.LocationID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO user_locations`).
+		WithArgs(sqlmock.AnyArg(), user.ID, user.LocationID, entity.PrimaryLocationLabel).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	err = repo.Create(ctx, user)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUserRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("erro criando mock do db: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewUserRepository(db)
+	ctx := context.Background()
+
+	columns := []string{"id", "location_id", "name", "email", "opt_out", "channels", "timezone", "notification_mode", "forecast_days", "created_at", "updated_at"}
+	createdAt := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	row := func(id uuid.UUID, name string) []driver.Value {
+		return []driver.Value{id, uuid.New(), name, name + "@exemplo.com", false, []byte("[]"), entity.DefaultTimezone, entity.NotificationModeSeparate, entity.DefaultForecastDays, createdAt, createdAt}
+	}
+	optOut := false
+	filter := entity.UserFilter{OptOut: &optOut}
+
+	t.Run("primeira página retorna o total e o cursor", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users WHERE opt_out = $1`)).
+			WithArgs(false).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectQuery(`ORDER BY name ASC, id ASC\s+LIMIT 3`).
+			WithArgs(false).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(row(ids[0], "Ana")...).
+				AddRow(row(ids[1], "Bruno")...).
+				AddRow(row(ids[2], "Carla")...))
+
+		page, err := repo.List(ctx, filter, entity.NewPageRequest(2, "", "name"))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, page.Total)
+		assert.Len(t, page.Items, 2)
+		assert.NotEmpty(t, page.NextCursor)
+
+		cursor, err := entity.DecodeCursor(page.NextCursor, "name")
+		assert.NoError(t, err)
+		assert.Equal(t, "Bruno", cursor.Value)
+		assert.Equal(t, ids[1], cursor.ID)
+
+		t.Run("página seguinte parte do cursor", func(t *testing.T) {
+			mock.ExpectQuery(`SELECT COUNT`).
+				WithArgs(false).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			mock.ExpectQuery(regexp.QuoteMeta(`WHERE opt_out = $1 AND (name, id) > ($2, $3)`)).
+				WithArgs(false, "Bruno", ids[1]).
+				WillReturnRows(sqlmock.NewRows(columns).AddRow(row(ids[2], "Carla")...))
+
+			next, err := repo.List(ctx, filter, entity.NewPageRequest(2, page.NextCursor, "name"))
+			assert.NoError(t, err)
+			assert.Len(t, next.Items, 1)
+			assert.Empty(t, next.NextCursor)
+		})
+	})
+
+	t.Run("ordenação decrescente", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(`ORDER BY created_at DESC, id DESC`).WillReturnRows(sqlmock.NewRows(columns))
+
+		page, err := repo.List(ctx, entity.UserFilter{}, entity.NewPageRequest(0, "", "-created_at"))
+		assert.NoError(t, err)
+		assert.Empty(t, page.Items)
+	})
+
+	t.Run("campo de ordenação inválido", func(t *testing.T) {
+		_, err := repo.List(ctx, filter, entity.NewPageRequest(10, "", "channels"))
+		assert.ErrorIs(t, err, handler.ErrInvalidSort)
+	})
+
+	t.Run("cursor de outra ordenação", func(t *testing.T) {
+		cursor := entity.Cursor{Sort: "name", Value: "Ana", ID: ids[0]}.Encode()
+
+		_, err := repo.List(ctx, filter, entity.NewPageRequest(10, cursor, "email"))
+		assert.ErrorIs(t, err, handler.ErrInvalidCursor)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_CreateDuplicateEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("erro criando mock do db: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewUserRepository(db)
+	user, err := entity.NewUser("Matheus", "matheus@exemplo.com", uuid.New())
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO users`).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "users_email_key"})
+	mock.ExpectRollback()
+
+	err = repo.Create(context.Background(), user)
+	assert.ErrorIs(t, err, handler.ErrDuplicateKey)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Delete(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        int64
+		expectedErr error
+	}{
+		{"remove usuário e mensagens pendentes", 1, nil},
+		{"usuário inexistente", 0, handler.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("erro criando mock do db: %v", err)
+			}
+			defer db.Close()
+
+			repo := postgres.NewUserRepository(db)
+			id := uuid.New()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(`DELETE FROM outbox`).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec(`DELETE FROM users`).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, tt.rows))
+			if tt.expectedErr == nil {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			err = repo.Delete(context.Background(), id)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepository_UpdateSyncsPrimaryLocation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("erro criando mock do db: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgres.NewUserRepository(db)
+	user, err := entity.NewUser("Matheus", "matheus@exemplo.com", uuid.New())
+	assert.NoError(t, err)
+	assert.NoError(t, user.SetNotificationMode(entity.NotificationModeCombined))
+	assert.NoError(t, user.SetForecastDays(7))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE users`).
+		WithArgs(user.Name, user.Email, user.LocationID, user.OptOut, []byte("[]"), user.Timezone, entity.NotificationModeCombined, 7, user.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE user_locations\s+SET is_primary = false, updated_at = NOW\(\)\s+WHERE user_id = \$1 AND is_primary AND location_id <> \$2`).
+		WithArgs(user.ID, user.LocationID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`ON CONFLICT \(user_id, location_id\) DO UPDATE SET is_primary = true`).
+		WithArgs(sqlmock.AnyArg(), user.ID, user.LocationID, entity.PrimaryLocationLabel).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.Update(context.Background(), user))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
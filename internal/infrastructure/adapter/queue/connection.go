@@ -0,0 +1,261 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var (
+	ErrBrokerUnavailable = errors.New("RabbitMQ indisponível")
+	ErrConfirmTimeout    = errors.New("tempo esgotado aguardando confirmação do RabbitMQ")
+	ErrPublishNacked     = errors.New("publicação rejeitada pelo RabbitMQ")
+	ErrMessageReturned   = errors.New("mensagem devolvida pelo RabbitMQ")
+)
+
+const (
+	defaultConfirmTimeout    = 5 * time.Second
+	defaultReconnectDelay    = time.Second
+	defaultMaxReconnectDelay = 30 * time.Second
+)
+
+// deferredConfirmation é a confirmação de uma única publicação. O cliente
+// AMQP a associa à delivery tag atribuída pelo broker, inclusive quando
+// publicações anteriores falharam.
+type deferredConfirmation interface {
+	Done() <-chan struct{}
+	Acked() bool
+}
+
+// confirmChannel adapta *amqp.Channel ao amqpChannel, expondo a confirmação
+// adiada pela interface deferredConfirmation.
+type confirmChannel struct {
+	*amqp.Channel
+}
+
+func (c confirmChannel) PublishWithDeferredConfirmWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) (deferredConfirmation, error) {
+	confirmation, err := c.Channel.PublishWithDeferredConfirmWithContext(ctx, exchange, key, mandatory, immediate, msg)
+	if err != nil || confirmation == nil {
+		return nil, err
+	}
+	return confirmation, nil
+}
+
+// dialFunc abre uma nova conexão e canal com o broker. A conexão pode ser nil
+// quando o canal não depende de uma conexão própria.
+type dialFunc func() (amqpChannel, io.Closer, error)
+
+func dialURL(url string) dialFunc {
+	return func() (amqpChannel, io.Closer, error) {
+		conn, err := amqp.Dial(url)
+		if err != nil {
+			return nil, nil, fmt.Errorf("erro ao conectar ao RabbitMQ: %w", err)
+		}
+
+		ch, err := conn.Channel()
+		if err != nil {
+			conn.Close()
+			return nil, nil, fmt.Errorf("erro ao criar canal: %w", err)
+		}
+
+		return confirmChannel{ch}, conn, nil
+	}
+}
+
+// connect abre um novo canal em modo de confirmação, declara a topologia,
+// migra a fila de retentativas antiga e reassina os consumidores registrados.
+func (s *RabbitMQService) connect() error {
+	ch, conn, err := s.dial()
+	if err != nil {
+		return err
+	}
+
+	if err := s.setup(ch); err != nil {
+		closeSession(ch, conn)
+		return err
+	}
+
+	if err := ch.Confirm(false); err != nil {
+		closeSession(ch, conn)
+		return fmt.Errorf("erro ao ativar confirmação de publicação: %w", err)
+	}
+
+	if err := ch.Qos(s.config.Prefetch, 0, false); err != nil {
+		closeSession(ch, conn)
+		return fmt.Errorf("erro ao configurar prefetch: %w", err)
+	}
+
+	returns := ch.NotifyReturn(make(chan amqp.Return, 16))
+	closed := ch.NotifyClose(make(chan *amqp.Error, 1))
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		closeSession(ch, conn)
+		return ErrBrokerUnavailable
+	}
+	s.channel = ch
+	s.conn = conn
+	s.returns = returns
+	consumers := append([]*consumer(nil), s.consumers...)
+	s.mu.Unlock()
+
+	if err := s.migrateLegacyRetryQueue(context.Background(), ch); err != nil {
+		log.Printf("Erro ao migrar a fila %s: %v", legacyRetryQueue, err)
+	}
+
+	for _, c := range consumers {
+		if err := s.subscribe(ch, c); err != nil {
+			log.Printf("Erro ao reassinar consumidor: %v", err)
+		}
+	}
+
+	go s.watch(closed)
+
+	return nil
+}
+
+// watch aguarda o fechamento do canal e, se não foi solicitado pelo
+// serviço, reconecta com backoff exponencial.
+func (s *RabbitMQService) watch(closed <-chan *amqp.Error) {
+	reason, ok := <-closed
+	if !ok || reason == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	ch, conn := s.channel, s.conn
+	s.channel = nil
+	s.conn = nil
+	s.mu.Unlock()
+
+	log.Printf("Conexão com o RabbitMQ perdida: %v", reason)
+	closeSession(ch, conn)
+
+	delay := s.reconnectDelay
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-time.After(delay):
+		}
+
+		err := s.connect()
+		if err == nil {
+			log.Printf("Conexão com o RabbitMQ restabelecida")
+			return
+		}
+
+		log.Printf("Erro ao reconectar ao RabbitMQ, nova tentativa em %v: %v", delay, err)
+		delay *= 2
+		if delay > s.maxReconnectDelay {
+			delay = s.maxReconnectDelay
+		}
+	}
+}
+
+func closeSession(ch amqpChannel, conn io.Closer) {
+	if ch != nil {
+		ch.Close()
+	}
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// publishMessage publica com mandatory e aguarda a confirmação do broker.
+// Mensagens sem fila de destino são devolvidas e tratadas como falha, para
+// que o outbox tente novamente.
+func (s *RabbitMQService) publishMessage(ctx context.Context, routingKey string, msg amqp.Publishing) error {
+	s.publishMu.Lock()
+	defer s.publishMu.Unlock()
+
+	s.mu.RLock()
+	ch, returns := s.channel, s.returns
+	s.mu.RUnlock()
+	if ch == nil {
+		return ErrBrokerUnavailable
+	}
+
+	// Descarta devoluções de publicações anteriores que expiraram
+	for drained := false; !drained; {
+		select {
+		case _, ok := <-returns:
+			drained = !ok
+		default:
+			drained = true
+		}
+	}
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, exchangeName, routingKey, true, false, msg)
+	if err != nil {
+		if errors.Is(err, amqp.ErrClosed) {
+			return fmt.Errorf("%w: %v", ErrBrokerUnavailable, err)
+		}
+		return err
+	}
+	if confirmation == nil {
+		return fmt.Errorf("%w: canal fora do modo de confirmação", ErrBrokerUnavailable)
+	}
+
+	return s.waitConfirm(ctx, confirmation, returns)
+}
+
+func (s *RabbitMQService) waitConfirm(ctx context.Context, confirmation deferredConfirmation, returns <-chan amqp.Return) error {
+	timer := time.NewTimer(s.confirmTimeout)
+	defer timer.Stop()
+
+	var returned *amqp.Return
+	for {
+		select {
+		case r, ok := <-returns:
+			if !ok {
+				returns = nil
+				continue
+			}
+			returned = &r
+		case <-confirmation.Done():
+			if !confirmation.Acked() {
+				return ErrPublishNacked
+			}
+
+			// O broker envia a devolução antes da confirmação
+			if returned == nil {
+				select {
+				case r, ok := <-returns:
+					if ok {
+						returned = &r
+					}
+				default:
+				}
+			}
+			if returned != nil {
+				return fmt.Errorf("%w: %s", ErrMessageReturned, returned.ReplyText)
+			}
+			return nil
+		case <-timer.C:
+			return ErrConfirmTimeout
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *RabbitMQService) currentChannel() (amqpChannel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.channel == nil {
+		return nil, ErrBrokerUnavailable
+	}
+	return s.channel, nil
+}
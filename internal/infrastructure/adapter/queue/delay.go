@@ -0,0 +1,29 @@
+package queue
+
+import "time"
+
+type delayBucket struct {
+	queue string
+	delay time.Duration
+}
+
+// delayBuckets fica em ordem decrescente; uma notificação agendada para daqui
+// a três dias passa três vezes pela fila de 24h e depois pelas menores.
+var delayBuckets = []delayBucket{
+	{queue: "notifications.delay.24h", delay: 24 * time.Hour},
+	{queue: "notifications.delay.6h", delay: 6 * time.Hour},
+	{queue: "notifications.delay.1h", delay: time.Hour},
+	{queue: "notifications.delay.10m", delay: 10 * time.Minute},
+	{queue: "notifications.delay.1m", delay: time.Minute},
+	{queue: "notifications.delay.10s", delay: 10 * time.Second},
+	{queue: "notifications.delay.1s", delay: time.Second},
+}
+
+func delayBucketFor(remaining time.Duration) (delayBucket, bool) {
+	for _, bucket := range delayBuckets {
+		if remaining >= bucket.delay {
+			return bucket, true
+		}
+	}
+	return delayBucket{}, false
+}
@@ -0,0 +1,138 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const maxDeadLetterInspection = 1000
+
+// ListDeadLetters lê as mensagens da DLQ sem consumi-las: todas são
+// devolvidas à fila ao final da leitura.
+func (s *RabbitMQService) ListDeadLetters(ctx context.Context) ([]*entity.DeadLetter, error) {
+	deliveries, err := s.fetchDeadLetters()
+	defer requeue(deliveries)
+	if err != nil {
+		return nil, err
+	}
+
+	letters := make([]*entity.DeadLetter, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		letters = append(letters, toDeadLetter(delivery))
+	}
+	return letters, nil
+}
+
+func (s *RabbitMQService) ReplayDeadLetter(ctx context.Context, id string) error {
+	deliveries, err := s.fetchDeadLetters()
+	if err != nil {
+		requeue(deliveries)
+		return err
+	}
+
+	for i, delivery := range deliveries {
+		if deadLetterID(delivery) != id {
+			continue
+		}
+
+		var notification entity.Notification
+		if err := json.Unmarshal(delivery.Body, &notification); err != nil {
+			requeue(deliveries)
+			return fmt.Errorf("erro ao ler notificação da DLQ: %w", err)
+		}
+
+		// Uma nova rodada de tentativas começa, mantendo o histórico das anteriores
+		headers := amqp.Table{}
+		for k, v := range delivery.Headers {
+			headers[k] = v
+		}
+		delete(headers, headerRetryCount)
+		delete(headers, headerDeadAt)
+
+		if err := s.publish(ctx, delivery.Body, headers, notification.ScheduledFor); err != nil {
+			requeue(deliveries)
+			return err
+		}
+
+		delivery.Ack(false)
+		requeue(append(deliveries[:i:i], deliveries[i+1:]...))
+		return nil
+	}
+
+	requeue(deliveries)
+	return handler.ErrNotFound
+}
+
+func (s *RabbitMQService) PurgeDeadLetters(ctx context.Context) (int, error) {
+	ch, err := s.currentChannel()
+	if err != nil {
+		return 0, err
+	}
+	return ch.QueuePurge(dlqQueue, false)
+}
+
+func (s *RabbitMQService) fetchDeadLetters() ([]amqp.Delivery, error) {
+	ch, err := s.currentChannel()
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []amqp.Delivery
+	for len(deliveries) < maxDeadLetterInspection {
+		delivery, ok, err := ch.Get(dlqQueue, false)
+		if err != nil {
+			return deliveries, fmt.Errorf("erro ao ler DLQ: %w", err)
+		}
+		if !ok {
+			break
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func requeue(deliveries []amqp.Delivery) {
+	for _, delivery := range deliveries {
+		delivery.Nack(false, true)
+	}
+}
+
+func toDeadLetter(delivery amqp.Delivery) *entity.DeadLetter {
+	letter := &entity.DeadLetter{
+		ID:       deadLetterID(delivery),
+		Attempts: attemptsFromHeaders(delivery.Headers),
+		Payload:  delivery.Body,
+	}
+	letter.LastError, _ = delivery.Headers[headerLastError].(string)
+	letter.DeadAt, _ = delivery.Headers[headerDeadAt].(time.Time)
+
+	var notification entity.Notification
+	if err := json.Unmarshal(delivery.Body, &notification); err == nil {
+		letter.NotificationID = notification.ID
+		letter.UserID = notification.UserID
+		letter.LocationID = notification.LocationID
+		letter.ScheduledFor = notification.ScheduledFor
+	}
+
+	return letter
+}
+
+// deadLetterID usa o MessageId atribuído ao enviar para a DLQ e, para
+// mensagens antigas sem ele, o ID da notificação.
+func deadLetterID(delivery amqp.Delivery) string {
+	if delivery.MessageId != "" {
+		return delivery.MessageId
+	}
+
+	var notification entity.Notification
+	if err := json.Unmarshal(delivery.Body, &notification); err != nil {
+		return ""
+	}
+	return notification.ID.String()
+}
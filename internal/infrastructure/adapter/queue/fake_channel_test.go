@@ -0,0 +1,426 @@
+package queue
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// fakeChannel simula em memória o roteamento direto do exchange, o TTL das
+// filas e o dead-letter, com um relógio controlado pelo teste.
+type fakeChannel struct {
+	mu        sync.Mutex
+	now       time.Time
+	queues    map[string]*fakeQueue
+	published []string
+	unacked   map[uint64]unackedMessage
+	nextTag   uint64
+	acks      int
+	rejects   int
+
+	dials         int
+	prefetch      int
+	consumerTags  map[string]chan amqp.Delivery
+	closed        bool
+	confirming    bool
+	publishTag    uint64
+	nackPublishes bool
+	dropConfirms  bool
+	publishErrs   []error
+	pending       map[uint64]*fakeConfirmation
+	returns       []chan amqp.Return
+	closeNotify   []chan *amqp.Error
+}
+
+// fakeConfirmation imita amqp.DeferredConfirmation: é resolvida pela
+// confirmação do broker ou negada quando o canal cai.
+type fakeConfirmation struct {
+	tag  uint64
+	done chan struct{}
+	ack  bool
+}
+
+func (f *fakeConfirmation) Done() <-chan struct{} { return f.done }
+
+func (f *fakeConfirmation) Acked() bool {
+	select {
+	case <-f.done:
+		return f.ack
+	default:
+		return false
+	}
+}
+
+func (f *fakeConfirmation) resolve(ack bool) {
+	f.ack = ack
+	close(f.done)
+}
+
+type unackedMessage struct {
+	queue      string
+	publishing amqp.Publishing
+}
+
+type fakeQueue struct {
+	args      amqp.Table
+	messages  []fakeMessage
+	consumers []chan amqp.Delivery
+}
+
+type fakeMessage struct {
+	publishing amqp.Publishing
+	enqueuedAt time.Time
+}
+
+func newFakeChannel(now time.Time) *fakeChannel {
+	return &fakeChannel{
+		now:          now,
+		queues:       make(map[string]*fakeQueue),
+		unacked:      make(map[uint64]unackedMessage),
+		consumerTags: make(map[string]chan amqp.Delivery),
+		pending:      make(map[uint64]*fakeConfirmation),
+	}
+}
+
+func (c *fakeChannel) clock() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// dial simula uma nova conexão com o mesmo broker: as filas e mensagens
+// sobrevivem, mas os consumidores e notificações do canal anterior não.
+func (c *fakeChannel) dial() (amqpChannel, io.Closer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dials++
+	c.closed = false
+	c.confirming = false
+	c.publishTag = 0
+	c.pending = make(map[uint64]*fakeConfirmation)
+	c.returns = nil
+	c.closeNotify = nil
+	return c, nil, nil
+}
+
+// Kill derruba a conexão como em um reinício do broker: os consumidores são
+// encerrados e as mensagens não confirmadas voltam para a fila.
+func (c *fakeChannel) Kill() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	for _, q := range c.queues {
+		for _, consumer := range q.consumers {
+			close(consumer)
+		}
+		q.consumers = nil
+	}
+	c.consumerTags = make(map[string]chan amqp.Delivery)
+	for tag, msg := range c.unacked {
+		q := c.queues[msg.queue]
+		q.messages = append(q.messages, fakeMessage{publishing: msg.publishing, enqueuedAt: c.now})
+		delete(c.unacked, tag)
+	}
+	for _, ch := range c.closeNotify {
+		ch <- amqp.ErrClosed
+		close(ch)
+	}
+	for tag, confirmation := range c.pending {
+		confirmation.resolve(false)
+		delete(c.pending, tag)
+	}
+	for _, ch := range c.returns {
+		close(ch)
+	}
+	c.closeNotify = nil
+	c.returns = nil
+}
+
+func (c *fakeChannel) Confirm(noWait bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.confirming = true
+	return nil
+}
+
+func (c *fakeChannel) NotifyReturn(ret chan amqp.Return) chan amqp.Return {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.returns = append(c.returns, ret)
+	return ret
+}
+
+func (c *fakeChannel) NotifyClose(closed chan *amqp.Error) chan *amqp.Error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closeNotify = append(c.closeNotify, closed)
+	return closed
+}
+
+func (c *fakeChannel) Consumers(queue string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.queues[queue].consumers)
+}
+
+func (c *fakeChannel) ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error {
+	return nil
+}
+
+func (c *fakeChannel) QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if q, ok := c.queues[name]; ok {
+		return amqp.Queue{Name: name, Messages: len(q.messages)}, nil
+	}
+	c.queues[name] = &fakeQueue{args: args}
+	return amqp.Queue{Name: name}, nil
+}
+
+func (c *fakeChannel) QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error {
+	return nil
+}
+
+// PublishWithDeferredConfirmWithContext numera as publicações como o
+// broker: uma publicação que falha não consome delivery tag.
+func (c *fakeChannel) PublishWithDeferredConfirmWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) (deferredConfirmation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, amqp.ErrClosed
+	}
+	if len(c.publishErrs) > 0 {
+		err := c.publishErrs[0]
+		c.publishErrs = c.publishErrs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.publish(key, mandatory, msg)
+	if !c.confirming {
+		return nil, nil
+	}
+
+	c.publishTag++
+	confirmation := &fakeConfirmation{tag: c.publishTag, done: make(chan struct{})}
+	if c.dropConfirms {
+		c.pending[confirmation.tag] = confirmation
+	} else {
+		confirmation.resolve(!c.nackPublishes)
+	}
+	return confirmation, nil
+}
+
+// PublishWithContext publica sem confirmação; usado pelos testes para
+// preparar mensagens diretamente nas filas.
+func (c *fakeChannel) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return amqp.ErrClosed
+	}
+	c.publish(key, mandatory, msg)
+	return nil
+}
+
+func (c *fakeChannel) publish(key string, mandatory bool, msg amqp.Publishing) {
+	c.published = append(c.published, key)
+	routed := c.route(key, msg)
+
+	if mandatory && !routed {
+		for _, ch := range c.returns {
+			ch <- amqp.Return{ReplyCode: amqp.NoRoute, ReplyText: "NO_ROUTE", RoutingKey: key}
+		}
+	}
+}
+
+func (c *fakeChannel) route(key string, msg amqp.Publishing) bool {
+	q, ok := c.queues[key]
+	if !ok {
+		return false
+	}
+
+	if len(q.consumers) > 0 {
+		q.consumers[0] <- c.deliver(key, msg)
+		return true
+	}
+
+	q.messages = append(q.messages, fakeMessage{publishing: msg, enqueuedAt: c.now})
+	return true
+}
+
+func (c *fakeChannel) deliver(queue string, msg amqp.Publishing) amqp.Delivery {
+	c.nextTag++
+	c.unacked[c.nextTag] = unackedMessage{queue: queue, publishing: msg}
+	return amqp.Delivery{
+		Acknowledger: c,
+		DeliveryTag:  c.nextTag,
+		MessageId:    msg.MessageId,
+		Headers:      msg.Headers,
+		Body:         msg.Body,
+		RoutingKey:   queue,
+	}
+}
+
+func (c *fakeChannel) Get(queue string, autoAck bool) (amqp.Delivery, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	q := c.queues[queue]
+	if len(q.messages) == 0 {
+		return amqp.Delivery{}, false, nil
+	}
+
+	msg := q.messages[0]
+	q.messages = q.messages[1:]
+	return c.deliver(queue, msg.publishing), true, nil
+}
+
+func (c *fakeChannel) QueuePurge(name string, noWait bool) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	purged := len(c.queues[name].messages)
+	c.queues[name].messages = nil
+	return purged, nil
+}
+
+func (c *fakeChannel) QueueDelete(name string, ifUnused, ifEmpty, noWait bool) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	q, ok := c.queues[name]
+	if !ok {
+		return 0, nil
+	}
+	if ifEmpty && len(q.messages) > 0 {
+		return 0, &amqp.Error{Code: amqp.PreconditionFailed, Reason: "PRECONDITION_FAILED - queue not empty"}
+	}
+	delete(c.queues, name)
+	return len(q.messages), nil
+}
+
+func (c *fakeChannel) Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	deliveries := make(chan amqp.Delivery, 100)
+	c.queues[queue].consumers = append(c.queues[queue].consumers, deliveries)
+	c.consumerTags[consumer] = deliveries
+
+	// Entrega o que já estava na fila
+	q := c.queues[queue]
+	for _, m := range q.messages {
+		deliveries <- c.deliver(queue, m.publishing)
+	}
+	q.messages = nil
+	return deliveries, nil
+}
+
+func (c *fakeChannel) Qos(prefetchCount, prefetchSize int, global bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prefetch = prefetchCount
+	return nil
+}
+
+func (c *fakeChannel) Cancel(consumer string, noWait bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deliveries, ok := c.consumerTags[consumer]
+	if !ok {
+		return nil
+	}
+	delete(c.consumerTags, consumer)
+
+	for _, q := range c.queues {
+		for i, ch := range q.consumers {
+			if ch == deliveries {
+				q.consumers = append(q.consumers[:i], q.consumers[i+1:]...)
+				break
+			}
+		}
+	}
+	close(deliveries)
+	return nil
+}
+
+func (c *fakeChannel) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	for _, ch := range c.closeNotify {
+		close(ch)
+	}
+	c.closeNotify = nil
+	return nil
+}
+
+// Advance avança o relógio e move para o dead-letter as mensagens cujo TTL expirou.
+func (c *fakeChannel) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+
+	for _, q := range c.queues {
+		ttl, ok := q.args["x-message-ttl"].(int64)
+		if !ok {
+			continue
+		}
+
+		var remaining []fakeMessage
+		for _, m := range q.messages {
+			if c.now.Sub(m.enqueuedAt) >= time.Duration(ttl)*time.Millisecond {
+				c.route(q.args["x-dead-letter-routing-key"].(string), m.publishing)
+				continue
+			}
+			remaining = append(remaining, m)
+		}
+		q.messages = remaining
+	}
+}
+
+func (c *fakeChannel) Held(queue string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.queues[queue].messages)
+}
+
+func (c *fakeChannel) Ack(tag uint64, multiple bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.unacked, tag)
+	c.acks++
+	return nil
+}
+
+func (c *fakeChannel) Nack(tag uint64, multiple, requeue bool) error {
+	return c.Reject(tag, requeue)
+}
+
+func (c *fakeChannel) Reject(tag uint64, requeue bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	msg := c.unacked[tag]
+	delete(c.unacked, tag)
+	c.rejects++
+
+	if requeue {
+		q := c.queues[msg.queue]
+		q.messages = append([]fakeMessage{{publishing: msg.publishing, enqueuedAt: c.now}}, q.messages...)
+	}
+	return nil
+}
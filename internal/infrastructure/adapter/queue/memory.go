@@ -0,0 +1,323 @@
+package queue
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/google/uuid"
+)
+
+var ErrQueueClosed = errors.New("fila fechada")
+
+type memoryMessage struct {
+	id          string
+	body        []byte
+	availableAt time.Time
+	retries     int
+	attempts    []entity.DeliveryAttempt
+	deadAt      time.Time
+	index       int
+}
+
+// messageHeap ordena as mensagens pendentes pelo horário em que ficam
+// disponíveis para consumo.
+type messageHeap []*memoryMessage
+
+func (h messageHeap) Len() int           { return len(h) }
+func (h messageHeap) Less(i, j int) bool { return h[i].availableAt.Before(h[j].availableAt) }
+func (h messageHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *messageHeap) Push(x interface{}) {
+	msg := x.(*memoryMessage)
+	msg.index = len(*h)
+	*h = append(*h, msg)
+}
+
+func (h *messageHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	msg := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return msg
+}
+
+// MemoryQueueService implementa a fila de notificações dentro do processo,
+// com a mesma semântica do RabbitMQ: entrega no horário agendado,
+// retentativas com backoff exponencial, DLQ e limite de consumo simultâneo.
+// As mensagens não sobrevivem a reinícios do processo; na inicialização as
+// notificações que ainda podem ser entregues são republicadas a partir do
+// banco com RequeueNotification.
+type MemoryQueueService struct {
+	mu          sync.Mutex
+	pending     messageHeap
+	deadLetters []*memoryMessage
+	ready       chan *memoryMessage
+	wake        chan struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+	concurrency int
+	retryDelays []time.Duration
+}
+
+func NewMemoryQueueService(concurrency int) *MemoryQueueService {
+	retryDelays := make([]time.Duration, 0, maxRetries)
+	for _, tier := range retryTiers[:maxRetries] {
+		retryDelays = append(retryDelays, tier.delay)
+	}
+
+	return newMemoryQueueService(concurrency, retryDelays)
+}
+
+func newMemoryQueueService(concurrency int, retryDelays []time.Duration) *MemoryQueueService {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	s := &MemoryQueueService{
+		ready:       make(chan *memoryMessage),
+		wake:        make(chan struct{}, 1),
+		done:        make(chan struct{}),
+		concurrency: concurrency,
+		retryDelays: retryDelays,
+	}
+
+	go s.dispatch()
+
+	return s
+}
+
+func (s *MemoryQueueService) PublishNotification(ctx context.Context, notification *entity.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar notificação: %w", err)
+	}
+
+	return s.enqueue(&memoryMessage{
+		body:        body,
+		availableAt: notification.ScheduledFor,
+	})
+}
+
+// RequeueNotification republica uma notificação recuperada do banco para
+// ficar disponível a partir de availableAt, descontando das retentativas as
+// tentativas já registradas.
+func (s *MemoryQueueService) RequeueNotification(ctx context.Context, notification *entity.Notification, availableAt time.Time) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar notificação: %w", err)
+	}
+
+	return s.enqueue(&memoryMessage{
+		body:        body,
+		availableAt: availableAt,
+		retries:     notification.Attempts,
+	})
+}
+
+// MaxAttempts é o número de tentativas de entrega antes de a mensagem ir
+// para a DLQ.
+func (s *MemoryQueueService) MaxAttempts() int {
+	return len(s.retryDelays) + 1
+}
+
+func (s *MemoryQueueService) enqueue(msg *memoryMessage) error {
+	select {
+	case <-s.done:
+		return ErrQueueClosed
+	default:
+	}
+
+	s.mu.Lock()
+	heap.Push(&s.pending, msg)
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// dispatch entrega aos consumidores as mensagens cujo horário chegou,
+// dormindo até a próxima mensagem pendente ou até uma nova publicação.
+func (s *MemoryQueueService) dispatch() {
+	for {
+		s.mu.Lock()
+		var next *memoryMessage
+		wait := time.Hour
+		if s.pending.Len() > 0 {
+			if until := time.Until(s.pending[0].availableAt); until <= 0 {
+				next = heap.Pop(&s.pending).(*memoryMessage)
+			} else {
+				wait = until
+			}
+		}
+		s.mu.Unlock()
+
+		if next != nil {
+			select {
+			case s.ready <- next:
+			case <-s.done:
+				return
+			}
+			continue
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-s.wake:
+			timer.Stop()
+		case <-s.done:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func (s *MemoryQueueService) ConsumeNotifications(ctx context.Context, handler func(*entity.Notification) error) error {
+	var wg sync.WaitGroup
+	for i := 0; i < s.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-s.done:
+					return
+				case msg := <-s.ready:
+					s.process(msg, handler)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return ErrQueueClosed
+}
+
+func (s *MemoryQueueService) process(msg *memoryMessage, handler func(*entity.Notification) error) {
+	var notification entity.Notification
+	if err := json.Unmarshal(msg.body, &notification); err != nil {
+		log.Printf("Descartando mensagem inválida da fila em memória: %v", err)
+		return
+	}
+
+	err := handler(&notification)
+	if err == nil {
+		return
+	}
+
+	now := time.Now()
+	msg.retries++
+	msg.attempts = append(msg.attempts, entity.DeliveryAttempt{
+		Attempt:  len(msg.attempts) + 1,
+		Error:    err.Error(),
+		FailedAt: now,
+	})
+
+	if msg.retries <= len(s.retryDelays) {
+		msg.availableAt = now.Add(s.retryDelays[msg.retries-1])
+		if err := s.enqueue(msg); err != nil {
+			log.Printf("Erro ao reagendar notificação %s: %v", notification.ID, err)
+		}
+		return
+	}
+
+	msg.id = uuid.NewString()
+	msg.deadAt = now
+
+	s.mu.Lock()
+	s.deadLetters = append(s.deadLetters, msg)
+	s.mu.Unlock()
+}
+
+func (s *MemoryQueueService) ListDeadLetters(ctx context.Context) ([]*entity.DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	letters := make([]*entity.DeadLetter, 0, len(s.deadLetters))
+	for _, msg := range s.deadLetters {
+		letters = append(letters, msg.deadLetter())
+	}
+	return letters, nil
+}
+
+func (s *MemoryQueueService) ReplayDeadLetter(ctx context.Context, id string) error {
+	s.mu.Lock()
+	var msg *memoryMessage
+	for i, letter := range s.deadLetters {
+		if letter.id == id {
+			msg = letter
+			s.deadLetters = append(s.deadLetters[:i], s.deadLetters[i+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if msg == nil {
+		return handler.ErrNotFound
+	}
+
+	// Uma nova rodada de tentativas começa, mantendo o histórico das anteriores
+	msg.retries = 0
+	msg.deadAt = time.Time{}
+	msg.availableAt = time.Now()
+	return s.enqueue(msg)
+}
+
+func (s *MemoryQueueService) PurgeDeadLetters(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := len(s.deadLetters)
+	s.deadLetters = nil
+	return purged, nil
+}
+
+func (s *MemoryQueueService) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	return nil
+}
+
+func (m *memoryMessage) deadLetter() *entity.DeadLetter {
+	letter := &entity.DeadLetter{
+		ID:       m.id,
+		Attempts: append([]entity.DeliveryAttempt(nil), m.attempts...),
+		DeadAt:   m.deadAt,
+		Payload:  m.body,
+	}
+	if len(m.attempts) > 0 {
+		letter.LastError = m.attempts[len(m.attempts)-1].Error
+	}
+
+	var notification entity.Notification
+	if err := json.Unmarshal(m.body, &notification); err == nil {
+		letter.NotificationID = notification.ID
+		letter.UserID = notification.UserID
+		letter.LocationID = notification.LocationID
+		letter.ScheduledFor = notification.ScheduledFor
+	}
+
+	return letter
+}
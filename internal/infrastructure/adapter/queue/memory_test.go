@@ -0,0 +1,263 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryQueueService_ScheduledDelivery(t *testing.T) {
+	service := newMemoryQueueService(1, nil)
+	defer service.Close()
+
+	delivered := make(chan *entity.Notification, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go service.ConsumeNotifications(ctx, func(n *entity.Notification) error {
+		delivered <- n
+		return nil
+	})
+
+	later := &entity.Notification{ID: uuid.New(), ScheduledFor: time.Now().Add(80 * time.Millisecond)}
+	now := &entity.Notification{ID: uuid.New(), ScheduledFor: time.Now()}
+	assert.NoError(t, service.PublishNotification(ctx, later))
+	assert.NoError(t, service.PublishNotification(ctx, now))
+
+	select {
+	case n := <-delivered:
+		assert.Equal(t, now.ID, n.ID)
+	case <-time.After(time.Second):
+		t.Fatal("notificação vencida não foi entregue")
+	}
+
+	assertNotDelivered(t, delivered)
+
+	select {
+	case n := <-delivered:
+		assert.Equal(t, later.ID, n.ID)
+		assert.False(t, time.Now().Before(later.ScheduledFor))
+	case <-time.After(time.Second):
+		t.Fatal("notificação agendada não foi entregue")
+	}
+}
+
+func TestMemoryQueueService_RetryAndDeadLetter(t *testing.T) {
+	service := newMemoryQueueService(1, []time.Duration{
+		5 * time.Millisecond,
+		10 * time.Millisecond,
+		20 * time.Millisecond,
+	})
+	defer service.Close()
+
+	var failing atomic.Bool
+	failing.Store(true)
+	attempts := make(chan *entity.Notification, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go service.ConsumeNotifications(ctx, func(n *entity.Notification) error {
+		attempts <- n
+		if failing.Load() {
+			return errors.New("webhook indisponível")
+		}
+		return nil
+	})
+
+	notification := &entity.Notification{ID: uuid.New(), UserID: uuid.New(), ScheduledFor: time.Now()}
+	assert.NoError(t, service.PublishNotification(ctx, notification))
+
+	var letters []*entity.DeadLetter
+	assert.Eventually(t, func() bool {
+		letters, _ = service.ListDeadLetters(ctx)
+		return len(letters) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Len(t, attempts, 4)
+
+	letter := letters[0]
+	assert.NotEmpty(t, letter.ID)
+	assert.Equal(t, notification.ID, letter.NotificationID)
+	assert.Equal(t, notification.UserID, letter.UserID)
+	assert.Equal(t, "webhook indisponível", letter.LastError)
+	assert.Len(t, letter.Attempts, 4)
+	assert.False(t, letter.DeadAt.IsZero())
+
+	t.Run("reprocessar mensagem inexistente", func(t *testing.T) {
+		assert.ErrorIs(t, service.ReplayDeadLetter(ctx, "inexistente"), handler.ErrNotFound)
+	})
+
+	t.Run("reprocessar devolve para a fila", func(t *testing.T) {
+		for len(attempts) > 0 {
+			<-attempts
+		}
+		failing.Store(false)
+
+		assert.NoError(t, service.ReplayDeadLetter(ctx, letter.ID))
+		select {
+		case n := <-attempts:
+			assert.Equal(t, notification.ID, n.ID)
+		case <-time.After(time.Second):
+			t.Fatal("notificação não foi reprocessada")
+		}
+
+		letters, err := service.ListDeadLetters(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, letters)
+	})
+}
+
+func TestMemoryQueueService_PurgeDeadLetters(t *testing.T) {
+	service := newMemoryQueueService(1, nil)
+	defer service.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go service.ConsumeNotifications(ctx, func(n *entity.Notification) error {
+		return errors.New("falha")
+	})
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, service.PublishNotification(ctx, &entity.Notification{ID: uuid.New(), ScheduledFor: time.Now()}))
+	}
+
+	assert.Eventually(t, func() bool {
+		letters, _ := service.ListDeadLetters(ctx)
+		return len(letters) == 3
+	}, time.Second, 5*time.Millisecond)
+
+	purged, err := service.PurgeDeadLetters(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, purged)
+
+	letters, _ := service.ListDeadLetters(ctx)
+	assert.Empty(t, letters)
+}
+
+func TestMemoryQueueService_Concurrency(t *testing.T) {
+	const concurrency = 3
+	service := newMemoryQueueService(concurrency, nil)
+	defer service.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		inFlight  int
+		maxFlight int
+		processed sync.WaitGroup
+	)
+	arrived := make(chan struct{}, 10)
+	release := make(chan struct{})
+
+	go service.ConsumeNotifications(ctx, func(n *entity.Notification) error {
+		defer processed.Done()
+		mu.Lock()
+		inFlight++
+		if inFlight > maxFlight {
+			maxFlight = inFlight
+		}
+		mu.Unlock()
+
+		// Os consumidores ficam presos até o teste liberá-los, então todos
+		// os disponíveis estão ocupados ao mesmo tempo
+		arrived <- struct{}{}
+		<-release
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return nil
+	})
+
+	processed.Add(10)
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, service.PublishNotification(ctx, &entity.Notification{ID: uuid.New(), ScheduledFor: time.Now()}))
+	}
+
+	for i := 0; i < concurrency; i++ {
+		select {
+		case <-arrived:
+		case <-time.After(time.Second):
+			t.Fatalf("apenas %d entregas em paralelo", i)
+		}
+	}
+	assertNotArrived(t, arrived)
+	close(release)
+	processed.Wait()
+
+	assert.Equal(t, concurrency, maxFlight)
+}
+
+func TestMemoryQueueService_RequeueNotification(t *testing.T) {
+	service := newMemoryQueueService(1, []time.Duration{
+		5 * time.Millisecond,
+		5 * time.Millisecond,
+		5 * time.Millisecond,
+	})
+	defer service.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	attempts := make(chan *entity.Notification, 10)
+	go service.ConsumeNotifications(ctx, func(n *entity.Notification) error {
+		attempts <- n
+		return errors.New("webhook indisponível")
+	})
+
+	assert.Equal(t, 4, service.MaxAttempts())
+
+	// Duas das quatro tentativas já foram feitas antes do reinício
+	notification := &entity.Notification{ID: uuid.New(), Attempts: 2, ScheduledFor: time.Now().Add(-time.Hour)}
+	assert.NoError(t, service.RequeueNotification(ctx, notification, time.Now().Add(50*time.Millisecond)))
+	assertNotDelivered(t, attempts)
+
+	assert.Eventually(t, func() bool {
+		letters, _ := service.ListDeadLetters(ctx)
+		return len(letters) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Len(t, attempts, 2)
+}
+
+func TestMemoryQueueService_Close(t *testing.T) {
+	service := newMemoryQueueService(1, nil)
+
+	consumed := make(chan error, 1)
+	go func() {
+		consumed <- service.ConsumeNotifications(context.Background(), func(n *entity.Notification) error {
+			return nil
+		})
+	}()
+
+	assert.NoError(t, service.Close())
+	assert.NoError(t, service.Close())
+
+	select {
+	case err := <-consumed:
+		assert.ErrorIs(t, err, ErrQueueClosed)
+	case <-time.After(time.Second):
+		t.Fatal("consumidor não foi encerrado")
+	}
+
+	err := service.PublishNotification(context.Background(), &entity.Notification{ID: uuid.New()})
+	assert.ErrorIs(t, err, ErrQueueClosed)
+}
+
+func assertNotArrived(t *testing.T, arrived chan struct{}) {
+	t.Helper()
+	select {
+	case <-arrived:
+		t.Fatal("entrega além do limite de concorrência")
+	case <-time.After(30 * time.Millisecond):
+	}
+}
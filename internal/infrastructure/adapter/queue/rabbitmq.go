@@ -4,51 +4,109 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"sync"
 	"time"
 
 	"weather-notification/internal/domain/entity"
 
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 const (
 	exchangeName = "notifications"
 	queueName    = "notifications.send"
-	retryQueue   = "notifications.retry"
 	dlqQueue     = "notifications.dlq"
+	maxRetries   = 3
 )
 
+// amqpChannel é o subconjunto de *amqp.Channel usado pelo serviço, o que
+// permite substituir o broker por uma implementação em memória nos testes.
+type amqpChannel interface {
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+	PublishWithDeferredConfirmWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) (deferredConfirmation, error)
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+	Get(queue string, autoAck bool) (amqp.Delivery, bool, error)
+	QueuePurge(name string, noWait bool) (int, error)
+	QueueDelete(name string, ifUnused, ifEmpty, noWait bool) (int, error)
+	Confirm(noWait bool) error
+	NotifyReturn(c chan amqp.Return) chan amqp.Return
+	NotifyClose(c chan *amqp.Error) chan *amqp.Error
+	Qos(prefetchCount, prefetchSize int, global bool) error
+	Cancel(consumer string, noWait bool) error
+	Close() error
+}
+
+// ConsumerConfig define quantas notificações são processadas em paralelo e
+// quantas mensagens o broker entrega antes das confirmações (prefetch).
+type ConsumerConfig struct {
+	Concurrency int
+	Prefetch    int
+}
+
+type consumer struct {
+	ctx     context.Context
+	handler func(*entity.Notification) error
+
+	mu      sync.Mutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
 type RabbitMQService struct {
-	conn    *amqp.Connection
-	channel *amqp.Channel
+	dial              dialFunc
+	now               func() time.Time
+	config            ConsumerConfig
+	confirmTimeout    time.Duration
+	reconnectDelay    time.Duration
+	maxReconnectDelay time.Duration
+
+	mu        sync.RWMutex
+	channel   amqpChannel
+	conn      io.Closer
+	returns   chan amqp.Return
+	consumers []*consumer
+	closed    bool
+	done      chan struct{}
+
+	publishMu sync.Mutex
 }
 
-func NewRabbitMQService(url string) (*RabbitMQService, error) {
-	conn, err := amqp.Dial(url)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao conectar ao RabbitMQ: %w", err)
-	}
+func NewRabbitMQService(url string, config ConsumerConfig) (*RabbitMQService, error) {
+	return newRabbitMQService(dialURL(url), time.Now, config)
+}
 
-	ch, err := conn.Channel()
-	if err != nil {
-		return nil, fmt.Errorf("erro ao criar canal: %w", err)
+func newRabbitMQService(dial dialFunc, now func() time.Time, config ConsumerConfig) (*RabbitMQService, error) {
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+	if config.Prefetch < config.Concurrency {
+		config.Prefetch = config.Concurrency
 	}
 
 	service := &RabbitMQService{
-		conn:    conn,
-		channel: ch,
+		dial:              dial,
+		now:               now,
+		config:            config,
+		confirmTimeout:    defaultConfirmTimeout,
+		reconnectDelay:    defaultReconnectDelay,
+		maxReconnectDelay: defaultMaxReconnectDelay,
+		done:              make(chan struct{}),
 	}
 
-	if err := service.setup(); err != nil {
+	if err := service.connect(); err != nil {
 		return nil, err
 	}
 
 	return service, nil
 }
 
-func (s *RabbitMQService) setup() error {
-	err := s.channel.ExchangeDeclare(
+func (s *RabbitMQService) setup(ch amqpChannel) error {
+	err := ch.ExchangeDeclare(
 		exchangeName,
 		"direct",
 		true,
@@ -61,9 +119,9 @@ func (s *RabbitMQService) setup() error {
 		return fmt.Errorf("erro ao declarar exchange: %w", err)
 	}
 
-	queues := []string{queueName, retryQueue, dlqQueue}
+	queues := []string{queueName, dlqQueue}
 	for _, q := range queues {
-		_, err = s.channel.QueueDeclare(
+		_, err = ch.QueueDeclare(
 			q,
 			true,
 			false,
@@ -75,7 +133,7 @@ func (s *RabbitMQService) setup() error {
 			return fmt.Errorf("erro ao declarar fila %s: %w", q, err)
 		}
 
-		err = s.channel.QueueBind(
+		err = ch.QueueBind(
 			q,
 			q,
 			exchangeName,
@@ -87,6 +145,36 @@ func (s *RabbitMQService) setup() error {
 		}
 	}
 
+	holdingQueues := append(append([]delayBucket{}, delayBuckets...), retryTiers...)
+	for _, bucket := range holdingQueues {
+		_, err = ch.QueueDeclare(
+			bucket.queue,
+			true,
+			false,
+			false,
+			false,
+			amqp.Table{
+				"x-message-ttl":             bucket.delay.Milliseconds(),
+				"x-dead-letter-exchange":    exchangeName,
+				"x-dead-letter-routing-key": queueName,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao declarar fila %s: %w", bucket.queue, err)
+		}
+
+		err = ch.QueueBind(
+			bucket.queue,
+			bucket.queue,
+			exchangeName,
+			false,
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao bind fila %s: %w", bucket.queue, err)
+		}
+	}
+
 	return nil
 }
 
@@ -96,20 +184,28 @@ func (s *RabbitMQService) PublishNotification(ctx context.Context, notification
 	if err != nil {
 		return fmt.Errorf("erro ao serializar notificação: %w", err)
 	}
-	log.Printf("Publicando notificação no RabbitMQ para fila %s", queueName)
 
-	err = s.channel.PublishWithContext(ctx,
-		exchangeName,
-		queueName,
-		true,
-		false,
-		amqp.Publishing{
-			DeliveryMode: amqp.Persistent,
-			Timestamp:    time.Now(),
-			ContentType:  "application/json",
-			Body:         data,
-		},
-	)
+	return s.publish(ctx, data, nil, notification.ScheduledFor)
+}
+
+// publish envia a mensagem para a fila de envio quando já está vencida ou
+// para a fila de espera cujo TTL mais se aproxima do tempo restante. Ao
+// expirar, a fila de espera devolve a mensagem para a fila de envio, que a
+// reencaminha para um intervalo menor até o horário agendado.
+func (s *RabbitMQService) publish(ctx context.Context, body []byte, headers amqp.Table, scheduledFor time.Time) error {
+	routingKey := queueName
+	if bucket, ok := delayBucketFor(scheduledFor.Sub(s.now())); ok {
+		routingKey = bucket.queue
+	}
+	log.Printf("Publicando notificação no RabbitMQ para fila %s", routingKey)
+
+	err := s.publishMessage(ctx, routingKey, amqp.Publishing{
+		Headers:      headers,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    s.now(),
+		ContentType:  "application/json",
+		Body:         body,
+	})
 
 	if err != nil {
 		log.Printf("Erro ao publicar no RabbitMQ: %v", err)
@@ -120,113 +216,200 @@ func (s *RabbitMQService) PublishNotification(ctx context.Context, notification
 	return nil
 }
 
+// ConsumeNotifications registra o consumidor, que é reassinado a cada
+// reconexão com o broker, e bloqueia até o contexto ser cancelado. No
+// cancelamento as entregas em andamento são concluídas e confirmadas antes
+// do retorno.
 func (s *RabbitMQService) ConsumeNotifications(ctx context.Context, handler func(*entity.Notification) error) error {
-	msgs, err := s.channel.Consume(queueName, "", false, false, false, false, nil)
-	if err != nil {
-		return err
-	}
+	c := &consumer{ctx: ctx, handler: handler}
 
-	retryMsgs, err := s.channel.Consume(retryQueue, "", false, false, false, false, nil)
-	if err != nil {
-		return err
-	}
+	s.mu.Lock()
+	s.consumers = append(s.consumers, c)
+	ch := s.channel
+	s.mu.Unlock()
 
-	forever := make(chan bool)
-	go s.processMessages(msgs, handler)
-	go s.processMessages(retryMsgs, handler)
+	if ch != nil {
+		if err := s.subscribe(ch, c); err != nil {
+			s.stop(c)
+			return err
+		}
+	}
 
 	select {
 	case <-ctx.Done():
+		log.Printf("Aguardando entregas em andamento...")
+		s.stop(c)
 		return ctx.Err()
-	case <-forever:
+	case <-s.done:
+		s.stop(c)
 		return nil
 	}
 }
 
-func (s *RabbitMQService) processMessages(msgs <-chan amqp.Delivery, handler func(*entity.Notification) error) {
-	for msg := range msgs {
-		var notification entity.Notification
-		if err := json.Unmarshal(msg.Body, &notification); err != nil {
-			msg.Reject(false)
-			continue
-		}
+func (s *RabbitMQService) subscribe(ch amqpChannel, c *consumer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return nil
+	}
 
-		if time.Now().Before(notification.ScheduledFor) {
-			msg.Reject(true)
-			continue
-		}
+	tag := "notification-worker-" + uuid.NewString()
+	msgs, err := ch.Consume(queueName, tag, false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao consumir fila %s: %w", queueName, err)
+	}
 
-		err := handler(&notification)
-		if err != nil {
-			s.handleError(msg)
-			msg.Reject(false)
-			continue
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		s.processMessages(c.ctx, ch, tag, msgs, c.handler)
+	}()
+	return nil
+}
+
+// stop remove o consumidor, impedindo novas assinaturas, e espera o fim
+// das entregas em andamento.
+func (s *RabbitMQService) stop(c *consumer) {
+	s.mu.Lock()
+	for i, registered := range s.consumers {
+		if registered == c {
+			s.consumers = append(s.consumers[:i], s.consumers[i+1:]...)
+			break
 		}
+	}
+	s.mu.Unlock()
 
-		msg.Ack(false)
+	c.mu.Lock()
+	c.stopped = true
+	c.mu.Unlock()
+
+	c.wg.Wait()
+}
+
+// processMessages distribui as entregas entre config.Concurrency workers.
+// Quando o contexto é cancelado a assinatura é encerrada, cada worker
+// termina a notificação atual e as mensagens recebidas e ainda não
+// iniciadas voltam para a fila.
+func (s *RabbitMQService) processMessages(ctx context.Context, ch amqpChannel, tag string, msgs <-chan amqp.Delivery, handler func(*entity.Notification) error) {
+	var wg sync.WaitGroup
+	for i := 0; i < s.config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				select {
+				case <-ctx.Done():
+					return
+				case msg, ok := <-msgs:
+					if !ok {
+						return
+					}
+					s.processMessage(ctx, msg, handler)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() == nil {
+		return
+	}
+
+	if err := ch.Cancel(tag, false); err != nil {
+		return
+	}
+	for msg := range msgs {
+		msg.Nack(false, true)
 	}
 }
 
-func (s *RabbitMQService) handleError(msg amqp.Delivery) {
-	retryCount := 0
-	if val, ok := msg.Headers["x-retry-count"]; ok {
-		switch v := val.(type) {
-		case int:
-			retryCount = v
-		case int32:
-			retryCount = int(v)
-		case int64:
-			retryCount = int(v)
-		case float64:
-			retryCount = int(v)
-		default:
-			log.Printf("Tipo inesperado para x-retry-count: %T\n", v)
+func (s *RabbitMQService) processMessage(ctx context.Context, msg amqp.Delivery, handler func(*entity.Notification) error) {
+	var notification entity.Notification
+	if err := json.Unmarshal(msg.Body, &notification); err != nil {
+		msg.Reject(false)
+		return
+	}
+
+	// Uma entrega iniciada é concluída mesmo durante o desligamento
+	deliveryCtx := context.WithoutCancel(ctx)
+
+	if _, wait := delayBucketFor(notification.ScheduledFor.Sub(s.now())); wait {
+		if err := s.publish(deliveryCtx, msg.Body, msg.Headers, notification.ScheduledFor); err != nil {
+			msg.Reject(true)
+			return
 		}
+		msg.Ack(false)
+		return
 	}
 
-	if count, ok := msg.Headers["x-retry-count"].(float64); ok {
-		retryCount = int(count)
+	// Restam menos que o menor intervalo de espera
+	if remaining := notification.ScheduledFor.Sub(s.now()); remaining > 0 {
+		select {
+		case <-ctx.Done():
+			msg.Reject(true)
+			return
+		case <-time.After(remaining):
+		}
 	}
 
-	if retryCount >= 3 {
-		s.publishToDLQ(msg.Body)
-		return
+	if err := handler(&notification); err != nil {
+		if err := s.handleError(deliveryCtx, msg, err); err != nil {
+			log.Printf("Erro ao reagendar notificação %s: %v", notification.ID, err)
+			msg.Reject(true)
+			return
+		}
 	}
 
-	s.channel.Publish(
-		exchangeName,
-		retryQueue,
-		false,
-		false,
-		amqp.Publishing{
-			Headers: amqp.Table{
-				"x-retry-count": retryCount + 1,
-			},
-			Body:         msg.Body,
-			DeliveryMode: amqp.Persistent,
-		},
-	)
+	msg.Ack(false)
 }
 
-func (s *RabbitMQService) publishToDLQ(body []byte) {
-	s.channel.Publish(
-		exchangeName,
-		dlqQueue,
-		false,
-		false,
-		amqp.Publishing{
-			Body:         body,
-			DeliveryMode: amqp.Persistent,
-		},
-	)
+// handleError encaminha a mensagem para a próxima fila de retentativa, cujo
+// TTL a devolve para a fila de envio, ou para a DLQ ao esgotar as tentativas.
+func (s *RabbitMQService) handleError(ctx context.Context, msg amqp.Delivery, cause error) error {
+	attempt := retryCount(msg.Headers)
+	headers := withFailedAttempt(msg.Headers, cause, s.now())
+
+	routingKey := dlqQueue
+	publishing := amqp.Publishing{
+		Headers:      headers,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    s.now(),
+		ContentType:  "application/json",
+		Body:         msg.Body,
+	}
+
+	if attempt < maxRetries && attempt < len(retryTiers) {
+		routingKey = retryTiers[attempt].queue
+	} else {
+		publishing.MessageId = uuid.NewString()
+		publishing.Headers[headerDeadAt] = s.now()
+	}
+
+	return s.publishMessage(ctx, routingKey, publishing)
 }
 
 func (s *RabbitMQService) Close() error {
-	if err := s.channel.Close(); err != nil {
-		return fmt.Errorf("erro ao fechar canal: %w", err)
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
 	}
-	if err := s.conn.Close(); err != nil {
-		return fmt.Errorf("erro ao fechar conexão: %w", err)
+	s.closed = true
+	close(s.done)
+	ch, conn := s.channel, s.conn
+	s.channel = nil
+	s.conn = nil
+	s.mu.Unlock()
+
+	if ch != nil {
+		if err := ch.Close(); err != nil {
+			return fmt.Errorf("erro ao fechar canal: %w", err)
+		}
+	}
+	if conn != nil {
+		if err := conn.Close(); err != nil {
+			return fmt.Errorf("erro ao fechar conexão: %w", err)
+		}
 	}
 	return nil
 }
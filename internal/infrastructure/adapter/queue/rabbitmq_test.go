@@ -0,0 +1,448 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelayBucketFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining time.Duration
+		expected  string
+		wait      bool
+	}{
+		{"três dias", 72 * time.Hour, "notifications.delay.24h", true},
+		{"duas horas", 2 * time.Hour, "notifications.delay.1h", true},
+		{"noventa segundos", 90 * time.Second, "notifications.delay.1m", true},
+		{"menos de um segundo", 500 * time.Millisecond, "", false},
+		{"já vencida", -time.Minute, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket, wait := delayBucketFor(tt.remaining)
+			assert.Equal(t, tt.wait, wait)
+			assert.Equal(t, tt.expected, bucket.queue)
+		})
+	}
+}
+
+func TestRabbitMQService_DelayedDelivery(t *testing.T) {
+	start := time.Date(2024, 2, 2, 10, 0, 0, 0, time.UTC)
+	ch := newFakeChannel(start)
+
+	service, err := newRabbitMQService(ch.dial, ch.clock, ConsumerConfig{})
+	require.NoError(t, err)
+
+	delivered := make(chan *entity.Notification, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go service.ConsumeNotifications(ctx, func(n *entity.Notification) error {
+		delivered <- n
+		return nil
+	})
+	require.Eventually(t, func() bool { return ch.Consumers(queueName) == 1 }, time.Second, time.Millisecond)
+
+	notification := &entity.Notification{
+		ID:           uuid.New(),
+		Status:       entity.StatusPending,
+		ScheduledFor: start.Add(72*time.Hour + 90*time.Second),
+	}
+	require.NoError(t, service.PublishNotification(ctx, notification))
+	assert.Equal(t, 1, ch.Held("notifications.delay.24h"))
+
+	// advance avança o relógio e espera a mensagem vencida ser republicada
+	// pelo consumidor na fila de espera indicada.
+	advance := func(d time.Duration, queue string) {
+		t.Helper()
+		ch.Advance(d)
+		require.Eventually(t, func() bool { return ch.Held(queue) == 1 }, time.Second, time.Millisecond,
+			"mensagem deveria aguardar em %s", queue)
+		assert.Empty(t, delivered, "notificação entregue antes do horário")
+	}
+
+	// Nada é entregue enquanto a mensagem espera na fila de 24h
+	advance(23*time.Hour, "notifications.delay.24h")
+	advance(time.Hour, "notifications.delay.24h")
+	advance(24*time.Hour, "notifications.delay.24h")
+	advance(24*time.Hour, "notifications.delay.1m")
+	advance(time.Minute, "notifications.delay.10s")
+	advance(10*time.Second, "notifications.delay.10s")
+	advance(10*time.Second, "notifications.delay.10s")
+	ch.Advance(10 * time.Second)
+
+	select {
+	case n := <-delivered:
+		assert.Equal(t, notification.ID, n.ID)
+		assert.False(t, ch.clock().Before(notification.ScheduledFor))
+	case <-time.After(time.Second):
+		t.Fatal("notificação não foi entregue")
+	}
+
+	assert.Less(t, len(ch.published), 10, "mensagem não deve girar pelo broker")
+}
+
+func assertNotDelivered(t *testing.T, delivered chan *entity.Notification) {
+	t.Helper()
+	select {
+	case n := <-delivered:
+		t.Fatalf("notificação %s entregue antes do horário", n.ID)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestRabbitMQService_RetryAndDeadLetter(t *testing.T) {
+	start := time.Date(2024, 2, 2, 10, 0, 0, 0, time.UTC)
+	ch := newFakeChannel(start)
+
+	service, err := newRabbitMQService(ch.dial, ch.clock, ConsumerConfig{})
+	assert.NoError(t, err)
+
+	var failing atomic.Bool
+	failing.Store(true)
+	attempts := make(chan *entity.Notification, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go service.ConsumeNotifications(ctx, func(n *entity.Notification) error {
+		attempts <- n
+		if failing.Load() {
+			return errors.New("webhook indisponível")
+		}
+		return nil
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	notification := &entity.Notification{
+		ID:           uuid.New(),
+		UserID:       uuid.New(),
+		Status:       entity.StatusPending,
+		ScheduledFor: start,
+	}
+	assert.NoError(t, service.PublishNotification(ctx, notification))
+
+	advance := func(d time.Duration) {
+		ch.Advance(d)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 1, ch.Held("notifications.retry.30s"))
+
+	advance(29 * time.Second)
+	assert.Equal(t, 1, ch.Held("notifications.retry.30s"))
+	advance(time.Second)
+	assert.Equal(t, 1, ch.Held("notifications.retry.2m"))
+
+	advance(2 * time.Minute)
+	assert.Equal(t, 1, ch.Held("notifications.retry.8m"))
+
+	advance(8 * time.Minute)
+	assert.Equal(t, 1, ch.Held(dlqQueue))
+	assert.Len(t, attempts, 4)
+
+	t.Run("listar não consome a DLQ", func(t *testing.T) {
+		letters, err := service.ListDeadLetters(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, letters, 1)
+		assert.Equal(t, 1, ch.Held(dlqQueue))
+
+		letter := letters[0]
+		assert.NotEmpty(t, letter.ID)
+		assert.Equal(t, notification.ID, letter.NotificationID)
+		assert.Equal(t, notification.UserID, letter.UserID)
+		assert.Equal(t, "webhook indisponível", letter.LastError)
+		assert.Equal(t, ch.clock(), letter.DeadAt)
+		assert.Len(t, letter.Attempts, 4)
+		for i, attempt := range letter.Attempts {
+			assert.Equal(t, i+1, attempt.Attempt)
+		}
+	})
+
+	t.Run("reprocessar mensagem inexistente", func(t *testing.T) {
+		err := service.ReplayDeadLetter(ctx, "inexistente")
+		assert.ErrorIs(t, err, handler.ErrNotFound)
+		assert.Equal(t, 1, ch.Held(dlqQueue))
+	})
+
+	t.Run("reprocessar devolve para a fila de envio", func(t *testing.T) {
+		letters, err := service.ListDeadLetters(ctx)
+		assert.NoError(t, err)
+
+		for len(attempts) > 0 {
+			<-attempts
+		}
+		failing.Store(false)
+
+		assert.NoError(t, service.ReplayDeadLetter(ctx, letters[0].ID))
+		select {
+		case n := <-attempts:
+			assert.Equal(t, notification.ID, n.ID)
+		case <-time.After(time.Second):
+			t.Fatal("notificação não foi reprocessada")
+		}
+		assert.Equal(t, 0, ch.Held(dlqQueue))
+	})
+}
+
+func TestRabbitMQService_PurgeDeadLetters(t *testing.T) {
+	ch := newFakeChannel(time.Now())
+	service, err := newRabbitMQService(ch.dial, ch.clock, ConsumerConfig{})
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		ch.PublishWithContext(context.Background(), exchangeName, dlqQueue, false, false, amqp.Publishing{Body: []byte(`{}`)})
+	}
+
+	purged, err := service.PurgeDeadLetters(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, purged)
+	assert.Equal(t, 0, ch.Held(dlqQueue))
+}
+
+func TestRabbitMQService_Reconnect(t *testing.T) {
+	ch := newFakeChannel(time.Now())
+	service, err := newRabbitMQService(ch.dial, ch.clock, ConsumerConfig{})
+	assert.NoError(t, err)
+	defer service.Close()
+	service.reconnectDelay = 5 * time.Millisecond
+
+	var failures atomic.Int32
+	failures.Store(2)
+	dial := service.dial
+	service.dial = func() (amqpChannel, io.Closer, error) {
+		if failures.Add(-1) >= 0 {
+			return nil, nil, errors.New("connection refused")
+		}
+		return dial()
+	}
+
+	delivered := make(chan *entity.Notification, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go service.ConsumeNotifications(ctx, func(n *entity.Notification) error {
+		delivered <- n
+		return nil
+	})
+	assert.Eventually(t, func() bool { return ch.Consumers(queueName) == 1 }, time.Second, time.Millisecond)
+
+	ch.Kill()
+
+	err = service.PublishNotification(ctx, &entity.Notification{ID: uuid.New(), ScheduledFor: time.Now()})
+	assert.ErrorIs(t, err, ErrBrokerUnavailable)
+
+	assert.Eventually(t, func() bool { return ch.Consumers(queueName) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, 2, ch.dials)
+
+	notification := &entity.Notification{ID: uuid.New(), ScheduledFor: time.Now()}
+	assert.NoError(t, service.PublishNotification(ctx, notification))
+
+	select {
+	case n := <-delivered:
+		assert.Equal(t, notification.ID, n.ID)
+	case <-time.After(time.Second):
+		t.Fatal("notificação não foi entregue após a reconexão")
+	}
+}
+
+func TestRabbitMQService_PublisherConfirms(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(ch *fakeChannel)
+		expected error
+	}{
+		{
+			name:  "confirmada pelo broker",
+			setup: func(ch *fakeChannel) {},
+		},
+		{
+			name:     "rejeitada pelo broker",
+			setup:    func(ch *fakeChannel) { ch.nackPublishes = true },
+			expected: ErrPublishNacked,
+		},
+		{
+			name:     "sem confirmação no prazo",
+			setup:    func(ch *fakeChannel) { ch.dropConfirms = true },
+			expected: ErrConfirmTimeout,
+		},
+		{
+			name:     "devolvida por falta de fila",
+			setup:    func(ch *fakeChannel) { delete(ch.queues, queueName) },
+			expected: ErrMessageReturned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := newFakeChannel(time.Now())
+			service, err := newRabbitMQService(ch.dial, ch.clock, ConsumerConfig{})
+			assert.NoError(t, err)
+			defer service.Close()
+			service.confirmTimeout = 20 * time.Millisecond
+
+			ch.mu.Lock()
+			tt.setup(ch)
+			ch.mu.Unlock()
+
+			err = service.PublishNotification(context.Background(), &entity.Notification{ID: uuid.New(), ScheduledFor: time.Now()})
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, ch.Held(queueName))
+				return
+			}
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestRabbitMQService_PublishFailsPartWay(t *testing.T) {
+	ch := newFakeChannel(time.Now())
+	service, err := newRabbitMQService(ch.dial, ch.clock, ConsumerConfig{})
+	require.NoError(t, err)
+	defer service.Close()
+	service.confirmTimeout = 20 * time.Millisecond
+
+	frameErr := errors.New("falha ao enviar o frame")
+	ch.mu.Lock()
+	ch.publishErrs = []error{nil, frameErr, nil, nil}
+	ch.mu.Unlock()
+
+	publish := func() error {
+		return service.PublishNotification(context.Background(), &entity.Notification{ID: uuid.New(), ScheduledFor: time.Now()})
+	}
+
+	assert.NoError(t, publish())
+	assert.ErrorIs(t, publish(), frameErr)
+
+	// As publicações seguintes esperam a confirmação da própria delivery
+	// tag, que não avança com a publicação que falhou
+	assert.NoError(t, publish())
+	assert.NoError(t, publish())
+	assert.Equal(t, uint64(3), ch.publishTag)
+	assert.Equal(t, 3, ch.Held(queueName))
+}
+
+func TestRabbitMQService_ConcurrentConsumers(t *testing.T) {
+	ch := newFakeChannel(time.Now())
+	service, err := newRabbitMQService(ch.dial, time.Now, ConsumerConfig{Concurrency: 3, Prefetch: 6})
+	assert.NoError(t, err)
+	defer service.Close()
+	assert.Equal(t, 6, ch.prefetch)
+
+	var (
+		mu        sync.Mutex
+		inFlight  int
+		maxFlight int
+		processed sync.WaitGroup
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go service.ConsumeNotifications(ctx, func(n *entity.Notification) error {
+		defer processed.Done()
+		mu.Lock()
+		inFlight++
+		if inFlight > maxFlight {
+			maxFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return nil
+	})
+	assert.Eventually(t, func() bool { return ch.Consumers(queueName) == 1 }, time.Second, time.Millisecond)
+
+	processed.Add(9)
+	for i := 0; i < 9; i++ {
+		assert.NoError(t, service.PublishNotification(ctx, &entity.Notification{ID: uuid.New(), ScheduledFor: time.Now()}))
+	}
+	processed.Wait()
+
+	assert.Equal(t, 3, maxFlight)
+}
+
+func TestRabbitMQService_GracefulDrain(t *testing.T) {
+	ch := newFakeChannel(time.Now())
+	service, err := newRabbitMQService(ch.dial, time.Now, ConsumerConfig{Concurrency: 2})
+	assert.NoError(t, err)
+	defer service.Close()
+
+	started := make(chan struct{}, 4)
+	var completed atomic.Int32
+
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, service.PublishNotification(context.Background(), &entity.Notification{ID: uuid.New(), ScheduledFor: time.Now()}))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	consumed := make(chan error, 1)
+	go func() {
+		consumed <- service.ConsumeNotifications(ctx, func(n *entity.Notification) error {
+			started <- struct{}{}
+			time.Sleep(50 * time.Millisecond)
+			completed.Add(1)
+			return nil
+		})
+	}()
+
+	<-started
+	<-started
+	cancel()
+
+	select {
+	case err := <-consumed:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("consumidor não foi encerrado")
+	}
+
+	// As entregas em andamento terminam e são confirmadas; as demais voltam para a fila
+	assert.Equal(t, int32(2), completed.Load())
+	assert.Equal(t, 2, ch.acks)
+	assert.Equal(t, 2, ch.Held(queueName))
+	assert.Equal(t, 0, ch.Consumers(queueName))
+}
+
+func TestRabbitMQService_MigrateLegacyRetryQueue(t *testing.T) {
+	ch := newFakeChannel(time.Now())
+	_, err := ch.QueueDeclare(legacyRetryQueue, true, false, false, false, nil)
+	require.NoError(t, err)
+
+	for _, headers := range []amqp.Table{nil, {headerRetryCount: int32(1)}, {headerRetryCount: int32(2)}, {headerRetryCount: int32(5)}} {
+		require.NoError(t, ch.PublishWithContext(context.Background(), exchangeName, legacyRetryQueue, false, false, amqp.Publishing{Headers: headers, Body: []byte(`{}`)}))
+	}
+
+	service, err := newRabbitMQService(ch.dial, ch.clock, ConsumerConfig{})
+	require.NoError(t, err)
+	defer service.Close()
+
+	assert.Equal(t, 2, ch.Held("notifications.retry.30s"))
+	assert.Equal(t, 1, ch.Held("notifications.retry.2m"))
+	assert.Equal(t, 1, ch.Held("notifications.retry.8m"))
+	assert.Equal(t, 4, ch.acks)
+
+	ch.mu.Lock()
+	_, exists := ch.queues[legacyRetryQueue]
+	ch.mu.Unlock()
+	assert.False(t, exists, "fila antiga deve ser removida após a migração")
+}
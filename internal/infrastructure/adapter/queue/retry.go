@@ -0,0 +1,136 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"weather-notification/internal/domain/entity"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	headerRetryCount = "x-retry-count"
+	headerLastError  = "x-last-error"
+	headerAttempts   = "x-attempts"
+	headerDeadAt     = "x-dead-at"
+)
+
+// legacyRetryQueue é a fila única de retentativas das versões anteriores,
+// substituída por retryTiers. Ela é drenada e removida ao conectar.
+const legacyRetryQueue = "notifications.retry"
+
+// retryTiers define o atraso de cada nova tentativa, crescendo
+// exponencialmente. Esgotadas as tentativas a mensagem vai para a DLQ.
+var retryTiers = []delayBucket{
+	{queue: "notifications.retry.30s", delay: 30 * time.Second},
+	{queue: "notifications.retry.2m", delay: 2 * time.Minute},
+	{queue: "notifications.retry.8m", delay: 8 * time.Minute},
+}
+
+func retryCount(headers amqp.Table) int {
+	switch v := headers[headerRetryCount].(type) {
+	case int:
+		return v
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+// withFailedAttempt copia os headers acrescentando a falha à pilha de
+// tentativas, preservando o histórico das anteriores.
+func withFailedAttempt(headers amqp.Table, cause error, at time.Time) amqp.Table {
+	attempt := retryCount(headers) + 1
+
+	result := amqp.Table{}
+	for k, v := range headers {
+		if k == "x-death" {
+			continue
+		}
+		result[k] = v
+	}
+
+	attempts, _ := headers[headerAttempts].([]interface{})
+	stack := make([]interface{}, 0, len(attempts)+1)
+	stack = append(stack, attempts...)
+	stack = append(stack, amqp.Table{
+		"attempt":   int32(attempt),
+		"error":     cause.Error(),
+		"failed_at": at,
+	})
+
+	result[headerRetryCount] = int32(attempt)
+	result[headerLastError] = cause.Error()
+	result[headerAttempts] = stack
+	return result
+}
+
+func attemptsFromHeaders(headers amqp.Table) []entity.DeliveryAttempt {
+	stack, _ := headers[headerAttempts].([]interface{})
+	attempts := make([]entity.DeliveryAttempt, 0, len(stack))
+	for _, item := range stack {
+		table, ok := item.(amqp.Table)
+		if !ok {
+			continue
+		}
+		attempt := entity.DeliveryAttempt{
+			Attempt: retryCount(amqp.Table{headerRetryCount: table["attempt"]}),
+		}
+		attempt.Error, _ = table["error"].(string)
+		attempt.FailedAt, _ = table["failed_at"].(time.Time)
+		attempts = append(attempts, attempt)
+	}
+	return attempts
+}
+
+// migrateLegacyRetryQueue move as mensagens deixadas na antiga fila
+// notifications.retry para a fila de retentativa correspondente ao número
+// de falhas já registrado e remove a fila quando ela fica vazia. Cada
+// mensagem só é confirmada depois que o broker confirma a nova publicação.
+func (s *RabbitMQService) migrateLegacyRetryQueue(ctx context.Context, ch amqpChannel) error {
+	if _, err := ch.QueueDeclare(legacyRetryQueue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("erro ao declarar fila %s: %w", legacyRetryQueue, err)
+	}
+
+	moved := 0
+	for {
+		msg, ok, err := ch.Get(legacyRetryQueue, false)
+		if err != nil {
+			return fmt.Errorf("erro ao ler fila %s: %w", legacyRetryQueue, err)
+		}
+		if !ok {
+			break
+		}
+
+		tier := retryTiers[min(max(retryCount(msg.Headers)-1, 0), len(retryTiers)-1)]
+		err = s.publishMessage(ctx, tier.queue, amqp.Publishing{
+			Headers:      msg.Headers,
+			DeliveryMode: amqp.Persistent,
+			Timestamp:    s.now(),
+			ContentType:  "application/json",
+			Body:         msg.Body,
+		})
+		if err != nil {
+			msg.Nack(false, true)
+			return fmt.Errorf("erro ao mover mensagem de %s: %w", legacyRetryQueue, err)
+		}
+		msg.Ack(false)
+		moved++
+	}
+
+	if moved > 0 {
+		log.Printf("%d mensagens movidas de %s para as filas de retentativa", moved, legacyRetryQueue)
+	}
+
+	if _, err := ch.QueueDelete(legacyRetryQueue, false, true, false); err != nil {
+		return fmt.Errorf("erro ao remover fila %s: %w", legacyRetryQueue, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+	"weather-notification/internal/domain/service"
+)
+
+type AlertWorker struct {
+	ctx      context.Context
+	service  *service.AlertService
+	interval time.Duration
+}
+
+func NewAlertWorker(
+	ctx context.Context,
+	service *service.AlertService,
+	interval time.Duration,
+) *AlertWorker {
+	return &AlertWorker{
+		ctx:      ctx,
+		service:  service,
+		interval: interval,
+	}
+}
+
+func (w *AlertWorker) Start() error {
+	log.Printf("Iniciando worker de alertas meteorológicos...")
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.evaluate()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			log.Printf("Finalizando worker de alertas meteorológicos...")
+			return nil
+		case <-ticker.C:
+			w.evaluate()
+		}
+	}
+}
+
+func (w *AlertWorker) evaluate() {
+	if err := w.service.EvaluateAlerts(w.ctx); err != nil {
+		log.Printf("Erro ao avaliar alertas meteorológicos: %v", err)
+	}
+}
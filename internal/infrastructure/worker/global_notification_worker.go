@@ -2,23 +2,28 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"log"
 	"time"
+	handler "weather-notification/internal/domain/error_handler"
 	"weather-notification/internal/domain/service"
 )
 
 type GlobalNotificationWorker struct {
 	ctx     context.Context
 	service *service.GlobalNotificationService
+	leader  *service.LeaderElectionService
 }
 
 func NewGlobalNotificationWorker(
 	ctx context.Context,
 	service *service.GlobalNotificationService,
+	leader *service.LeaderElectionService,
 ) *GlobalNotificationWorker {
 	return &GlobalNotificationWorker{
 		ctx:     ctx,
 		service: service,
+		leader:  leader,
 	}
 }
 
@@ -27,15 +32,38 @@ func (w *GlobalNotificationWorker) Start() error {
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
 
+	// Recupera execuções perdidas enquanto o serviço esteve fora do ar
+	w.process()
+
 	for {
 		select {
 		case <-w.ctx.Done():
 			log.Printf("Finalizando worker de notificações globais...")
 			return nil
 		case <-ticker.C:
-			if err := w.service.ProcessActiveNotifications(w.ctx); err != nil {
-				log.Printf("Erro ao processar notificações globais: %v", err)
-			}
+			w.process()
 		}
 	}
 }
+
+func (w *GlobalNotificationWorker) process() {
+	// Renova a concessão antes de processar para que apenas o líder
+	// atual dispare as notificações globais
+	isLeader, err := w.leader.Heartbeat(w.ctx)
+	if err != nil {
+		log.Printf("Erro ao verificar liderança do agendador: %v", err)
+		return
+	}
+	if !isLeader {
+		return
+	}
+
+	err = w.service.ProcessActiveNotifications(w.ctx, w.leader)
+	if errors.Is(err, handler.ErrLeaseLost) {
+		log.Printf("Liderança do agendador perdida durante o processamento das notificações globais")
+		return
+	}
+	if err != nil {
+		log.Printf("Erro ao processar notificações globais: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+	"weather-notification/internal/domain/service"
+)
+
+type LeaderElectionWorker struct {
+	ctx     context.Context
+	service *service.LeaderElectionService
+}
+
+func NewLeaderElectionWorker(
+	ctx context.Context,
+	service *service.LeaderElectionService,
+) *LeaderElectionWorker {
+	return &LeaderElectionWorker{
+		ctx:     ctx,
+		service: service,
+	}
+}
+
+func (w *LeaderElectionWorker) Start() error {
+	log.Printf("Iniciando eleição de líder do agendador...")
+	ticker := time.NewTicker(w.service.TTL() / 3)
+	defer ticker.Stop()
+
+	w.heartbeat()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			log.Printf("Finalizando eleição de líder do agendador...")
+			return nil
+		case <-ticker.C:
+			w.heartbeat()
+		}
+	}
+}
+
+func (w *LeaderElectionWorker) heartbeat() {
+	wasLeader := w.service.IsLeader()
+
+	isLeader, err := w.service.Heartbeat(w.ctx)
+	if err != nil {
+		log.Printf("Erro ao renovar liderança do agendador: %v", err)
+	}
+
+	if isLeader != wasLeader {
+		if isLeader {
+			log.Printf("Instância assumiu a liderança do agendador global")
+		} else {
+			log.Printf("Instância perdeu a liderança do agendador global")
+		}
+	}
+}
@@ -2,12 +2,12 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"log"
-	"os"
 	"time"
 	"weather-notification/internal/domain/entity"
+	handler "weather-notification/internal/domain/error_handler"
 	"weather-notification/internal/domain/service"
-	"weather-notification/internal/infrastructure/adapter/notifier"
 )
 
 type NotificationWorker struct {
@@ -15,6 +15,7 @@ type NotificationWorker struct {
 	notificationSvc *service.NotificationService
 	weatherSvc      *service.WeatherService
 	queueService    service.QueueService
+	expiration      time.Duration
 }
 
 func NewNotificationWorker(
@@ -22,12 +23,14 @@ func NewNotificationWorker(
 	notificationSvc *service.NotificationService,
 	weatherSvc *service.WeatherService,
 	queueService service.QueueService,
+	expiration time.Duration,
 ) *NotificationWorker {
 	return &NotificationWorker{
 		ctx:             ctx,
 		notificationSvc: notificationSvc,
 		weatherSvc:      weatherSvc,
 		queueService:    queueService,
+		expiration:      expiration,
 	}
 }
 
@@ -39,27 +42,48 @@ func (w *NotificationWorker) Start() error {
 func (w *NotificationWorker) processNotification(notification *entity.Notification) error {
 	log.Printf("Recebendo notificação para processamento: %s", notification.ID)
 
+	// Uma entrega iniciada é concluída mesmo durante o desligamento
+	ctx := context.WithoutCancel(w.ctx)
+
 	if time.Now().Before(notification.ScheduledFor) {
 		log.Printf("Notificação %s agendada para futuro: %v", notification.ID, notification.ScheduledFor)
 		return nil
 	}
 
-	forecast, err := w.weatherSvc.GetForecast(w.ctx, notification.LocationID)
+	current, err := w.notificationSvc.StartProcessing(ctx, notification, w.expiration)
 	if err != nil {
-		log.Printf("Erro ao atualizar previsão: %v", err)
+		switch {
+		case errors.Is(err, handler.ErrInvalidStatusTransition),
+			errors.Is(err, handler.ErrNotificationRescheduled),
+			errors.Is(err, handler.ErrNotificationExpired),
+			errors.Is(err, handler.ErrNotificationInProgress),
+			errors.Is(err, handler.ErrNotFound):
+			log.Printf("Descartando notificação %s: %v", notification.ID, err)
+			return nil
+		}
+		log.Printf("Erro ao iniciar processamento: %v", err)
 		return err
 	}
+	notification = current
 
-	notification.Content = *forecast
+	if err := w.refreshForecasts(ctx, notification); err != nil {
+		log.Printf("Erro ao atualizar previsão: %v", err)
+		w.fail(ctx, notification, err)
+		return err
+	}
 
-	err = w.sendNotification(notification)
+	err = w.sendNotification(ctx, notification)
 	if err != nil {
 		log.Printf("Erro ao enviar notificação: %v", err)
+		w.fail(ctx, notification, err)
+		if errors.Is(err, handler.ErrPermanentDelivery) {
+			// Nenhuma retentativa teria outro resultado
+			return nil
+		}
 		return err
 	}
 
-	notification.MarkAsSent()
-	err = w.notificationSvc.UpdateStatus(w.ctx, notification)
+	err = w.notificationSvc.CompleteProcessing(ctx, notification)
 	if err != nil {
 		log.Printf("Erro ao atualizar status: %v", err)
 		return err
@@ -68,14 +92,46 @@ func (w *NotificationWorker) processNotification(notification *entity.Notificati
 	return nil
 }
 
-func (w *NotificationWorker) sendNotification(notification *entity.Notification) error {
-	notifier := notifier.NewWebNotifier(os.Getenv("WEBHOOK_URL"))
+// refreshForecasts atualiza a previsão da localização da notificação e das
+// localizações adicionais de uma notificação combinada, mantendo a
+// quantidade de dias agendada.
+func (w *NotificationWorker) refreshForecasts(ctx context.Context, notification *entity.Notification) error {
+	days := notification.Content.Days()
+	if days == 0 {
+		days = entity.DefaultForecastDays
+	}
 
-	err := notifier.Send(w.ctx, notification)
+	forecast, err := w.weatherSvc.GetForecastForDays(ctx, notification.LocationID, days)
 	if err != nil {
-		log.Printf("Erro ao enviar notificação web: %v", err)
 		return err
 	}
+	notification.Content = *forecast
+
+	for i, location := range notification.AdditionalLocations {
+		forecast, err := w.weatherSvc.GetForecastForDays(ctx, location.LocationID, days)
+		if err != nil {
+			return err
+		}
+		notification.AdditionalLocations[i].Content = *forecast
+	}
 
 	return nil
 }
+
+func (w *NotificationWorker) fail(ctx context.Context, notification *entity.Notification, cause error) {
+	if err := w.notificationSvc.FailProcessing(ctx, notification, cause); err != nil {
+		log.Printf("Erro ao registrar falha da notificação %s: %v", notification.ID, err)
+	}
+}
+
+func (w *NotificationWorker) sendNotification(ctx context.Context, notification *entity.Notification) error {
+	results, err := w.notificationSvc.Deliver(ctx, notification)
+
+	for _, result := range results {
+		if result.Status == entity.StatusFailed {
+			log.Printf("Erro ao enviar notificação %s pelo canal %s: %s", notification.ID, result.Channel, result.Error)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+	"weather-notification/internal/domain/service"
+)
+
+type OutboxWorker struct {
+	ctx      context.Context
+	service  *service.OutboxRelayService
+	interval time.Duration
+}
+
+func NewOutboxWorker(
+	ctx context.Context,
+	service *service.OutboxRelayService,
+	interval time.Duration,
+) *OutboxWorker {
+	return &OutboxWorker{
+		ctx:      ctx,
+		service:  service,
+		interval: interval,
+	}
+}
+
+func (w *OutboxWorker) Start() error {
+	log.Printf("Iniciando relay do outbox...")
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.relay()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			log.Printf("Finalizando relay do outbox...")
+			return nil
+		case <-ticker.C:
+			w.relay()
+		}
+	}
+}
+
+func (w *OutboxWorker) relay() {
+	if _, err := w.service.RelayPending(w.ctx); err != nil {
+		log.Printf("Erro ao publicar mensagens do outbox: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+	"weather-notification/internal/domain/service"
+)
+
+type SubscriptionWorker struct {
+	ctx     context.Context
+	service *service.SubscriptionService
+}
+
+func NewSubscriptionWorker(
+	ctx context.Context,
+	service *service.SubscriptionService,
+) *SubscriptionWorker {
+	return &SubscriptionWorker{
+		ctx:     ctx,
+		service: service,
+	}
+}
+
+func (w *SubscriptionWorker) Start() error {
+	log.Printf("Iniciando worker de inscrições recorrentes...")
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			log.Printf("Finalizando worker de inscrições recorrentes...")
+			return nil
+		case <-ticker.C:
+			if err := w.service.ProcessDueSubscriptions(w.ctx); err != nil {
+				log.Printf("Erro ao processar inscrições recorrentes: %v", err)
+			}
+		}
+	}
+}
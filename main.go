@@ -7,19 +7,25 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
+	_ "time/tzdata"
 	"weather-notification/internal/domain/service"
 	middleware "weather-notification/internal/middlewares"
 
 	_ "weather-notification/docs"
 	"weather-notification/internal/infrastructure/adapter/api/handler"
+	"weather-notification/internal/infrastructure/adapter/cache"
 	"weather-notification/internal/infrastructure/adapter/cptec"
+	"weather-notification/internal/infrastructure/adapter/notifier"
 	postgres "weather-notification/internal/infrastructure/adapter/persistence/postgres"
 	"weather-notification/internal/infrastructure/adapter/queue"
 	"weather-notification/internal/infrastructure/worker"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 	swaggerFiles "github.com/swaggo/files"
@@ -55,60 +61,225 @@ func main() {
 	locationRepo := postgres.NewLocationRepository(db)
 	notificationRepo := postgres.NewNotificationRepository(db)
 	globalNotificationRepo := postgres.NewGlobalNotificationRepository(db)
+	deliveryRepo := postgres.NewDeliveryRepository(db)
+	idempotencyRepo := postgres.NewIdempotencyRepository(db, postgres.DefaultClaimLease)
+	alertRepo := postgres.NewAlertRuleRepository(db)
+	subscriptionRepo := postgres.NewSubscriptionRepository(db)
+	leaseRepo := postgres.NewLeaseRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	userLocationRepo := postgres.NewUserLocationRepository(db)
 
 	// ADAPTERS
 	cptecClient := cptec.NewClient()
 
-	queueService, err := queue.NewRabbitMQService(os.Getenv("RABBITMQ_URL"))
+	cacheTTL, err := time.ParseDuration(os.Getenv("FORECAST_CACHE_TTL"))
 	if err != nil {
-		log.Fatalf("Erro ao conectar ao RabbitMQ: %v", err)
+		cacheTTL = time.Hour
+	}
+
+	var forecastCache service.ForecastCache
+	switch os.Getenv("FORECAST_CACHE") {
+	case "postgres":
+		forecastCache = postgres.NewForecastCacheRepository(db, cacheTTL)
+	case "none":
+		forecastCache = nil
+	default:
+		forecastCache = cache.NewMemoryForecastCache(cacheTTL)
+	}
+
+	notifiers := service.NewNotifierRegistry(
+		notifier.NewWebNotifier(os.Getenv("WEBHOOK_URL")),
+	)
+	if os.Getenv("SLACK_WEBHOOK_URL") != "" {
+		notifiers.Register(notifier.NewSlackNotifier(os.Getenv("SLACK_WEBHOOK_URL")))
+	}
+	if os.Getenv("SMTP_HOST") != "" {
+		notifiers.Register(notifier.NewEmailNotifier(notifier.EmailConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+			Insecure: os.Getenv("SMTP_INSECURE") == "true",
+		}))
+	}
+	if os.Getenv("SMS_GATEWAY_URL") != "" {
+		notifiers.Register(notifier.NewSMSNotifier(
+			os.Getenv("SMS_GATEWAY_URL"),
+			os.Getenv("SMS_GATEWAY_TOKEN"),
+		))
+	}
+
+	rateLimits, err := service.ParseRateLimits(os.Getenv("DELIVERY_RATE_LIMITS"))
+	if err != nil {
+		log.Fatalf("Erro ao ler DELIVERY_RATE_LIMITS: %v", err)
+	}
+	rateLimiter := service.NewDeliveryRateLimiter(rateLimits)
+
+	consumerConfig := queue.ConsumerConfig{}
+	if concurrency, err := strconv.Atoi(os.Getenv("QUEUE_CONCURRENCY")); err == nil {
+		consumerConfig.Concurrency = concurrency
+	}
+	if prefetch, err := strconv.Atoi(os.Getenv("QUEUE_PREFETCH")); err == nil {
+		consumerConfig.Prefetch = prefetch
+	}
+
+	var queueService service.QueueService
+	var deadLetterQueue service.DeadLetterQueue
+	switch os.Getenv("QUEUE_BACKEND") {
+	case "memory":
+		memoryQueue := queue.NewMemoryQueueService(consumerConfig.Concurrency)
+		queueService, deadLetterQueue = memoryQueue, memoryQueue
+
+		requeued, err := service.RequeueNotifications(context.Background(), notificationRepo, memoryQueue)
+		if err != nil {
+			log.Printf("Erro ao republicar notificações: %v", err)
+		}
+		log.Printf("%d notificações republicadas na fila em memória", requeued)
+	default:
+		rabbitQueue, err := queue.NewRabbitMQService(os.Getenv("RABBITMQ_URL"), consumerConfig)
+		if err != nil {
+			log.Fatalf("Erro ao conectar ao RabbitMQ: %v", err)
+		}
+		queueService, deadLetterQueue = rabbitQueue, rabbitQueue
 	}
 	defer queueService.Close()
 
 	// SERVICES
-	weatherService := service.NewWeatherService(cptecClient, locationRepo)
+	weatherService := service.NewWeatherService(cptecClient, locationRepo, forecastCache)
 	notificationService := service.NewNotificationService(
 		notificationRepo,
 		userRepo,
+		userLocationRepo,
+		deliveryRepo,
+		idempotencyRepo,
 		weatherService,
-		queueService,
+		notifiers,
+		rateLimiter,
 	)
+	gracePeriod, err := time.ParseDuration(os.Getenv("GLOBAL_NOTIFICATION_GRACE_PERIOD"))
+	if err != nil {
+		gracePeriod = 30 * time.Minute
+	}
 	globalNotificationService := service.NewGlobalNotificationService(
 		globalNotificationRepo,
 		userRepo,
-		queueService,
+		userLocationRepo,
 		weatherService,
 		notificationRepo,
+		gracePeriod,
 	)
 	userService := service.NewUserService(userRepo)
+	userLocationService := service.NewUserLocationService(userRepo, userLocationRepo)
+	alertService := service.NewAlertService(
+		alertRepo,
+		userRepo,
+		notificationRepo,
+		weatherService,
+	)
+	subscriptionService := service.NewSubscriptionService(
+		subscriptionRepo,
+		userRepo,
+		notificationRepo,
+		weatherService,
+	)
+	outboxService := service.NewOutboxRelayService(outboxRepo, queueService, 100)
+	deadLetterService := service.NewDeadLetterService(deadLetterQueue)
+
+	instanceID := os.Getenv("INSTANCE_ID")
+	if instanceID == "" {
+		hostname, _ := os.Hostname()
+		instanceID = hostname + "-" + uuid.NewString()[:8]
+	}
+	leaseTTL, err := time.ParseDuration(os.Getenv("LEADER_LEASE_TTL"))
+	if err != nil {
+		leaseTTL = 30 * time.Second
+	}
+	leaderService := service.NewLeaderElectionService(
+		leaseRepo,
+		service.GlobalSchedulerLease,
+		instanceID,
+		leaseTTL,
+	)
 
 	// WORKERS
+	notificationExpiration, err := time.ParseDuration(os.Getenv("NOTIFICATION_EXPIRATION"))
+	if err != nil {
+		notificationExpiration = 24 * time.Hour
+	}
+	consumerCtx, stopConsumer := context.WithCancel(context.Background())
 	notificationWorker := worker.NewNotificationWorker(
-		context.Background(),
+		consumerCtx,
 		notificationService,
 		weatherService,
 		queueService,
+		notificationExpiration,
 	)
-	globalWorker := worker.NewGlobalNotificationWorker(context.Background(), globalNotificationService)
+	leaderWorker := worker.NewLeaderElectionWorker(context.Background(), leaderService)
+	globalWorker := worker.NewGlobalNotificationWorker(context.Background(), globalNotificationService, leaderService)
+
+	alertInterval, err := time.ParseDuration(os.Getenv("ALERT_CHECK_INTERVAL"))
+	if err != nil {
+		alertInterval = 30 * time.Minute
+	}
+	alertWorker := worker.NewAlertWorker(context.Background(), alertService, alertInterval)
+	subscriptionWorker := worker.NewSubscriptionWorker(context.Background(), subscriptionService)
+
+	outboxInterval, err := time.ParseDuration(os.Getenv("OUTBOX_RELAY_INTERVAL"))
+	if err != nil {
+		outboxInterval = 5 * time.Second
+	}
+	outboxWorker := worker.NewOutboxWorker(context.Background(), outboxService, outboxInterval)
 
+	consumerDone := make(chan struct{})
 	go func() {
-		if err := notificationWorker.Start(); err != nil {
+		defer close(consumerDone)
+		if err := notificationWorker.Start(); err != nil && err != context.Canceled {
 			log.Printf("Erro no worker: %v", err)
 		}
 	}()
 
+	go func() {
+		if err := outboxWorker.Start(); err != nil {
+			log.Printf("Erro no relay do outbox: %v", err)
+		}
+	}()
+
+	go func() {
+		if err := leaderWorker.Start(); err != nil {
+			log.Printf("Erro na eleição de líder: %v", err)
+		}
+	}()
+
 	go func() {
 		if err := globalWorker.Start(); err != nil {
 			log.Printf("Erro no worker de notificações globais: %v", err)
 		}
 	}()
 
+	go func() {
+		if err := alertWorker.Start(); err != nil {
+			log.Printf("Erro no worker de alertas: %v", err)
+		}
+	}()
+
+	go func() {
+		if err := subscriptionWorker.Start(); err != nil {
+			log.Printf("Erro no worker de inscrições recorrentes: %v", err)
+		}
+	}()
+
 	// API
 	forecastHandler := handler.NewWeatherHandler(weatherService)
 	notificationHandler := handler.NewNotificationHandler(notificationService)
 	globalNotificationHandler := handler.NewGlobalNotificationHandler(globalNotificationService)
 	userHandler := handler.NewUserHandler(userService, weatherService)
+	userLocationHandler := handler.NewUserLocationHandler(userLocationService)
 	webhookHandler := handler.NewWebhookHandler()
+	alertHandler := handler.NewAlertHandler(alertService)
+	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionService)
+	schedulerHandler := handler.NewSchedulerHandler(leaderService)
+	deadLetterHandler := handler.NewDeadLetterHandler(deadLetterService)
 
 	gin.SetMode(os.Getenv("GIN_MODE"))
 	router := gin.Default()
@@ -124,7 +295,12 @@ func main() {
 		notificationHandler.SetupRoutes(api)
 		globalNotificationHandler.SetupRoutes(api)
 		userHandler.SetupRoutes(api)
+		userLocationHandler.SetupRoutes(api)
 		webhookHandler.SetupRoutes(api)
+		alertHandler.SetupRoutes(api)
+		subscriptionHandler.SetupRoutes(api)
+		schedulerHandler.SetupRoutes(api)
+		deadLetterHandler.SetupRoutes(api)
 	}
 
 	router.GET("/health", func(c *gin.Context) {
@@ -147,7 +323,7 @@ func main() {
 
 	// SHUTDOWN
 	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 	log.Println("Desligando servidor...")
 
@@ -158,5 +334,21 @@ func main() {
 		log.Fatal("Server forçado a fechar:", err)
 	}
 
+	if err := leaderService.Release(ctx); err != nil {
+		log.Printf("Erro ao liberar liderança do agendador: %v", err)
+	}
+
+	drainTimeout, err := time.ParseDuration(os.Getenv("QUEUE_DRAIN_TIMEOUT"))
+	if err != nil {
+		drainTimeout = 30 * time.Second
+	}
+	stopConsumer()
+	select {
+	case <-consumerDone:
+		log.Println("Entregas em andamento concluídas")
+	case <-time.After(drainTimeout):
+		log.Println("Tempo esgotado aguardando entregas em andamento")
+	}
+
 	log.Println("Servidor encerrado")
 }
@@ -1,41 +1,218 @@
 -- uuid-ossp é uma extensão do PostgreSQL que permite a geração de UUIDs
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
+-- pg_trgm e unaccent permitem a busca de cidades tolerante a erros de digitação e acentos
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent não é IMMUTABLE e por isso não pode ser usada diretamente em índices
+CREATE OR REPLACE FUNCTION f_unaccent(text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+-- Todos os horários são armazenados em UTC; a conversão para o fuso do usuário é feita na aplicação
+DO $$
+BEGIN
+    EXECUTE format('ALTER DATABASE %I SET timezone TO ''UTC''', current_database());
+END
+$$;
+SET timezone TO 'UTC';
+
 CREATE TABLE locations (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     cptec_id INTEGER UNIQUE NOT NULL,
     name VARCHAR(255) NOT NULL,
     state VARCHAR(2) NOT NULL,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    timezone VARCHAR(64) NOT NULL DEFAULT 'America/Sao_Paulo',
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE INDEX idx_locations_coordinates ON locations(latitude, longitude) WHERE latitude IS NOT NULL;
+CREATE INDEX idx_locations_name_trgm ON locations USING gin (f_unaccent(lower(name)) gin_trgm_ops);
+
 CREATE TABLE users (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     location_id UUID NOT NULL REFERENCES locations(id),
     name VARCHAR(255) NOT NULL,
     email VARCHAR(255) UNIQUE NOT NULL,
     opt_out BOOLEAN DEFAULT FALSE,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    channels JSONB NOT NULL DEFAULT '[]',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'America/Sao_Paulo',
+    notification_mode VARCHAR(20) NOT NULL DEFAULT 'SEPARADA',
+    forecast_days INTEGER NOT NULL DEFAULT 4 CHECK (forecast_days BETWEEN 1 AND 11),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE INDEX idx_users_created ON users(created_at, id);
+
 CREATE TABLE global_notifications (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     time_of_day TIME NOT NULL,              
     frequency VARCHAR(50) NOT NULL,           
     active BOOLEAN DEFAULT TRUE,             
     last_execution TIMESTAMP WITH TIME ZONE,    
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
 CREATE TABLE notifications (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    user_id UUID NOT NULL REFERENCES users(id),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
     location_id UUID NOT NULL REFERENCES locations(id),
     content JSONB NOT NULL,
+    location_label VARCHAR(100),
+    additional_locations JSONB NOT NULL DEFAULT '[]',
+    message TEXT,
+    status VARCHAR(50) NOT NULL,
+    scheduled_for TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    last_http_status INTEGER,
+    last_attempt_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_user_created ON notifications(user_id, created_at, id);
+CREATE INDEX idx_notifications_user_scheduled ON notifications(user_id, scheduled_for, id);
+
+CREATE TABLE forecast_cache (
+    cptec_id INTEGER NOT NULL,
+    horizon VARCHAR(20) NOT NULL DEFAULT '4_DIAS',
+    content JSONB NOT NULL,
+    issued_at DATE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (cptec_id, horizon)
+);
+
+
+CREATE TABLE notification_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL,
+    address VARCHAR(255),
     status VARCHAR(50) NOT NULL,
-    scheduled_for TIMESTAMP NOT NULL,
-    sent_at TIMESTAMP,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
\ No newline at end of file
+    error TEXT,
+    http_status INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notification_deliveries_notification ON notification_deliveries(notification_id);
+
+CREATE TABLE alert_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    location_id UUID NOT NULL REFERENCES locations(id),
+    metric VARCHAR(50) NOT NULL,
+    operator VARCHAR(20),
+    threshold NUMERIC(6, 2) NOT NULL DEFAULT 0,
+    conditions JSONB NOT NULL DEFAULT '[]',
+    active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE alert_triggers (
+    rule_id UUID NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+    forecast_date DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (rule_id, forecast_date)
+);
+
+CREATE TABLE subscriptions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    location_id UUID NOT NULL REFERENCES locations(id),
+    cron_expression VARCHAR(100) NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'America/Sao_Paulo',
+    active BOOLEAN DEFAULT TRUE,
+    next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_subscriptions_next_run ON subscriptions(next_run_at) WHERE active = true;
+
+CREATE TABLE global_notification_executions (
+    global_notification_id UUID NOT NULL REFERENCES global_notifications(id) ON DELETE CASCADE,
+    timezone VARCHAR(64) NOT NULL,
+    last_execution TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (global_notification_id, timezone)
+);
+
+CREATE TABLE global_notification_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    global_notification_id UUID NOT NULL REFERENCES global_notifications(id) ON DELETE CASCADE,
+    timezone VARCHAR(64) NOT NULL,
+    scheduled_for TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_global_notification_runs_notification ON global_notification_runs(global_notification_id, scheduled_for);
+
+CREATE TABLE scheduler_leases (
+    name VARCHAR(100) PRIMARY KEY,
+    holder_id VARCHAR(255) NOT NULL,
+    acquired_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    renewed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE outbox (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    aggregate_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    available_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_outbox_pending ON outbox(available_at) WHERE dispatched_at IS NULL;
+
+CREATE TABLE delivery_idempotency (
+    key VARCHAR(100) PRIMARY KEY,
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    sent_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE notification_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    error TEXT,
+    details TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notification_events_notification ON notification_events(notification_id, created_at);
+
+CREATE TABLE user_locations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    location_id UUID NOT NULL REFERENCES locations(id),
+    label VARCHAR(100) NOT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    notify BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, location_id)
+);
+
+CREATE UNIQUE INDEX idx_user_locations_primary ON user_locations(user_id) WHERE is_primary;